@@ -1,13 +1,24 @@
 package main
 
 import (
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd"
 )
 
 func main() {
-	command := cmd.NewCfsCommand()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		// Restore the default behaviour after the first signal, so that a
+		// second one kills cfs even if it is stuck cleaning up.
+		<-ctx.Done()
+		stop()
+	}()
+
+	command := cmd.NewCfsCommand(ctx)
 
 	if err := command.Execute(); err != nil {
 		os.Exit(1)
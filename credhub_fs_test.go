@@ -6,14 +6,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/mdelillo/credhub-fs/test/helpers"
+	"github.com/mdelillo/credhub-fs/test/server"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
@@ -27,11 +31,25 @@ var _ = Describe("CredhubFs", func() {
 		credhubListenAddr   string
 		fakeUAA             string
 		uaaListenAddr       string
+		uaaSession          *gexec.Session
+		homeDir             string
+		tlsDir              string
+		ca                  *server.CA
+		caPath              string
+		certPath            string
+		keyPath             string
 		clientID            string
 		clientSecret        string
+		otherClientID       string
+		otherClientSecret   string
+		username            string
+		password            string
 		jwtSigningKey       *rsa.PrivateKey
 		cfs                 func(args ...string) *gexec.Session
+		cfsAs               func(clientID, clientSecret string, args ...string) *gexec.Session
+		cfsWithEnv          func(env []string, args ...string) *gexec.Session
 		setValueInCredhub   func(name, value string)
+		setInCredhub        func(name, credentialType, valueJSON string)
 		findByPathInCredHub func(path string) []string
 	)
 
@@ -45,6 +63,17 @@ var _ = Describe("CredhubFs", func() {
 
 		fakeUAA, err = gexec.Build(filepath.Join("github.com", "mdelillo", "credhub-fs", "test", "fake-uaa"))
 		Expect(err).NotTo(HaveOccurred())
+
+		tlsDir, err = ioutil.TempDir("", "cfs-tls")
+		Expect(err).NotTo(HaveOccurred())
+
+		ca, err = server.NewCA()
+		Expect(err).NotTo(HaveOccurred())
+		caPath = filepath.Join(tlsDir, "ca.pem")
+		Expect(ioutil.WriteFile(caPath, []byte(ca.CertificatePEM), 0600)).To(Succeed())
+
+		certPath, keyPath, err = ca.WriteCertificate(tlsDir, "127.0.0.1")
+		Expect(err).NotTo(HaveOccurred())
 	})
 
 	BeforeEach(func() {
@@ -54,43 +83,66 @@ var _ = Describe("CredhubFs", func() {
 
 		credhubListenAddr = helpers.GetFreeAddr()
 		uaaListenAddr = helpers.GetFreeAddr()
+		clientID = helpers.RandomString()
+		clientSecret = helpers.RandomString()
+		otherClientID = helpers.RandomString()
+		otherClientSecret = helpers.RandomString()
+		username = helpers.RandomString()
+		password = helpers.RandomString()
 
 		cmd := exec.Command(
 			fakeCredhub,
 			"--listen-addr", credhubListenAddr,
-			"--cert-path", filepath.Join("test", "fixtures", "127.0.0.1-cert.pem"),
-			"--key-path", filepath.Join("test", "fixtures", "127.0.0.1-key.pem"),
+			"--cert-path", certPath,
+			"--key-path", keyPath,
+			"--client-ca-path", caPath,
 			"--auth-server-addr", "https://"+uaaListenAddr,
 			"--jwt-verification-key", helpers.PublicKeyToPEM(&jwtSigningKey.PublicKey),
+			"--enforce-permissions",
+			"--admin-client", clientID,
 		)
 		_, err = gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(helpers.WaitForServerToBeAvailable(credhubListenAddr, 5*time.Second)).To(Succeed())
 
-		clientID = helpers.RandomString()
-		clientSecret = helpers.RandomString()
 		cmd = exec.Command(
 			fakeUAA,
 			"--listen-addr", uaaListenAddr,
-			"--cert-path", filepath.Join("test", "fixtures", "127.0.0.1-cert.pem"),
-			"--key-path", filepath.Join("test", "fixtures", "127.0.0.1-key.pem"),
+			"--cert-path", certPath,
+			"--key-path", keyPath,
 			"--jwt-signing-key", helpers.PrivateKeyToPEM(jwtSigningKey),
 			"--client", clientID+":"+clientSecret,
+			"--client", otherClientID+":"+otherClientSecret,
+			"--user", username+":"+password,
 		)
-		_, err = gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+		uaaSession, err = gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(helpers.WaitForServerToBeAvailable(uaaListenAddr, 5*time.Second)).To(Succeed())
 
-		cfs = func(args ...string) *gexec.Session {
+		homeDir, err = ioutil.TempDir("", "cfs-home")
+		Expect(err).NotTo(HaveOccurred())
+
+		cfsWithEnv = func(env []string, args ...string) *gexec.Session {
 			cmd := exec.Command(cfsPath, args...)
-			cmd.Env = append(cmd.Env, "CREDHUB_ADDR="+credhubListenAddr)
-			cmd.Env = append(cmd.Env, "CLIENT_ID="+clientID)
-			cmd.Env = append(cmd.Env, "CLIENT_SECRET="+clientSecret)
+			cmd.Env = append([]string{"HOME=" + homeDir, "PATH=" + os.Getenv("PATH")}, env...)
 			session, err := gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
 			Expect(err).NotTo(HaveOccurred())
 			return session
 		}
 
+		cfsAs = func(clientID, clientSecret string, args ...string) *gexec.Session {
+			return cfsWithEnv([]string{
+				"CREDHUB_ADDR=" + credhubListenAddr,
+				"CLIENT_ID=" + clientID,
+				"CLIENT_SECRET=" + clientSecret,
+				"CREDHUB_CA_CERT=" + caPath,
+			}, args...)
+		}
+
+		cfs = func(args ...string) *gexec.Session {
+			return cfsAs(clientID, clientSecret, args...)
+		}
+
 		getUAAToken := func() string {
 			tokenURL := fmt.Sprintf("https://%s/oauth/token", uaaListenAddr)
 			values := url.Values{
@@ -113,12 +165,13 @@ var _ = Describe("CredhubFs", func() {
 			return tokenResponse.AccessToken
 		}
 
-		setValueInCredhub = func(name, value string) {
+		setInCredhub = func(name, credentialType, valueJSON string) {
 			url := fmt.Sprintf("https://%s/api/v1/data", credhubListenAddr)
 			body := strings.NewReader(fmt.Sprintf(
-				`{"name": "%s", "value": "%s", "type": "value"}`,
+				`{"name": "%s", "value": %s, "type": "%s"}`,
 				name,
-				value,
+				valueJSON,
+				credentialType,
 			))
 
 			req, err := http.NewRequest(http.MethodPut, url, body)
@@ -135,6 +188,10 @@ var _ = Describe("CredhubFs", func() {
 			ExpectWithOffset(1, resp.StatusCode).To(Equal(http.StatusOK), string(respBody))
 		}
 
+		setValueInCredhub = func(name, value string) {
+			setInCredhub(name, "value", fmt.Sprintf(`"%s"`, value))
+		}
+
 		findByPathInCredHub = func(path string) []string {
 			url := fmt.Sprintf("https://%s/api/v1/data?path=%s", credhubListenAddr, path)
 
@@ -168,10 +225,12 @@ var _ = Describe("CredhubFs", func() {
 
 	AfterEach(func() {
 		gexec.KillAndWait()
+		Expect(os.RemoveAll(homeDir)).To(Succeed())
 	})
 
 	AfterSuite(func() {
 		gexec.CleanupBuildArtifacts()
+		Expect(os.RemoveAll(tlsDir)).To(Succeed())
 	})
 
 	It("prints the help text", func() {
@@ -190,6 +249,198 @@ var _ = Describe("CredhubFs", func() {
 			Eventually(session).Should(gexec.Exit(0))
 			Expect(session).To(gbytes.Say(value))
 		})
+
+		It("shows structured credentials", func() {
+			name := "/" + helpers.RandomString()
+			setInCredhub(name, "json", `{"some-key": "some-value"}`)
+
+			session := cfs("cat", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session.Out.Contents()).To(MatchJSON(`{"some-key": "some-value"}`))
+
+			name = "/" + helpers.RandomString()
+			setInCredhub(name, "user", `{"username": "some-username", "password": "some-password"}`)
+
+			session = cfs("cat", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say("username: some-username\npassword: some-password\n"))
+		})
+	})
+
+	Describe("cfs certs expiring", func() {
+		It("reports certificates expiring within the window and exits non-zero", func() {
+			dir := "/" + helpers.RandomString()
+			soon, err := json.Marshal(helpers.GenerateCertificatePEM("soon.example.com", time.Now().Add(10*24*time.Hour)))
+			Expect(err).NotTo(HaveOccurred())
+			later, err := json.Marshal(helpers.GenerateCertificatePEM("later.example.com", time.Now().Add(100*24*time.Hour)))
+			Expect(err).NotTo(HaveOccurred())
+			setInCredhub(dir+"/soon", "certificate", fmt.Sprintf(`{"certificate": %s}`, soon))
+			setInCredhub(dir+"/later", "certificate", fmt.Sprintf(`{"certificate": %s}`, later))
+
+			session := cfs("certs", "expiring", "--within", "30d", dir)
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(session.Out).To(gbytes.Say(dir + `/soon\s+CN=soon.example.com`))
+			Expect(session.Out.Contents()).NotTo(ContainSubstring(dir + "/later"))
+			Expect(session.Err).To(gbytes.Say(`1 certificate\(s\) expire within 30d`))
+
+			session = cfs("certs", "expiring", "--within", "7d", dir)
+			Eventually(session).Should(gexec.Exit(0))
+		})
+	})
+
+	Describe("cfs cp", func() {
+		It("copies credentials", func() {
+			name := "/" + helpers.RandomString()
+			dir := "/" + helpers.RandomString()
+			otherDir := "/" + helpers.RandomString()
+			setInCredhub(name, "password", `"some-password"`)
+			setValueInCredhub(fmt.Sprintf("%s/cred1", dir), "some-value")
+			setValueInCredhub(fmt.Sprintf("%s/nested/cred2", dir), "some-other-value")
+
+			By("Copying a single credential")
+			session := cfs("cp", name, name+"-copy")
+			Eventually(session).Should(gexec.Exit(0))
+
+			session = cfs("cat", name+"-copy")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say("some-password"))
+
+			By("Copying a directory recursively")
+			session = cfs("cp", "-r", dir, otherDir)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(findByPathInCredHub(otherDir)).To(ConsistOf(
+				otherDir+"/cred1",
+				otherDir+"/nested/cred2",
+			))
+
+			session = cfs("cat", otherDir+"/nested/cred2")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say("some-other-value"))
+		})
+	})
+
+	Describe("cfs find", func() {
+		It("finds credentials by name, type and age", func() {
+			dir := "/" + helpers.RandomString()
+			setValueInCredhub(dir+"/db-value", "some-value")
+			setInCredhub(dir+"/nested/db-password", "password", `"some-password"`)
+			setInCredhub(dir+"/nested/other-password", "password", `"some-password"`)
+
+			session := cfs("find", dir, "-name", "db-*", "-type", "password", "-mtime", "-1")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(Equal(dir + "/nested/db-password\n"))
+
+			session = cfs("find", dir, "-mtime", "+1")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session.Out.Contents()).To(BeEmpty())
+		})
+	})
+
+	Describe("cfs generate", func() {
+		It("generates credentials in CredHub", func() {
+			dir := "/" + helpers.RandomString()
+
+			session := cfs("generate", dir+"/password", "--type", "password", "--length", "40", "--exclude-number")
+			Eventually(session).Should(gexec.Exit(0))
+
+			session = cfs("cat", dir+"/password")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(MatchRegexp(`^[A-Za-z]{40}\n$`))
+
+			session = cfs("generate", dir+"/ca", "--type", "certificate", "--common-name", "some-ca", "--is-ca")
+			Eventually(session, 10*time.Second).Should(gexec.Exit(0))
+			session = cfs("generate", dir+"/cert", "--type", "certificate", "--common-name", "example.com", "--ca", dir+"/ca")
+			Eventually(session, 10*time.Second).Should(gexec.Exit(0))
+
+			session = cfs("stat", dir+"/cert")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session.Out).To(gbytes.Say(`Issuer:\s+CN=some-ca`))
+			Expect(session.Out).To(gbytes.Say(`Subject:\s+CN=example.com`))
+
+			session = cfs("generate", dir+"/cert", "--type", "certificate", "--common-name", "example.com", "--ca", dir+"/missing-ca")
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say("CA does not exist"))
+		})
+
+		It("leaves an existing credential unchanged with '--no-clobber'", func() {
+			name := "/" + helpers.RandomString()
+			setValueInCredhub(name, "some-value")
+
+			session := cfs("generate", "--no-clobber", name, "--type", "password")
+			Eventually(session).Should(gexec.Exit(0))
+
+			session = cfs("cat", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(Equal("some-value\n"))
+		})
+	})
+
+	Describe("cfs grep", func() {
+		It("prints the names of credentials containing the pattern", func() {
+			dir := "/" + helpers.RandomString()
+			secret := helpers.RandomString()
+			setValueInCredhub(dir+"/value", "prefix-"+secret)
+			setInCredhub(dir+"/nested/user", "user", fmt.Sprintf(`{"username": "admin", "password": "%s"}`, secret))
+			setValueInCredhub(dir+"/other", helpers.RandomString())
+
+			session := cfs("grep", "-r", secret, dir)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(Equal(dir + "/nested/user\n" + dir + "/value\n"))
+		})
+	})
+
+	Describe("cfs history", func() {
+		It("lists credential versions which can be shown with cat", func() {
+			name := "/" + helpers.RandomString()
+			setValueInCredhub(name, "old-value")
+			setValueInCredhub(name, "new-value")
+
+			session := cfs("history", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say(`0  (\S+)  \S+\n`))
+			Expect(session).To(gbytes.Say(`1  (\S+)  \S+\n`))
+
+			lines := strings.Split(strings.TrimSpace(string(session.Out.Contents())), "\n")
+			oldID := strings.Fields(lines[1])[1]
+
+			session = cfs("cat", "--version", "1", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say("old-value"))
+
+			session = cfs("cat", "--id", oldID)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say("old-value"))
+
+			session = cfs("cat", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say("new-value"))
+		})
+	})
+
+	Describe("cfs logout", func() {
+		It("removes the access token that cfs invocations share", func() {
+			tokenRequests := func() int {
+				return strings.Count(string(uaaSession.Out.Contents()), "/oauth/token")
+			}
+			name := "/" + helpers.RandomString()
+			setValueInCredhub(name, "some-value")
+			Eventually(tokenRequests).Should(Equal(1))
+
+			Eventually(cfs("cat", name)).Should(gexec.Exit(0))
+			Eventually(cfs("cat", name)).Should(gexec.Exit(0))
+			Eventually(tokenRequests).Should(Equal(2))
+			Consistently(tokenRequests).Should(Equal(2))
+
+			info, err := os.Stat(filepath.Join(homeDir, ".cfs", "tokens.json"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+			Eventually(cfs("logout")).Should(gexec.Exit(0))
+			Expect(filepath.Join(homeDir, ".cfs", "tokens.json")).NotTo(BeAnExistingFile())
+
+			Eventually(cfs("cat", name)).Should(gexec.Exit(0))
+			Eventually(tokenRequests).Should(Equal(3))
+		})
 	})
 
 	Describe("cfs ls", func() {
@@ -218,6 +469,121 @@ var _ = Describe("CredhubFs", func() {
 		})
 	})
 
+	Describe("cfs mount", func() {
+		var (
+			mountpoint string
+			session    *gexec.Session
+		)
+
+		BeforeEach(func() {
+			if _, err := os.Stat("/dev/fuse"); err != nil {
+				Skip("/dev/fuse is not available")
+			}
+			if _, err := exec.LookPath("fusermount"); err != nil {
+				Skip("fusermount is not available")
+			}
+
+			var err error
+			mountpoint, err = ioutil.TempDir("", "cfs-mount")
+			Expect(err).NotTo(HaveOccurred())
+
+			session = cfs("mount", mountpoint)
+			Eventually(func() bool {
+				return helpers.IsMounted(mountpoint)
+			}, 5*time.Second).Should(BeTrue())
+		})
+
+		AfterEach(func() {
+			if session != nil {
+				session.Interrupt()
+				Eventually(session, 5*time.Second).Should(gexec.Exit(0))
+			}
+			Expect(os.RemoveAll(mountpoint)).To(Succeed())
+		})
+
+		It("exposes credentials as files and paths as directories", func() {
+			dir := helpers.RandomString()
+			name := helpers.RandomString()
+			value := helpers.RandomString()
+			setValueInCredhub("/"+dir+"/"+name, value)
+
+			info, err := os.Stat(filepath.Join(mountpoint, dir))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.IsDir()).To(BeTrue())
+
+			contents, err := ioutil.ReadFile(filepath.Join(mountpoint, dir, name))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal(value + "\n"))
+		})
+
+		It("sets credentials written to files and deletes removed files", func() {
+			name := "/" + helpers.RandomString()
+			value := helpers.RandomString()
+
+			Expect(ioutil.WriteFile(filepath.Join(mountpoint, name), []byte(value+"\n"), 0600)).To(Succeed())
+
+			catSession := cfs("cat", name)
+			Eventually(catSession).Should(gexec.Exit(0))
+			Expect(catSession).To(gbytes.Say(value))
+
+			Expect(os.Remove(filepath.Join(mountpoint, name))).To(Succeed())
+			Expect(findByPathInCredHub("/")).NotTo(ContainElement(name))
+		})
+	})
+
+	Describe("cfs mv", func() {
+		It("moves credentials", func() {
+			name := "/" + helpers.RandomString()
+			dir := "/" + helpers.RandomString()
+			otherDir := "/" + helpers.RandomString()
+			setValueInCredhub(name, "some-value")
+			setValueInCredhub(fmt.Sprintf("%s/cred1", dir), "some-value")
+			setValueInCredhub(fmt.Sprintf("%s/nested/cred2", dir), "some-other-value")
+
+			By("Moving a single credential")
+			session := cfs("mv", name, name+"-moved")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(findByPathInCredHub("/")).To(ContainElement(name + "-moved"))
+			Expect(findByPathInCredHub("/")).NotTo(ContainElement(name))
+
+			By("Moving a directory recursively")
+			session = cfs("mv", "-r", dir, otherDir)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(findByPathInCredHub(otherDir)).To(ConsistOf(
+				otherDir+"/cred1",
+				otherDir+"/nested/cred2",
+			))
+			Expect(findByPathInCredHub(dir)).To(BeEmpty())
+		})
+	})
+
+	Describe("cfs regenerate", func() {
+		It("regenerates a credential or every certificate signed by a CA", func() {
+			dir := "/" + helpers.RandomString()
+
+			Eventually(cfs("generate", dir+"/password", "--type", "password")).Should(gexec.Exit(0))
+			Eventually(cfs("generate", dir+"/ca", "--type", "certificate", "--common-name", "some-ca", "--is-ca"), 10*time.Second).Should(gexec.Exit(0))
+			Eventually(cfs("generate", dir+"/cert", "--type", "certificate", "--common-name", "example.com", "--ca", dir+"/ca"), 10*time.Second).Should(gexec.Exit(0))
+
+			session := cfs("regenerate", dir+"/password")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(Equal(dir + "/password\n"))
+
+			session = cfs("history", dir+"/password")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(strings.Count(string(session.Out.Contents()), "\n")).To(Equal(2))
+
+			session = cfs("regenerate", "--signed-by", dir+"/ca")
+			Eventually(session, 10*time.Second).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(Equal(dir + "/cert\n"))
+
+			setValueInCredhub(dir+"/static", "some-value")
+			session = cfs("regenerate", dir+"/static")
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say("value was statically set"))
+		})
+	})
+
 	Describe("cfs rm", func() {
 		It("removes credentials", func() {
 			name := "/" + helpers.RandomString()
@@ -239,4 +605,497 @@ var _ = Describe("CredhubFs", func() {
 			Expect(findByPathInCredHub("/")).To(HaveLen(1))
 		})
 	})
+
+	Describe("cfs rollback", func() {
+		It("restores the previous version of a credential", func() {
+			name := "/" + helpers.RandomString()
+			setValueInCredhub(name, "good-value")
+			setValueInCredhub(name, "bad-value")
+
+			session := cfs("rollback", "--dry-run", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say("Would restore %s to version", name))
+
+			session = cfs("cat", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say("bad-value"))
+
+			session = cfs("rollback", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say("Restored %s to version", name))
+
+			session = cfs("cat", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say("good-value"))
+		})
+	})
+
+	Describe("cfs serve-webdav", func() {
+		var (
+			davListenAddr string
+			davRequest    func(method, name, username, password, body string) (int, string)
+		)
+
+		BeforeEach(func() {
+			davListenAddr = helpers.GetFreeAddr()
+
+			davRequest = func(method, name, username, password, body string) (int, string) {
+				req, err := http.NewRequest(method, "https://"+davListenAddr+name, strings.NewReader(body))
+				ExpectWithOffset(1, err).NotTo(HaveOccurred())
+				if password != "" {
+					req.SetBasicAuth(username, password)
+				}
+
+				resp, err := helpers.HTTPClient.Do(req)
+				ExpectWithOffset(1, err).NotTo(HaveOccurred())
+				defer resp.Body.Close()
+				respBody, err := ioutil.ReadAll(resp.Body)
+				ExpectWithOffset(1, err).NotTo(HaveOccurred())
+				return resp.StatusCode, string(respBody)
+			}
+		})
+
+		It("serves credentials read-only over WebDAV to authenticated clients", func() {
+			name := "/" + helpers.RandomString()
+			value := helpers.RandomString()
+			setValueInCredhub(name, value)
+
+			session := cfs(
+				"serve-webdav",
+				"--listen", davListenAddr,
+				"--cert-path", filepath.Join("test", "fixtures", "127.0.0.1-cert.pem"),
+				"--key-path", filepath.Join("test", "fixtures", "127.0.0.1-key.pem"),
+			)
+			Eventually(session).Should(gbytes.Say(`Username: cfs\nPassword: (\w+)\n`))
+			password := regexp.MustCompile(`Password: (\w+)`).FindStringSubmatch(string(session.Out.Contents()))[1]
+
+			status, _ := davRequest(http.MethodGet, name, "", "", "")
+			Expect(status).To(Equal(http.StatusUnauthorized))
+
+			status, body := davRequest(http.MethodGet, name, "cfs", password, "")
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).To(Equal(value + "\n"))
+
+			status, _ = davRequest(http.MethodPut, name, "cfs", password, "new-value")
+			Expect(status).To(Equal(http.StatusForbidden))
+
+			session.Interrupt()
+			Eventually(session, 5*time.Second).Should(gexec.Exit(0))
+		})
+
+		It("sets credentials over WebDAV with '--writable'", func() {
+			name := "/" + helpers.RandomString()
+			value := helpers.RandomString()
+
+			session := cfsWithEnv([]string{
+				"CREDHUB_ADDR=" + credhubListenAddr,
+				"CLIENT_ID=" + clientID,
+				"CLIENT_SECRET=" + clientSecret,
+				"CREDHUB_CA_CERT=" + caPath,
+				"CFS_WEBDAV_PASSWORD=some-password",
+			},
+				"serve-webdav",
+				"--listen", davListenAddr,
+				"--cert-path", filepath.Join("test", "fixtures", "127.0.0.1-cert.pem"),
+				"--key-path", filepath.Join("test", "fixtures", "127.0.0.1-key.pem"),
+				"--username", "some-user",
+				"--writable",
+			)
+			Eventually(session).Should(gbytes.Say("Username: some-user\n"))
+			Expect(session.Out.Contents()).NotTo(ContainSubstring("some-password"))
+
+			status, _ := davRequest(http.MethodPut, name, "some-user", "some-password", value)
+			Expect(status).To(Equal(http.StatusCreated))
+
+			catSession := cfs("cat", name)
+			Eventually(catSession).Should(gexec.Exit(0))
+			Expect(catSession).To(gbytes.Say(value))
+
+			session.Interrupt()
+			Eventually(session, 5*time.Second).Should(gexec.Exit(0))
+		})
+	})
+
+	Describe("cfs setfacl", func() {
+		It("grants and revokes permissions which are shown by getfacl", func() {
+			dir := "/" + helpers.RandomString()
+			name := dir + "/" + helpers.RandomString()
+			value := helpers.RandomString()
+			setValueInCredhub(name, value)
+			otherActor := "uaa-client:" + otherClientID
+
+			session := cfsAs(otherClientID, otherClientSecret, "cat", name)
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say("no such credential"))
+
+			Eventually(cfs("setfacl", "-m", otherActor+":read", dir+"/*")).Should(gexec.Exit(0))
+
+			session = cfs("getfacl", dir+"/*")
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say("use '--actor'"))
+
+			session = cfs("getfacl", "--actor", otherActor, dir+"/*")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(Equal(fmt.Sprintf("# path: %s/*\n%s:read\n", dir, otherActor)))
+
+			session = cfsAs(otherClientID, otherClientSecret, "cat", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say(value))
+
+			session = cfsAs(otherClientID, otherClientSecret, "write", name, "some-other-value")
+			Eventually(session).Should(gexec.Exit(1))
+
+			Eventually(cfs("setfacl", "-x", otherActor, dir+"/*")).Should(gexec.Exit(0))
+
+			session = cfsAs(otherClientID, otherClientSecret, "cat", name)
+			Eventually(session).Should(gexec.Exit(1))
+		})
+	})
+
+	Describe("cfs stat", func() {
+		It("shows credential metadata", func() {
+			name := "/" + helpers.RandomString()
+			setValueInCredhub(name, "first-value")
+			setValueInCredhub(name, "second-value")
+
+			session := cfs("stat", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say(`Name:\s+` + name))
+			Expect(session).To(gbytes.Say(`Type:\s+value`))
+			Expect(session).To(gbytes.Say(`Versions:\s+2`))
+		})
+
+		It("shows certificate details with a custom format", func() {
+			name := "/" + helpers.RandomString()
+			certificate, err := json.Marshal(helpers.GenerateCertificatePEM("some-common-name", time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)))
+			Expect(err).NotTo(HaveOccurred())
+			setInCredhub(name, "certificate", fmt.Sprintf(`{"certificate": %s}`, certificate))
+
+			session := cfs("stat", "--format", "{{.Certificate.Subject}} {{.Certificate.NotAfter.Format \"2006-01-02\"}}", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(Equal("CN=some-common-name 2030-01-02\n"))
+		})
+	})
+
+	Describe("cfs target", func() {
+		It("uses credentials from named targets", func() {
+			cfsWithoutCredentials := func(args ...string) *gexec.Session {
+				return cfsWithEnv([]string{"LOCAL_SECRET=" + clientSecret}, args...)
+			}
+			name := "/" + helpers.RandomString()
+			value := helpers.RandomString()
+			setValueInCredhub(name, value)
+
+			Eventually(cfsWithoutCredentials(
+				"target", "add", "local",
+				"--credhub-addr", credhubListenAddr,
+				"--client-id", clientID,
+				"--client-secret-env", "LOCAL_SECRET",
+				"--ca-cert", caPath,
+			)).Should(gexec.Exit(0))
+			Eventually(cfsWithoutCredentials(
+				"target", "add", "other",
+				"--credhub-addr", credhubListenAddr,
+				"--client-id", otherClientID,
+				"--client-secret-command", "echo "+otherClientSecret,
+				"--ca-cert", caPath,
+			)).Should(gexec.Exit(0))
+
+			Eventually(cfsWithoutCredentials("login")).Should(gexec.Exit(0))
+			Expect(filepath.Join(homeDir, ".cfs", "tokens.json")).To(BeAnExistingFile())
+
+			session := cfsWithoutCredentials("target", "list")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say(`\* local\s+` + credhubListenAddr))
+			Expect(session).To(gbytes.Say(`  other\s+` + credhubListenAddr))
+
+			session = cfsWithoutCredentials("cat", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say(value))
+
+			Eventually(cfsWithoutCredentials("target", "use", "other")).Should(gexec.Exit(0))
+
+			session = cfsWithoutCredentials("cat", name)
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say("no such credential"))
+
+			session = cfsWithoutCredentials("--target", "local", "cat", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say(value))
+
+			Eventually(cfsWithoutCredentials("target", "remove", "local")).Should(gexec.Exit(0))
+
+			session = cfsWithoutCredentials("--target", "local", "cat", name)
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(session).To(gbytes.Say("could not find target local"))
+		})
+	})
+
+	Describe("TLS validation", func() {
+		var (
+			name string
+			env  []string
+		)
+
+		BeforeEach(func() {
+			name = "/" + helpers.RandomString()
+			setValueInCredhub(name, "some-value")
+			env = []string{
+				"CREDHUB_ADDR=" + credhubListenAddr,
+				"CLIENT_ID=" + clientID,
+				"CLIENT_SECRET=" + clientSecret,
+			}
+		})
+
+		It("verifies certificates against the given CAs", func() {
+			session := cfsWithEnv(env, "cat", name)
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say("signed by an unknown authority"))
+
+			Eventually(cfsWithEnv(env, "cat", name, "--ca-cert", caPath)).Should(gexec.Exit(0))
+			Eventually(cfsWithEnv(append(env, "CREDHUB_CA_CERT="+ca.CertificatePEM), "cat", name)).Should(gexec.Exit(0))
+			Eventually(cfsWithEnv(env, "cat", name, "--skip-tls-validation")).Should(gexec.Exit(0))
+		})
+
+		It("rejects certificates which do not match the address", func() {
+			env[0] = "CREDHUB_ADDR=" + strings.Replace(credhubListenAddr, "127.0.0.1", "localhost", 1)
+
+			session := cfsWithEnv(env, "cat", name, "--ca-cert", caPath)
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say("does not match its address"))
+		})
+	})
+
+	Describe("authenticating as a UAA user", func() {
+		var (
+			dir  string
+			name string
+			env  []string
+		)
+
+		BeforeEach(func() {
+			dir = "/" + helpers.RandomString()
+			name = dir + "/" + helpers.RandomString()
+			setValueInCredhub(name, "some-value")
+			Eventually(cfs("setfacl", "-m", "uaa-user:"+username+":read", dir+"/*")).Should(gexec.Exit(0))
+
+			env = []string{"CREDHUB_ADDR=" + credhubListenAddr, "CREDHUB_CA_CERT=" + caPath}
+		})
+
+		It("logs in with a username and password", func() {
+			session := cfsWithEnv(env, "cat", name, "--username", username, "--password", "some-wrong-password")
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say("401 Unauthorized"))
+
+			session = cfsWithEnv(append(env, "CREDHUB_USERNAME="+username, "CREDHUB_PASSWORD="+password), "cat", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say("some-value"))
+		})
+
+		It("uses refresh tokens and access tokens obtained elsewhere", func() {
+			resp, err := helpers.HTTPClient.PostForm(fmt.Sprintf("https://%s/oauth/token", uaaListenAddr), url.Values{
+				"client_id":     {"credhub_cli"},
+				"client_secret": {""},
+				"grant_type":    {"password"},
+				"username":      {username},
+				"password":      {password},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+			var tokenResponse struct {
+				AccessToken  string `json:"access_token"`
+				RefreshToken string `json:"refresh_token"`
+			}
+			Expect(json.NewDecoder(resp.Body).Decode(&tokenResponse)).To(Succeed())
+
+			session := cfsWithEnv(append(env, "CREDHUB_REFRESH_TOKEN="+tokenResponse.RefreshToken), "cat", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say("some-value"))
+
+			tokenRequests := strings.Count(string(uaaSession.Out.Contents()), "/oauth/token")
+
+			session = cfsWithEnv(env, "cat", name, "--token", tokenResponse.AccessToken)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say("some-value"))
+
+			Expect(strings.Count(string(uaaSession.Out.Contents()), "/oauth/token")).To(Equal(tokenRequests))
+		})
+	})
+
+	Describe("client certificates", func() {
+		It("authenticates with a client certificate instead of a UAA client", func() {
+			name := "/" + helpers.RandomString()
+			value := helpers.RandomString()
+			setValueInCredhub(name, value)
+
+			appGUID := helpers.RandomString()
+			clientCertPath, clientKeyPath, err := ca.WriteClientCertificate(tlsDir, appGUID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(cfs("setfacl", "-m", "mtls-app:"+appGUID+":read", name)).Should(gexec.Exit(0))
+			tokenRequests := strings.Count(string(uaaSession.Out.Contents()), "/oauth/token")
+
+			session := cfsWithEnv([]string{
+				"CREDHUB_ADDR=" + credhubListenAddr,
+				"CF_INSTANCE_CERT=" + clientCertPath,
+				"CF_INSTANCE_KEY=" + clientKeyPath,
+				"CREDHUB_CA_CERT=" + caPath,
+			}, "cat", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say(value))
+
+			session = cfsWithEnv([]string{"CREDHUB_CA_CERT=" + caPath}, "cat", name,
+				"--credhub-addr", credhubListenAddr,
+				"--client-cert", clientCertPath,
+				"--client-key", clientKeyPath,
+			)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say(value))
+
+			Expect(strings.Count(string(uaaSession.Out.Contents()), "/oauth/token")).To(Equal(tokenRequests))
+		})
+	})
+
+	Describe("interrupting cfs", func() {
+		It("cancels requests which are in flight", func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			defer listener.Close()
+
+			accepted := make(chan net.Conn, 1)
+			go func() {
+				defer GinkgoRecover()
+				conn, err := listener.Accept()
+				if err == nil {
+					accepted <- conn
+				}
+			}()
+
+			session := cfsWithEnv([]string{
+				"CREDHUB_ADDR=" + listener.Addr().String(),
+				"CLIENT_ID=" + clientID,
+				"CLIENT_SECRET=" + clientSecret,
+			}, "cat", "/some-name")
+
+			var conn net.Conn
+			Eventually(accepted).Should(Receive(&conn))
+			defer conn.Close()
+
+			session.Interrupt()
+			Eventually(session, time.Second).Should(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say("interrupt"))
+		})
+	})
+
+	Describe("transient CredHub failures", func() {
+		injectFault := func(method, path string, status, count int) {
+			body := fmt.Sprintf(`{"method": "%s", "path": "%s", "status": %d, "count": %d}`, method, path, status, count)
+			resp, err := helpers.HTTPClient.Post("https://"+credhubListenAddr+"/fake/faults", "application/json", strings.NewReader(body))
+			ExpectWithOffset(1, err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+			ExpectWithOffset(1, resp.StatusCode).To(Equal(http.StatusNoContent))
+		}
+
+		It("retries idempotent requests", func() {
+			name := "/" + helpers.RandomString()
+			setValueInCredhub(name, "some-value")
+
+			injectFault("GET", "/api/v1/data", http.StatusServiceUnavailable, 2)
+			session := cfs("cat", name)
+			Eventually(session, 10*time.Second).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say("some-value"))
+
+			injectFault("GET", "/api/v1/data", http.StatusServiceUnavailable, 3)
+			session = cfs("cat", "--retries", "1", name)
+			Eventually(session, 10*time.Second).Should(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say("503 Service Unavailable"))
+		})
+
+		It("does not retry requests which are not idempotent", func() {
+			injectFault("POST", "/api/v1/data", http.StatusServiceUnavailable, 1)
+			session := cfs("generate", "/"+helpers.RandomString(), "--type", "password")
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say("503 Service Unavailable"))
+		})
+	})
+
+	Describe("cfs tree", func() {
+		It("renders the credentials under a path as a tree", func() {
+			dir := "/" + helpers.RandomString()
+			setValueInCredhub(dir+"/cred1", "some-value")
+			setValueInCredhub(dir+"/nested/cred2", "some-value")
+			setValueInCredhub(dir+"/nested/cred3", "some-value")
+
+			session := cfs("tree", "--du", dir)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say(regexp.QuoteMeta(dir + " [3 credentials, newest ")))
+			Expect(session).To(gbytes.Say(`├── cred1 \[`))
+			Expect(session).To(gbytes.Say(`└── nested/ \[2 credentials, newest `))
+			Expect(session).To(gbytes.Say(`    ├── cred2 \[`))
+			Expect(session).To(gbytes.Say(`    └── cred3 \[`))
+			Expect(session).To(gbytes.Say("1 directory, 3 credentials"))
+		})
+	})
+
+	Describe("cfs write", func() {
+		It("sets the value of a credential", func() {
+			name := "/" + helpers.RandomString()
+			value := helpers.RandomString()
+			newValue := helpers.RandomString()
+
+			By("Writing a value from an argument")
+			session := cfs("write", name, value)
+			Eventually(session).Should(gexec.Exit(0))
+
+			session = cfs("cat", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say(value))
+
+			By("Refusing to overwrite the credential with '-n'")
+			session = cfs("write", "-n", name, newValue)
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say("credential already exists"))
+
+			By("Overwriting the credential with a value from stdin")
+			cmd := exec.Command(cfsPath, "write", name)
+			cmd.Env = []string{
+				"CREDHUB_ADDR=" + credhubListenAddr,
+				"CLIENT_ID=" + clientID,
+				"CLIENT_SECRET=" + clientSecret,
+				"CREDHUB_CA_CERT=" + caPath,
+			}
+			cmd.Stdin = strings.NewReader(newValue + "\n")
+			session, err := gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(session).Should(gexec.Exit(0))
+
+			session = cfs("cat", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say(newValue))
+		})
+	})
+
+	Describe("cfs tee", func() {
+		It("sets the value of a credential from stdin and copies it to stdout", func() {
+			name := "/" + helpers.RandomString()
+			value := helpers.RandomString()
+
+			cmd := exec.Command(cfsPath, "tee", name)
+			cmd.Env = []string{
+				"CREDHUB_ADDR=" + credhubListenAddr,
+				"CLIENT_ID=" + clientID,
+				"CLIENT_SECRET=" + clientSecret,
+				"CREDHUB_CA_CERT=" + caPath,
+			}
+			cmd.Stdin = strings.NewReader(value + "\n")
+			session, err := gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(Equal(value + "\n"))
+
+			session = cfs("cat", name)
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session).To(gbytes.Say(value))
+		})
+	})
 })
@@ -1,8 +1,9 @@
 module github.com/mdelillo/credhub-fs
 
-go 1.12
+go 1.16
 
 require (
+	bazil.org/fuse v0.0.0-20200407214033-5883e5a4b512
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/gin-contrib/sse v0.0.0-20190301062529-5545eab6dad3 // indirect
 	github.com/gin-gonic/gin v1.3.0
@@ -13,6 +14,9 @@ require (
 	github.com/onsi/ginkgo v1.8.0
 	github.com/onsi/gomega v1.5.0
 	github.com/spf13/cobra v0.0.3
+	github.com/spf13/pflag v1.0.3
 	github.com/spf13/viper v1.3.2
+	golang.org/x/net v0.0.0-20190320064053-1272bf9dcd53
 	gopkg.in/go-playground/validator.v8 v8.18.2 // indirect
+	gopkg.in/yaml.v2 v2.2.2
 )
@@ -1,43 +1,87 @@
 package cat
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
+	"github.com/google/uuid"
 	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
 	"github.com/mdelillo/credhub-fs/pkg/credhub"
 	"github.com/spf13/cobra"
 )
 
 type cmdCatRunner struct {
+	ctx           context.Context
 	credhubClient credhubClient
+	version       int
+	id            string
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
 type credhubClient credhub.Client
 
 func NewCmdCat(dependencies cmdutil.Dependencies) *cobra.Command {
-	return &cobra.Command{
-		Use:   "cat /path/to/credential",
+	cmd := &cobra.Command{
+		Use:   "cat [--version N] /path/to/credential | cat --id ID",
 		Short: "Get the value of a credential",
 		Args: func(cmd *cobra.Command, args []string) error {
-			if len(args) != 1 {
-				return errors.New("must provide a credential path")
+			id, _ := cmd.Flags().GetString("id")
+			if id == "" {
+				if len(args) != 1 {
+					return errors.New("must provide a credential path")
+				}
+				return nil
+			}
+
+			if cmd.Flags().Changed("version") {
+				return errors.New("cannot use '--version' with '--id'")
+			}
+			if len(args) != 0 {
+				return errors.New("cannot provide a credential path with '--id'")
 			}
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			version, _ := cmd.Flags().GetInt("version")
+			id, _ := cmd.Flags().GetString("id")
+
 			cmd.SilenceUsage = true
 
-			c := &cmdCatRunner{credhubClient: dependencies.GetCredhubClient()}
+			c := &cmdCatRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+				version:       version,
+				id:            id,
+			}
 			return c.Run(cmd, args)
 		},
 	}
+
+	cmd.Flags().Int("version", 0, "number of versions before the current one to show")
+	cmd.Flags().String("id", "", "ID of the credential version to show")
+
+	return cmd
 }
 
 func (c *cmdCatRunner) Run(cmd *cobra.Command, args []string) error {
-	name := args[0]
-	cred, err := c.credhubClient.GetCredentialByName(name)
+	var name string
+	var cred credhub.Credential
+	var err error
+	if c.id != "" {
+		name = c.id
+		id, parseErr := uuid.Parse(c.id)
+		if parseErr != nil {
+			return fmt.Errorf("'%s': invalid credential ID", c.id)
+		}
+		cred, err = c.credhubClient.GetCredentialByID(c.ctx, id)
+	} else if c.version != 0 {
+		name = args[0]
+		cred, err = cmdutil.GetCredentialVersion(c.ctx, c.credhubClient, name, c.version)
+	} else {
+		name = args[0]
+		cred, err = c.credhubClient.GetCredentialByName(c.ctx, name)
+	}
 	if err != nil {
 		switch err.(type) {
 		case *credhub.ErrCredentialNotFound:
@@ -47,6 +91,11 @@ func (c *cmdCatRunner) Run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	fmt.Fprintln(cmd.OutOrStdout(), cred.Value)
+	output, err := credhub.FormatValue(cred.Value)
+	if err != nil {
+		return fmt.Errorf("failed to format credential: %s", err.Error())
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), output)
 	return nil
 }
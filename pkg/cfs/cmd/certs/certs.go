@@ -0,0 +1,24 @@
+package certs
+
+import (
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/spf13/cobra"
+)
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+func NewCmdCerts(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "certs",
+		Short: "Inspect certificate credentials",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newCmdExpiring(dependencies))
+
+	return cmd
+}
@@ -0,0 +1,265 @@
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/spf13/cobra"
+)
+
+const day = 24 * time.Hour
+
+type cmdExpiringRunner struct {
+	ctx           context.Context
+	credhubClient credhubClient
+	window        time.Duration
+	within        string
+	output        string
+}
+
+type expiringCertificate struct {
+	Name         string    `json:"name"`
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SANs         []string  `json:"sans"`
+	IsCA         bool      `json:"is_ca"`
+	SelfSigned   bool      `json:"self_signed"`
+	CAName       string    `json:"ca_name,omitempty"`
+	NotAfter     time.Time `json:"not_after"`
+	DaysToExpiry int       `json:"days_to_expiry"`
+}
+
+func newCmdExpiring(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "expiring [PATH]",
+		Short: "Report certificates that expire within a window, exiting non-zero if there are any",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return errors.New("must provide at most one path")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			within, _ := cmd.Flags().GetString("within")
+			output, _ := cmd.Flags().GetString("output")
+
+			window, err := parseWindow(within)
+			if err != nil {
+				return err
+			}
+			if output != "table" && output != "json" {
+				return fmt.Errorf("invalid output format '%s': must be 'table' or 'json'", output)
+			}
+
+			cmd.SilenceUsage = true
+
+			c := &cmdExpiringRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+				window:        window,
+				within:        within,
+				output:        output,
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	cmd.Flags().String("within", "30d", "report certificates expiring within this long, in days (30d) or as a Go duration (12h)")
+	cmd.Flags().StringP("output", "o", "table", "output format: table or json")
+
+	return cmd
+}
+
+func parseWindow(within string) (time.Duration, error) {
+	if strings.HasSuffix(within, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(within, "d"))
+		if err == nil && days >= 0 {
+			return time.Duration(days) * day, nil
+		}
+	} else if duration, err := time.ParseDuration(within); err == nil && duration >= 0 {
+		return duration, nil
+	}
+	return 0, fmt.Errorf("invalid value '%s' for '--within'", within)
+}
+
+func (c *cmdExpiringRunner) Run(cmd *cobra.Command, args []string) error {
+	path := "/"
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if path[0] != '/' {
+		path = "/" + path
+	}
+
+	names, err := c.credentialNames(path)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var expiring []expiringCertificate
+	failures := 0
+	for _, name := range names {
+		credential, err := c.credhubClient.GetCredentialByName(c.ctx, name)
+		if err != nil {
+			fmt.Fprintf(cmd.OutOrStderr(), "failed to check %s: failed to get credential: %s\n", name, err.Error())
+			failures++
+			continue
+		}
+
+		certificate, ok := credential.Value.(credhub.Certificate)
+		if !ok {
+			continue
+		}
+
+		parsed, err := certificate.ParseCertificate()
+		if err != nil {
+			fmt.Fprintf(cmd.OutOrStderr(), "failed to check %s: %s\n", name, err.Error())
+			failures++
+			continue
+		}
+
+		if parsed.NotAfter.After(now.Add(c.window)) {
+			continue
+		}
+
+		var sans []string
+		sans = append(sans, parsed.DNSNames...)
+		for _, ip := range parsed.IPAddresses {
+			sans = append(sans, ip.String())
+		}
+		sans = append(sans, parsed.EmailAddresses...)
+		for _, uri := range parsed.URIs {
+			sans = append(sans, uri.String())
+		}
+
+		expiring = append(expiring, expiringCertificate{
+			Name:         name,
+			Subject:      parsed.Subject.String(),
+			Issuer:       parsed.Issuer.String(),
+			SANs:         sans,
+			IsCA:         parsed.IsCA,
+			SelfSigned:   isSelfSigned(parsed),
+			CAName:       certificate.CAName,
+			NotAfter:     parsed.NotAfter,
+			DaysToExpiry: int(math.Floor(float64(parsed.NotAfter.Sub(now)) / float64(day))),
+		})
+	}
+
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiring[i].NotAfter.Before(expiring[j].NotAfter)
+	})
+
+	if err := c.print(cmd.OutOrStdout(), expiring); err != nil {
+		return err
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("failed to check %d of %d credentials", failures, len(names))
+	}
+	if len(expiring) > 0 {
+		return fmt.Errorf("%d certificate(s) expire within %s", len(expiring), c.within)
+	}
+	return nil
+}
+
+func (c *cmdExpiringRunner) credentialNames(path string) ([]string, error) {
+	credentials, err := c.credhubClient.FindCredentialsByPath(c.ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find credentials: %s", err.Error())
+	}
+
+	if len(credentials) == 0 && path != "/" {
+		if _, err := c.credhubClient.GetCredentialByName(c.ctx, path); err != nil {
+			switch err.(type) {
+			case *credhub.ErrCredentialNotFound:
+				return nil, fmt.Errorf("'%s': no such credential or path", path)
+			default:
+				return nil, fmt.Errorf("failed to get credential: %s", err.Error())
+			}
+		}
+		return []string{path}, nil
+	}
+
+	var names []string
+	for _, credential := range credentials {
+		names = append(names, credential.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (c *cmdExpiringRunner) print(out io.Writer, expiring []expiringCertificate) error {
+	if c.output == "json" {
+		if expiring == nil {
+			expiring = []expiringCertificate{}
+		}
+		output, err := json.MarshalIndent(expiring, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format output: %s", err.Error())
+		}
+		fmt.Fprintln(out, string(output))
+		return nil
+	}
+
+	if len(expiring) == 0 {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSUBJECT\tSANS\tISSUER\tDAYS\tEXPIRES")
+	for _, certificate := range expiring {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n",
+			certificate.Name,
+			certificate.Subject,
+			orNone(strings.Join(certificate.SANs, ",")),
+			issuerDescription(certificate),
+			certificate.DaysToExpiry,
+			certificate.NotAfter.UTC().Format(time.RFC3339),
+		)
+	}
+	return w.Flush()
+}
+
+func isSelfSigned(certificate *x509.Certificate) bool {
+	if !bytes.Equal(certificate.RawIssuer, certificate.RawSubject) {
+		return false
+	}
+	return certificate.CheckSignature(certificate.SignatureAlgorithm, certificate.RawTBSCertificate, certificate.Signature) == nil
+}
+
+func issuerDescription(certificate expiringCertificate) string {
+	var description string
+	switch {
+	case certificate.CAName != "":
+		description = certificate.CAName
+	case certificate.SelfSigned:
+		description = "self-signed"
+	default:
+		description = certificate.Issuer
+	}
+	if certificate.IsCA {
+		description += " (CA)"
+	}
+	return description
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,166 @@
+package certs_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/certs"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/certs/certsfakes"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/test/helpers"
+)
+
+var _ = Describe("Certs", func() {
+	Describe("expiring", func() {
+		var (
+			fakeCredhubClient *certsfakes.FakeCredhubClient
+			dependencies      cmdutil.Dependencies
+			credentials       map[string]credhub.Credential
+			inDays            func(days int) time.Time
+			runExpiring       func(args ...string) (string, error)
+		)
+
+		BeforeEach(func() {
+			fakeCredhubClient = &certsfakes.FakeCredhubClient{}
+			dependencies = cmdutil.NewDependencies()
+			dependencies.SetCredhubClient(fakeCredhubClient)
+
+			inDays = func(days int) time.Time {
+				return time.Now().Add(time.Duration(days)*24*time.Hour + time.Hour).UTC().Truncate(time.Second)
+			}
+
+			credentials = map[string]credhub.Credential{
+				"/certs/soon": {Type: "certificate", Value: credhub.Certificate{
+					CAName:      "/certs/ca",
+					Certificate: helpers.GenerateCertificatePEM("soon.example.com", inDays(10)),
+				}},
+				"/certs/expired": {Type: "certificate", Value: credhub.Certificate{
+					Certificate: helpers.GenerateCertificatePEM("expired.example.com", inDays(-3)),
+				}},
+				"/certs/later": {Type: "certificate", Value: credhub.Certificate{
+					Certificate: helpers.GenerateCertificatePEM("later.example.com", inDays(100)),
+				}},
+				"/certs/password": {Type: "password", Value: credhub.Password("some-password")},
+			}
+			fakeCredhubClient.FindCredentialsByPathStub = func(_ context.Context, path string) ([]credhub.Credential, error) {
+				if path != "/certs" {
+					return nil, nil
+				}
+				var found []credhub.Credential
+				for name := range credentials {
+					found = append(found, credhub.Credential{Name: name})
+				}
+				return found, nil
+			}
+			fakeCredhubClient.GetCredentialByNameStub = func(_ context.Context, name string) (credhub.Credential, error) {
+				credential, ok := credentials[name]
+				if !ok {
+					return credhub.Credential{}, &credhub.ErrCredentialNotFound{}
+				}
+				credential.Name = name
+				return credential, nil
+			}
+
+			runExpiring = func(args ...string) (string, error) {
+				var output bytes.Buffer
+				cmd := certs.NewCmdCerts(dependencies)
+				cmd.SetArgs(append([]string{"expiring"}, args...))
+				cmd.SetOutput(&output)
+				cmd.SilenceErrors = true
+				err := cmd.Execute()
+				return output.String(), err
+			}
+		})
+
+		It("reports certificates expiring within the window, soonest first, and returns an error", func() {
+			output, err := runExpiring("--within", "30d", "/certs")
+			Expect(err).To(MatchError("2 certificate(s) expire within 30d"))
+
+			Expect(output).To(MatchRegexp(`NAME\s+SUBJECT\s+SANS\s+ISSUER\s+DAYS\s+EXPIRES\n`))
+			Expect(output).To(MatchRegexp(`/certs/expired\s+CN=expired.example.com\s+-\s+self-signed\s+-3\s+\S+\n` +
+				`/certs/soon\s+CN=soon.example.com\s+-\s+/certs/ca\s+10\s+\S+\n`))
+			Expect(output).NotTo(ContainSubstring("/certs/later"))
+		})
+
+		It("prints JSON with '--output json'", func() {
+			output, err := runExpiring("--within", "30d", "-o", "json", "/certs")
+			Expect(err).To(HaveOccurred())
+
+			var report []map[string]interface{}
+			Expect(json.Unmarshal([]byte(output), &report)).To(Succeed())
+			Expect(report).To(HaveLen(2))
+			Expect(report[0]["name"]).To(Equal("/certs/expired"))
+			Expect(report[0]["self_signed"]).To(BeTrue())
+			Expect(report[0]["days_to_expiry"]).To(BeEquivalentTo(-3))
+			Expect(report[1]["name"]).To(Equal("/certs/soon"))
+			Expect(report[1]["ca_name"]).To(Equal("/certs/ca"))
+			Expect(report[1]["subject"]).To(Equal("CN=soon.example.com"))
+		})
+
+		It("succeeds when nothing expires within the window", func() {
+			delete(credentials, "/certs/soon")
+			delete(credentials, "/certs/expired")
+
+			output, err := runExpiring("--within", "30d", "-o", "json", "/certs")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(Equal("[]\n"))
+		})
+
+		It("accepts Go durations for '--within'", func() {
+			output, err := runExpiring("--within", "2500h", "/certs")
+			Expect(err).To(MatchError("3 certificate(s) expire within 2500h"))
+			Expect(output).To(ContainSubstring("/certs/later"))
+		})
+
+		It("checks a single certificate", func() {
+			_, err := runExpiring("/certs/later")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when a certificate cannot be parsed", func() {
+			It("reports the failure and returns an error", func() {
+				credentials["/certs/invalid"] = credhub.Credential{Type: "certificate", Value: credhub.Certificate{Certificate: "not-a-certificate"}}
+				delete(credentials, "/certs/soon")
+				delete(credentials, "/certs/expired")
+
+				output, err := runExpiring("/certs")
+				Expect(err).To(MatchError("failed to check 1 of 3 credentials"))
+				Expect(output).To(ContainSubstring("failed to check /certs/invalid: failed to decode certificate PEM\n"))
+			})
+		})
+
+		Context("when the path does not exist", func() {
+			It("returns an error", func() {
+				_, err := runExpiring("/missing")
+				Expect(err).To(MatchError("'/missing': no such credential or path"))
+			})
+		})
+
+		Context("when finding credentials fails", func() {
+			It("returns an error", func() {
+				fakeCredhubClient.FindCredentialsByPathStub = nil
+				fakeCredhubClient.FindCredentialsByPathReturns(nil, errors.New("some-error"))
+
+				_, err := runExpiring("/certs")
+				Expect(err).To(MatchError("failed to find credentials: some-error"))
+			})
+		})
+
+		Context("when the arguments are invalid", func() {
+			It("returns an error", func() {
+				_, err := runExpiring("--within", "soon", "/certs")
+				Expect(err).To(MatchError("invalid value 'soon' for '--within'"))
+
+				_, err = runExpiring("-o", "yaml", "/certs")
+				Expect(err).To(MatchError("invalid output format 'yaml': must be 'table' or 'json'"))
+			})
+		})
+	})
+})
@@ -1,52 +1,113 @@
 package cmd
 
 import (
-	"crypto/tls"
+	"context"
+	"errors"
 	"fmt"
-	"net"
-	"net/http"
 	"os"
-	"time"
 
 	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/cat"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/certs"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/cp"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/find"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/generate"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/getfacl"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/grep"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/history"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/login"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/logout"
 	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/ls"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/mount"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/mv"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/regenerate"
 	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/rm"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/rollback"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/servewebdav"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/setfacl"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/stat"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/target"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/tree"
 	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/write"
+	"github.com/mdelillo/credhub-fs/pkg/config"
 	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/pkg/tokencache"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
-func NewCfsCommand() *cobra.Command {
+// NewCfsCommand returns the root cfs command. Subcommands stop making
+// requests to CredHub and UAA once ctx is cancelled.
+func NewCfsCommand(ctx context.Context) *cobra.Command {
 	dependencies := cmdutil.NewDependencies()
+	dependencies.SetContext(ctx)
+	if tokenCachePath, err := tokencache.DefaultPath(); err == nil {
+		dependencies.SetTokenCache(tokencache.New(tokenCachePath))
+	}
+	if configPath, err := config.DefaultPath(); err == nil {
+		dependencies.SetConfigPath(configPath)
+	}
+
 	cmd := &cobra.Command{
 		Use:   "cfs",
 		Short: "cfs interacts with CredHub using Unix filesystem commands",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			requiredFlags := []string{"credhub-addr", "client-id", "client-secret"}
+			resolved, err := resolveTarget(cmd, dependencies.GetConfigPath())
+			if err != nil {
+				fmt.Printf("Failed to load target: %s\n", err.Error())
+				os.Exit(1)
+			}
+
+			requiredFlags, authenticator := authentication(resolved)
+			requiredFlags = append([]requiredFlag{{"credhub-addr", resolved.CredhubAddr}}, requiredFlags...)
 			for _, flag := range requiredFlags {
-				if viper.GetString(flag) == "" {
-					fmt.Printf("Must provide `%s`\n", flag)
+				if flag.value == "" {
+					fmt.Printf("Must provide `%s`\n", flag.name)
 					cmd.Usage()
 					os.Exit(1)
 				}
 			}
 
-			httpClient := &http.Client{
-				Timeout: 5 * time.Second,
-				Transport: &http.Transport{
-					TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
-					Dial:                (&net.Dialer{Timeout: 5 * time.Second}).Dial,
-					TLSHandshakeTimeout: 5 * time.Second,
-				},
+			httpClient, err := credhub.NewHTTPClient(resolved.CACerts, resolved.SkipTLSValidation)
+			if err != nil {
+				fmt.Printf("Failed to configure TLS: %s\n", err.Error())
+				os.Exit(1)
+			}
+
+			options, err := clientOptions()
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+
+			if authenticator == nil {
+				credhubClient, err := credhub.NewClientWithCertificate(
+					resolved.CredhubAddr,
+					resolved.ClientCert,
+					resolved.ClientKey,
+					httpClient,
+					options...,
+				)
+				if err != nil {
+					fmt.Printf("Failed to load client certificate: %s\n", err.Error())
+					os.Exit(1)
+				}
+				dependencies.SetCredhubClient(credhubClient)
+				return
+			}
+
+			var tokenStore credhub.TokenStore
+			if tokenCache := dependencies.GetTokenCache(); tokenCache != nil {
+				tokenStore = tokenCache
 			}
 
 			dependencies.SetCredhubClient(
 				credhub.NewClient(
-					viper.GetString("credhub-addr"),
-					viper.GetString("client-id"),
-					viper.GetString("client-secret"),
+					resolved.CredhubAddr,
+					authenticator,
 					httpClient,
+					tokenStore,
+					options...,
 				),
 			)
 		},
@@ -58,14 +119,194 @@ func NewCfsCommand() *cobra.Command {
 	cmd.PersistentFlags().String("credhub-addr", "", "address of CredHub server [$CREDHUB_ADDR]")
 	cmd.PersistentFlags().String("client-id", "", "UAA client ID [$CLIENT_ID]")
 	cmd.PersistentFlags().String("client-secret", "", "UAA client secret [$CLIENT_SECRET]")
+	cmd.PersistentFlags().String("username", "", "UAA user to log in as instead of a UAA client [$CREDHUB_USERNAME]")
+	cmd.PersistentFlags().String("password", "", "password of the UAA user [$CREDHUB_PASSWORD]")
+	cmd.PersistentFlags().String("refresh-token", "", "UAA refresh token to obtain access tokens with [$CREDHUB_REFRESH_TOKEN]")
+	cmd.PersistentFlags().String("token", "", "access token to send to CredHub as is [$CREDHUB_TOKEN]")
+	cmd.PersistentFlags().String("client-cert", "", "client certificate to authenticate with instead of a UAA client, as a path or PEM [$CF_INSTANCE_CERT]")
+	cmd.PersistentFlags().String("client-key", "", "private key of the client certificate, as a path or PEM [$CF_INSTANCE_KEY]")
+	cmd.PersistentFlags().StringArray("ca-cert", nil, "CA certificate to trust, as a path or PEM; can be repeated [$CREDHUB_CA_CERT]")
+	cmd.PersistentFlags().Bool("skip-tls-validation", false, "do not verify the certificates of CredHub and UAA")
+	cmd.PersistentFlags().Int("retries", credhub.DefaultRetryPolicy().MaxRetries, "number of times to retry requests which fail with a transient error [$CFS_RETRIES]")
+	cmd.PersistentFlags().Float64("rate-limit", 0, "maximum number of requests per second to send to CredHub and UAA, or 0 for no limit [$CFS_RATE_LIMIT]")
+	cmd.PersistentFlags().String("target", "", "name of a target in ~/.cfs/config.yml to use instead of the current target [$CFS_TARGET]")
 	viper.BindEnv("credhub-addr", "CREDHUB_ADDR")
 	viper.BindEnv("client-id", "CLIENT_ID")
 	viper.BindEnv("client-secret", "CLIENT_SECRET")
+	viper.BindEnv("username", "CREDHUB_USERNAME")
+	viper.BindEnv("password", "CREDHUB_PASSWORD")
+	viper.BindEnv("refresh-token", "CREDHUB_REFRESH_TOKEN")
+	viper.BindEnv("token", "CREDHUB_TOKEN")
+	viper.BindEnv("client-cert", "CF_INSTANCE_CERT")
+	viper.BindEnv("client-key", "CF_INSTANCE_KEY")
+	viper.BindEnv("retries", "CFS_RETRIES")
+	viper.BindEnv("rate-limit", "CFS_RATE_LIMIT")
+	viper.BindEnv("target", "CFS_TARGET")
 	viper.BindPFlags(cmd.PersistentFlags())
 
 	cmd.AddCommand(cat.NewCmdCat(dependencies))
+	cmd.AddCommand(certs.NewCmdCerts(dependencies))
+	cmd.AddCommand(cp.NewCmdCp(dependencies))
+	cmd.AddCommand(find.NewCmdFind(dependencies))
+	cmd.AddCommand(generate.NewCmdGenerate(dependencies))
+	cmd.AddCommand(getfacl.NewCmdGetfacl(dependencies))
+	cmd.AddCommand(grep.NewCmdGrep(dependencies))
+	cmd.AddCommand(history.NewCmdHistory(dependencies))
+	cmd.AddCommand(login.NewCmdLogin(dependencies))
+	cmd.AddCommand(logout.NewCmdLogout(dependencies))
 	cmd.AddCommand(ls.NewCmdLs(dependencies))
+	cmd.AddCommand(mount.NewCmdMount(dependencies))
+	cmd.AddCommand(mv.NewCmdMv(dependencies))
+	cmd.AddCommand(regenerate.NewCmdRegenerate(dependencies))
 	cmd.AddCommand(rm.NewCmdRm(dependencies))
+	cmd.AddCommand(rollback.NewCmdRollback(dependencies))
+	cmd.AddCommand(servewebdav.NewCmdServeWebdav(dependencies))
+	cmd.AddCommand(setfacl.NewCmdSetfacl(dependencies))
+	cmd.AddCommand(stat.NewCmdStat(dependencies))
+	cmd.AddCommand(target.NewCmdTarget(dependencies))
+	cmd.AddCommand(write.NewCmdTee(dependencies))
+	cmd.AddCommand(tree.NewCmdTree(dependencies))
+	cmd.AddCommand(write.NewCmdWrite(dependencies))
 
 	return cmd
 }
+
+type requiredFlag struct {
+	name  string
+	value string
+}
+
+// authentication decides how to authenticate with CredHub, returning the flags
+// which that requires and the authenticator to obtain access tokens with, which
+// is nil when a client certificate is used instead. An access token is used in
+// preference to a refresh token, then a UAA user, then a client certificate
+// and finally a UAA client.
+func authentication(resolved config.Target) ([]requiredFlag, credhub.Authenticator) {
+	token := viper.GetString("token")
+	refreshToken := viper.GetString("refresh-token")
+	username := viper.GetString("username")
+	password := viper.GetString("password")
+
+	switch {
+	case token != "":
+		return nil, credhub.NewStaticTokenAuthenticator(token)
+	case refreshToken != "":
+		return nil, credhub.NewRefreshTokenAuthenticator(refreshToken)
+	case username != "" || password != "":
+		return []requiredFlag{{"username", username}, {"password", password}},
+			credhub.NewPasswordAuthenticator(username, password)
+	case resolved.ClientCert != "" || resolved.ClientKey != "":
+		return []requiredFlag{{"client-cert", resolved.ClientCert}, {"client-key", resolved.ClientKey}}, nil
+	default:
+		return []requiredFlag{{"client-id", resolved.ClientID}, {"client-secret", resolved.ClientSecret}},
+			credhub.NewClientCredentialsAuthenticator(resolved.ClientID, resolved.ClientSecret)
+	}
+}
+
+// clientOptions returns the options which configure how the CredHub client
+// retries and rate limits its requests.
+func clientOptions() ([]credhub.ClientOption, error) {
+	retries := viper.GetInt("retries")
+	if retries < 0 {
+		return nil, errors.New("`retries` must not be negative")
+	}
+	rateLimit := viper.GetFloat64("rate-limit")
+	if rateLimit < 0 {
+		return nil, errors.New("`rate-limit` must not be negative")
+	}
+
+	policy := credhub.DefaultRetryPolicy()
+	policy.MaxRetries = retries
+	options := []credhub.ClientOption{credhub.WithRetryPolicy(policy)}
+	if rateLimit > 0 {
+		options = append(options, credhub.WithRateLimiter(credhub.NewRateLimiter(rateLimit, 1)))
+	}
+	return options, nil
+}
+
+// resolveTarget returns the CredHub to connect to and the UAA client or client
+// certificate to authenticate with. They come from a target in the config file
+// when one is named with '--target', or when no CredHub address is given and
+// there is a current target. Flags given on the command line override the
+// target's values.
+func resolveTarget(cmd *cobra.Command, configPath string) (config.Target, error) {
+	caCerts, _ := cmd.Flags().GetStringArray("ca-cert")
+	if len(caCerts) == 0 && os.Getenv("CREDHUB_CA_CERT") != "" {
+		caCerts = []string{os.Getenv("CREDHUB_CA_CERT")}
+	}
+	skipTLSValidation, _ := cmd.Flags().GetBool("skip-tls-validation")
+
+	resolved := config.Target{
+		CredhubAddr:       viper.GetString("credhub-addr"),
+		ClientID:          viper.GetString("client-id"),
+		ClientSecret:      viper.GetString("client-secret"),
+		ClientCert:        viper.GetString("client-cert"),
+		ClientKey:         viper.GetString("client-key"),
+		CACerts:           caCerts,
+		SkipTLSValidation: skipTLSValidation,
+	}
+
+	// Every Cloud Foundry app container has an instance identity certificate
+	// in CF_INSTANCE_CERT, so a UAA client given explicitly takes precedence
+	// over it. A certificate given with '--client-cert' always wins.
+	if resolved.ClientID != "" && !cmd.Flags().Changed("client-cert") {
+		resolved.ClientCert = ""
+		resolved.ClientKey = ""
+	}
+
+	targetName := viper.GetString("target")
+	if targetName == "" && resolved.CredhubAddr != "" {
+		return resolved, nil
+	}
+	if configPath == "" {
+		if targetName != "" {
+			return config.Target{}, errors.New("could not determine the location of the config file")
+		}
+		return resolved, nil
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return config.Target{}, err
+	}
+	if targetName == "" {
+		targetName = cfg.CurrentTarget
+	}
+	if targetName == "" {
+		return resolved, nil
+	}
+
+	t, err := cfg.GetTarget(targetName)
+	if err != nil {
+		return config.Target{}, err
+	}
+	resolved.Name = t.Name
+
+	if !cmd.Flags().Changed("credhub-addr") {
+		resolved.CredhubAddr = t.CredhubAddr
+	}
+	if !cmd.Flags().Changed("client-id") {
+		resolved.ClientID = t.ClientID
+	}
+	if !cmd.Flags().Changed("client-cert") {
+		resolved.ClientCert = t.ClientCert
+	}
+	if !cmd.Flags().Changed("client-key") {
+		resolved.ClientKey = t.ClientKey
+	}
+	if !cmd.Flags().Changed("client-secret") && resolved.ClientCert == "" {
+		secret, err := t.Secret()
+		if err != nil {
+			return config.Target{}, fmt.Errorf("failed to get client secret for target %s: %s", t.Name, err.Error())
+		}
+		resolved.ClientSecret = secret
+	}
+	if !cmd.Flags().Changed("ca-cert") && len(t.CACerts) > 0 {
+		resolved.CACerts = t.CACerts
+	}
+	if !cmd.Flags().Changed("skip-tls-validation") {
+		resolved.SkipTLSValidation = t.SkipTLSValidation
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,106 @@
+package cp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/pkg/transfer"
+	"github.com/spf13/cobra"
+)
+
+type cmdCpRunner struct {
+	ctx           context.Context
+	credhubClient credhubClient
+	recursive     bool
+	noClobber     bool
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+func NewCmdCp(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cp SOURCE DESTINATION",
+		Short: "Copy credentials",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("must provide a source and destination path")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			recursive, _ := cmd.Flags().GetBool("recursive")
+			noClobber, _ := cmd.Flags().GetBool("no-clobber")
+
+			cmd.SilenceUsage = true
+
+			c := &cmdCpRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+				recursive:     recursive,
+				noClobber:     noClobber,
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	cmd.Flags().BoolP("recursive", "r", false, "recursively copy credentials")
+	cmd.Flags().BoolP("no-clobber", "n", false, "do not overwrite existing credentials")
+
+	return cmd
+}
+
+func (c *cmdCpRunner) Run(cmd *cobra.Command, args []string) error {
+	transfers, sourceIsDir, err := transfer.Plan(c.ctx, c.credhubClient, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	if sourceIsDir && !c.recursive {
+		return errors.New("not copying recursively without '-r' flag")
+	}
+
+	failures := 0
+	for i, transfer := range transfers {
+		if err := c.copyCredential(transfer); err != nil {
+			if interrupted := cmdutil.Interrupted(c.ctx, fmt.Sprintf("copying %d of %d credentials", i-failures, len(transfers))); interrupted != nil {
+				return interrupted
+			}
+			fmt.Fprintf(cmd.OutOrStderr(), "failed to copy %s to %s: %s\n", transfer.Source.Name, transfer.Destination, err.Error())
+			failures++
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("failed to copy %d of %d credentials", failures, len(transfers))
+	}
+	return nil
+}
+
+func (c *cmdCpRunner) copyCredential(transfer transfer.Transfer) error {
+	if c.noClobber {
+		_, err := c.credhubClient.GetCredentialByName(c.ctx, transfer.Destination)
+		if err == nil {
+			return nil
+		}
+		if _, isNotFoundError := err.(*credhub.ErrCredentialNotFound); !isNotFoundError {
+			return fmt.Errorf("failed to get credential: %s", err.Error())
+		}
+	}
+
+	source := transfer.Source
+	if source.Value == nil {
+		var err error
+		source, err = c.credhubClient.GetCredentialByName(c.ctx, source.Name)
+		if err != nil {
+			return fmt.Errorf("failed to get credential: %s", err.Error())
+		}
+	}
+
+	if _, err := c.credhubClient.SetCredential(c.ctx, credhub.Credential{Name: transfer.Destination, Value: source.Value}); err != nil {
+		return fmt.Errorf("failed to set credential: %s", err.Error())
+	}
+	return nil
+}
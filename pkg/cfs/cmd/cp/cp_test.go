@@ -0,0 +1,252 @@
+package cp_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/cp"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/cp/cpfakes"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+var _ = Describe("Cp", func() {
+	var (
+		fakeCredhubClient *cpfakes.FakeCredhubClient
+		dependencies      cmdutil.Dependencies
+		storedCredentials map[string]credhub.Credential
+		setCredentials    func() map[string]credhub.CredentialValue
+	)
+
+	BeforeEach(func() {
+		fakeCredhubClient = &cpfakes.FakeCredhubClient{}
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetCredhubClient(fakeCredhubClient)
+
+		storedCredentials = map[string]credhub.Credential{}
+		fakeCredhubClient.GetCredentialByNameStub = func(_ context.Context, name string) (credhub.Credential, error) {
+			credential, found := storedCredentials[name]
+			if !found {
+				return credhub.Credential{}, &credhub.ErrCredentialNotFound{}
+			}
+			return credential, nil
+		}
+		fakeCredhubClient.FindCredentialsByPathStub = func(_ context.Context, path string) ([]credhub.Credential, error) {
+			var credentials []credhub.Credential
+			for name := range storedCredentials {
+				if strings.HasPrefix(name, strings.TrimSuffix(path, "/")+"/") {
+					credentials = append(credentials, credhub.Credential{Name: name})
+				}
+			}
+			return credentials, nil
+		}
+
+		setCredentials = func() map[string]credhub.CredentialValue {
+			set := map[string]credhub.CredentialValue{}
+			for i := 0; i < fakeCredhubClient.SetCredentialCallCount(); i++ {
+				_, credential := fakeCredhubClient.SetCredentialArgsForCall(i)
+				set[credential.Name] = credential.Value
+			}
+			return set
+		}
+	})
+
+	It("copies a credential, preserving its type", func() {
+		storedCredentials["/some/cred"] = credhub.Credential{
+			Name:  "/some/cred",
+			Value: credhub.Password("some-password"),
+		}
+
+		cmd := cp.NewCmdCp(dependencies)
+		cmd.SetArgs([]string{"/some/cred", "/other/cred"})
+		cmd.SetOutput(ioutil.Discard)
+
+		Expect(cmd.Execute()).To(Succeed())
+
+		Expect(setCredentials()).To(Equal(map[string]credhub.CredentialValue{
+			"/other/cred": credhub.Password("some-password"),
+		}))
+	})
+
+	It("copies a credential into an existing directory", func() {
+		storedCredentials["/some/cred"] = credhub.Credential{Name: "/some/cred", Value: credhub.Value("some-value")}
+		storedCredentials["/other/existing"] = credhub.Credential{Name: "/other/existing", Value: credhub.Value("some-value")}
+
+		cmd := cp.NewCmdCp(dependencies)
+		cmd.SetArgs([]string{"/some/cred", "/other"})
+		cmd.SetOutput(ioutil.Discard)
+
+		Expect(cmd.Execute()).To(Succeed())
+
+		Expect(setCredentials()).To(HaveKey("/other/cred"))
+	})
+
+	Context("when the source is a directory", func() {
+		BeforeEach(func() {
+			storedCredentials["/dep-a/cred1"] = credhub.Credential{Name: "/dep-a/cred1", Value: credhub.Value("value1")}
+			storedCredentials["/dep-a/nested/cred2"] = credhub.Credential{Name: "/dep-a/nested/cred2", Value: credhub.Value("value2")}
+		})
+
+		It("copies the directory when `recursive` is true", func() {
+			cmd := cp.NewCmdCp(dependencies)
+			cmd.SetArgs([]string{"-r", "/dep-a", "/dep-b"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(Succeed())
+
+			Expect(setCredentials()).To(Equal(map[string]credhub.CredentialValue{
+				"/dep-b/cred1":        credhub.Value("value1"),
+				"/dep-b/nested/cred2": credhub.Value("value2"),
+			}))
+		})
+
+		It("copies the directory into an existing directory", func() {
+			storedCredentials["/existing/cred"] = credhub.Credential{Name: "/existing/cred", Value: credhub.Value("some-value")}
+
+			cmd := cp.NewCmdCp(dependencies)
+			cmd.SetArgs([]string{"-r", "/dep-a", "/existing"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(Succeed())
+
+			Expect(setCredentials()).To(Equal(map[string]credhub.CredentialValue{
+				"/existing/dep-a/cred1":        credhub.Value("value1"),
+				"/existing/dep-a/nested/cred2": credhub.Value("value2"),
+			}))
+		})
+
+		It("copies the contents of the directory when the source ends in '/*'", func() {
+			storedCredentials["/existing/cred"] = credhub.Credential{Name: "/existing/cred", Value: credhub.Value("some-value")}
+
+			cmd := cp.NewCmdCp(dependencies)
+			cmd.SetArgs([]string{"-r", "/dep-a/*", "/existing/"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(Succeed())
+
+			Expect(setCredentials()).To(Equal(map[string]credhub.CredentialValue{
+				"/existing/cred1":        credhub.Value("value1"),
+				"/existing/nested/cred2": credhub.Value("value2"),
+			}))
+		})
+
+		It("returns an error when `recursive` is false", func() {
+			cmd := cp.NewCmdCp(dependencies)
+			cmd.SetArgs([]string{"/dep-a", "/dep-b"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("not copying recursively without '-r' flag"))
+			Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(0))
+		})
+
+		It("skips existing credentials when `no-clobber` is true", func() {
+			storedCredentials["/dep-b/cred1"] = credhub.Credential{Name: "/dep-b/cred1", Value: credhub.Value("existing")}
+
+			cmd := cp.NewCmdCp(dependencies)
+			cmd.SetArgs([]string{"-r", "-n", "/dep-a/*", "/dep-b"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(Succeed())
+
+			Expect(setCredentials()).To(Equal(map[string]credhub.CredentialValue{
+				"/dep-b/nested/cred2": credhub.Value("value2"),
+			}))
+		})
+
+		It("reports failures and continues copying", func() {
+			fakeCredhubClient.SetCredentialStub = func(_ context.Context, credential credhub.Credential) (credhub.Credential, error) {
+				if credential.Name == "/dep-b/cred1" {
+					return credhub.Credential{}, errors.New("some-error")
+				}
+				return credential, nil
+			}
+
+			var output bytes.Buffer
+			cmd := cp.NewCmdCp(dependencies)
+			cmd.SetArgs([]string{"-r", "/dep-a", "/dep-b"})
+			cmd.SetOutput(&output)
+
+			Expect(cmd.Execute()).To(MatchError("failed to copy 1 of 2 credentials"))
+
+			Expect(output.String()).To(ContainSubstring("failed to copy /dep-a/cred1 to /dep-b/cred1: failed to set credential: some-error"))
+			Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(2))
+		})
+
+		It("stops and reports how many credentials were copied when interrupted", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			dependencies.SetContext(ctx)
+
+			calls := 0
+			fakeCredhubClient.SetCredentialStub = func(ctx context.Context, credential credhub.Credential) (credhub.Credential, error) {
+				calls++
+				if calls == 2 {
+					cancel()
+				}
+				return credential, ctx.Err()
+			}
+
+			var output bytes.Buffer
+			cmd := cp.NewCmdCp(dependencies)
+			cmd.SetArgs([]string{"-r", "/dep-a", "/dep-b"})
+			cmd.SetOutput(&output)
+
+			Expect(cmd.Execute()).To(MatchError("interrupted after copying 1 of 2 credentials"))
+			Expect(output.String()).NotTo(ContainSubstring("failed to copy"))
+		})
+	})
+
+	Context("when the source does not exist", func() {
+		It("returns an error", func() {
+			cmd := cp.NewCmdCp(dependencies)
+			cmd.SetArgs([]string{"/some-path", "/other-path"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("'/some-path': no such credential or path"))
+			Expect(cmd.SilenceUsage).To(BeTrue())
+		})
+	})
+
+	Context("when getting the source credential fails", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.GetCredentialByNameStub = nil
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{}, errors.New("some-error"))
+
+			cmd := cp.NewCmdCp(dependencies)
+			cmd.SetArgs([]string{"/some-path", "/other-path"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("failed to get credential: some-error"))
+		})
+	})
+
+	Context("when finding credentials fails", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.FindCredentialsByPathStub = nil
+			fakeCredhubClient.FindCredentialsByPathReturns(nil, errors.New("some-error"))
+
+			cmd := cp.NewCmdCp(dependencies)
+			cmd.SetArgs([]string{"/some-path", "/other-path"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("failed to find credentials: some-error"))
+		})
+	})
+
+	Context("when the wrong number of arguments are provided", func() {
+		It("returns an error and shows the usage", func() {
+			cmd := cp.NewCmdCp(dependencies)
+			cmd.SetArgs([]string{"/some-path"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("must provide a source and destination path"))
+			Expect(cmd.SilenceUsage).To(BeFalse())
+		})
+	})
+})
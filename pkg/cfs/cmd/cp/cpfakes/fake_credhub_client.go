@@ -0,0 +1,1228 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package cpfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+type FakeCredhubClient struct {
+	AuthenticateStub        func(context.Context) error
+	authenticateMutex       sync.RWMutex
+	authenticateArgsForCall []struct {
+		arg1 context.Context
+	}
+	authenticateReturns struct {
+		result1 error
+	}
+	authenticateReturnsOnCall map[int]struct {
+		result1 error
+	}
+	BulkRegenerateStub        func(context.Context, string) ([]string, error)
+	bulkRegenerateMutex       sync.RWMutex
+	bulkRegenerateArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	bulkRegenerateReturns struct {
+		result1 []string
+		result2 error
+	}
+	bulkRegenerateReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	CreatePermissionStub        func(context.Context, credhub.Permission) (credhub.Permission, error)
+	createPermissionMutex       sync.RWMutex
+	createPermissionArgsForCall []struct {
+		arg1 context.Context
+		arg2 credhub.Permission
+	}
+	createPermissionReturns struct {
+		result1 credhub.Permission
+		result2 error
+	}
+	createPermissionReturnsOnCall map[int]struct {
+		result1 credhub.Permission
+		result2 error
+	}
+	DeleteCredentialByNameStub        func(context.Context, string) error
+	deleteCredentialByNameMutex       sync.RWMutex
+	deleteCredentialByNameArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	deleteCredentialByNameReturns struct {
+		result1 error
+	}
+	deleteCredentialByNameReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeletePermissionStub        func(context.Context, uuid.UUID) error
+	deletePermissionMutex       sync.RWMutex
+	deletePermissionArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}
+	deletePermissionReturns struct {
+		result1 error
+	}
+	deletePermissionReturnsOnCall map[int]struct {
+		result1 error
+	}
+	FindCredentialsByPathStub        func(context.Context, string) ([]credhub.Credential, error)
+	findCredentialsByPathMutex       sync.RWMutex
+	findCredentialsByPathArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	findCredentialsByPathReturns struct {
+		result1 []credhub.Credential
+		result2 error
+	}
+	findCredentialsByPathReturnsOnCall map[int]struct {
+		result1 []credhub.Credential
+		result2 error
+	}
+	GenerateCredentialStub        func(context.Context, string, credhub.GenerateParameters, credhub.GenerateMode) (credhub.Credential, error)
+	generateCredentialMutex       sync.RWMutex
+	generateCredentialArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 credhub.GenerateParameters
+		arg4 credhub.GenerateMode
+	}
+	generateCredentialReturns struct {
+		result1 credhub.Credential
+		result2 error
+	}
+	generateCredentialReturnsOnCall map[int]struct {
+		result1 credhub.Credential
+		result2 error
+	}
+	GetCredentialByIDStub        func(context.Context, uuid.UUID) (credhub.Credential, error)
+	getCredentialByIDMutex       sync.RWMutex
+	getCredentialByIDArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}
+	getCredentialByIDReturns struct {
+		result1 credhub.Credential
+		result2 error
+	}
+	getCredentialByIDReturnsOnCall map[int]struct {
+		result1 credhub.Credential
+		result2 error
+	}
+	GetCredentialByNameStub        func(context.Context, string) (credhub.Credential, error)
+	getCredentialByNameMutex       sync.RWMutex
+	getCredentialByNameArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getCredentialByNameReturns struct {
+		result1 credhub.Credential
+		result2 error
+	}
+	getCredentialByNameReturnsOnCall map[int]struct {
+		result1 credhub.Credential
+		result2 error
+	}
+	GetCredentialVersionsStub        func(context.Context, string, int) ([]credhub.Credential, error)
+	getCredentialVersionsMutex       sync.RWMutex
+	getCredentialVersionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}
+	getCredentialVersionsReturns struct {
+		result1 []credhub.Credential
+		result2 error
+	}
+	getCredentialVersionsReturnsOnCall map[int]struct {
+		result1 []credhub.Credential
+		result2 error
+	}
+	GetPermissionStub        func(context.Context, string, string) (credhub.Permission, error)
+	getPermissionMutex       sync.RWMutex
+	getPermissionArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	getPermissionReturns struct {
+		result1 credhub.Permission
+		result2 error
+	}
+	getPermissionReturnsOnCall map[int]struct {
+		result1 credhub.Permission
+		result2 error
+	}
+	GetPermissionsStub        func(context.Context, string) ([]credhub.Permission, error)
+	getPermissionsMutex       sync.RWMutex
+	getPermissionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getPermissionsReturns struct {
+		result1 []credhub.Permission
+		result2 error
+	}
+	getPermissionsReturnsOnCall map[int]struct {
+		result1 []credhub.Permission
+		result2 error
+	}
+	RegenerateCredentialStub        func(context.Context, string) (credhub.Credential, error)
+	regenerateCredentialMutex       sync.RWMutex
+	regenerateCredentialArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	regenerateCredentialReturns struct {
+		result1 credhub.Credential
+		result2 error
+	}
+	regenerateCredentialReturnsOnCall map[int]struct {
+		result1 credhub.Credential
+		result2 error
+	}
+	SetCredentialStub        func(context.Context, credhub.Credential) (credhub.Credential, error)
+	setCredentialMutex       sync.RWMutex
+	setCredentialArgsForCall []struct {
+		arg1 context.Context
+		arg2 credhub.Credential
+	}
+	setCredentialReturns struct {
+		result1 credhub.Credential
+		result2 error
+	}
+	setCredentialReturnsOnCall map[int]struct {
+		result1 credhub.Credential
+		result2 error
+	}
+	UpdatePermissionStub        func(context.Context, credhub.Permission) (credhub.Permission, error)
+	updatePermissionMutex       sync.RWMutex
+	updatePermissionArgsForCall []struct {
+		arg1 context.Context
+		arg2 credhub.Permission
+	}
+	updatePermissionReturns struct {
+		result1 credhub.Permission
+		result2 error
+	}
+	updatePermissionReturnsOnCall map[int]struct {
+		result1 credhub.Permission
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCredhubClient) Authenticate(arg1 context.Context) error {
+	fake.authenticateMutex.Lock()
+	ret, specificReturn := fake.authenticateReturnsOnCall[len(fake.authenticateArgsForCall)]
+	fake.authenticateArgsForCall = append(fake.authenticateArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("Authenticate", []interface{}{arg1})
+	fake.authenticateMutex.Unlock()
+	if fake.AuthenticateStub != nil {
+		return fake.AuthenticateStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.authenticateReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeCredhubClient) AuthenticateCallCount() int {
+	fake.authenticateMutex.RLock()
+	defer fake.authenticateMutex.RUnlock()
+	return len(fake.authenticateArgsForCall)
+}
+
+func (fake *FakeCredhubClient) AuthenticateCalls(stub func(context.Context) error) {
+	fake.authenticateMutex.Lock()
+	defer fake.authenticateMutex.Unlock()
+	fake.AuthenticateStub = stub
+}
+
+func (fake *FakeCredhubClient) AuthenticateArgsForCall(i int) context.Context {
+	fake.authenticateMutex.RLock()
+	defer fake.authenticateMutex.RUnlock()
+	argsForCall := fake.authenticateArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeCredhubClient) AuthenticateReturns(result1 error) {
+	fake.authenticateMutex.Lock()
+	defer fake.authenticateMutex.Unlock()
+	fake.AuthenticateStub = nil
+	fake.authenticateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCredhubClient) AuthenticateReturnsOnCall(i int, result1 error) {
+	fake.authenticateMutex.Lock()
+	defer fake.authenticateMutex.Unlock()
+	fake.AuthenticateStub = nil
+	if fake.authenticateReturnsOnCall == nil {
+		fake.authenticateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.authenticateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCredhubClient) BulkRegenerate(arg1 context.Context, arg2 string) ([]string, error) {
+	fake.bulkRegenerateMutex.Lock()
+	ret, specificReturn := fake.bulkRegenerateReturnsOnCall[len(fake.bulkRegenerateArgsForCall)]
+	fake.bulkRegenerateArgsForCall = append(fake.bulkRegenerateArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("BulkRegenerate", []interface{}{arg1, arg2})
+	fake.bulkRegenerateMutex.Unlock()
+	if fake.BulkRegenerateStub != nil {
+		return fake.BulkRegenerateStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.bulkRegenerateReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCredhubClient) BulkRegenerateCallCount() int {
+	fake.bulkRegenerateMutex.RLock()
+	defer fake.bulkRegenerateMutex.RUnlock()
+	return len(fake.bulkRegenerateArgsForCall)
+}
+
+func (fake *FakeCredhubClient) BulkRegenerateCalls(stub func(context.Context, string) ([]string, error)) {
+	fake.bulkRegenerateMutex.Lock()
+	defer fake.bulkRegenerateMutex.Unlock()
+	fake.BulkRegenerateStub = stub
+}
+
+func (fake *FakeCredhubClient) BulkRegenerateArgsForCall(i int) (context.Context, string) {
+	fake.bulkRegenerateMutex.RLock()
+	defer fake.bulkRegenerateMutex.RUnlock()
+	argsForCall := fake.bulkRegenerateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCredhubClient) BulkRegenerateReturns(result1 []string, result2 error) {
+	fake.bulkRegenerateMutex.Lock()
+	defer fake.bulkRegenerateMutex.Unlock()
+	fake.BulkRegenerateStub = nil
+	fake.bulkRegenerateReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) BulkRegenerateReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.bulkRegenerateMutex.Lock()
+	defer fake.bulkRegenerateMutex.Unlock()
+	fake.BulkRegenerateStub = nil
+	if fake.bulkRegenerateReturnsOnCall == nil {
+		fake.bulkRegenerateReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.bulkRegenerateReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) CreatePermission(arg1 context.Context, arg2 credhub.Permission) (credhub.Permission, error) {
+	fake.createPermissionMutex.Lock()
+	ret, specificReturn := fake.createPermissionReturnsOnCall[len(fake.createPermissionArgsForCall)]
+	fake.createPermissionArgsForCall = append(fake.createPermissionArgsForCall, struct {
+		arg1 context.Context
+		arg2 credhub.Permission
+	}{arg1, arg2})
+	fake.recordInvocation("CreatePermission", []interface{}{arg1, arg2})
+	fake.createPermissionMutex.Unlock()
+	if fake.CreatePermissionStub != nil {
+		return fake.CreatePermissionStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.createPermissionReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCredhubClient) CreatePermissionCallCount() int {
+	fake.createPermissionMutex.RLock()
+	defer fake.createPermissionMutex.RUnlock()
+	return len(fake.createPermissionArgsForCall)
+}
+
+func (fake *FakeCredhubClient) CreatePermissionCalls(stub func(context.Context, credhub.Permission) (credhub.Permission, error)) {
+	fake.createPermissionMutex.Lock()
+	defer fake.createPermissionMutex.Unlock()
+	fake.CreatePermissionStub = stub
+}
+
+func (fake *FakeCredhubClient) CreatePermissionArgsForCall(i int) (context.Context, credhub.Permission) {
+	fake.createPermissionMutex.RLock()
+	defer fake.createPermissionMutex.RUnlock()
+	argsForCall := fake.createPermissionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCredhubClient) CreatePermissionReturns(result1 credhub.Permission, result2 error) {
+	fake.createPermissionMutex.Lock()
+	defer fake.createPermissionMutex.Unlock()
+	fake.CreatePermissionStub = nil
+	fake.createPermissionReturns = struct {
+		result1 credhub.Permission
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) CreatePermissionReturnsOnCall(i int, result1 credhub.Permission, result2 error) {
+	fake.createPermissionMutex.Lock()
+	defer fake.createPermissionMutex.Unlock()
+	fake.CreatePermissionStub = nil
+	if fake.createPermissionReturnsOnCall == nil {
+		fake.createPermissionReturnsOnCall = make(map[int]struct {
+			result1 credhub.Permission
+			result2 error
+		})
+	}
+	fake.createPermissionReturnsOnCall[i] = struct {
+		result1 credhub.Permission
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) DeleteCredentialByName(arg1 context.Context, arg2 string) error {
+	fake.deleteCredentialByNameMutex.Lock()
+	ret, specificReturn := fake.deleteCredentialByNameReturnsOnCall[len(fake.deleteCredentialByNameArgsForCall)]
+	fake.deleteCredentialByNameArgsForCall = append(fake.deleteCredentialByNameArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("DeleteCredentialByName", []interface{}{arg1, arg2})
+	fake.deleteCredentialByNameMutex.Unlock()
+	if fake.DeleteCredentialByNameStub != nil {
+		return fake.DeleteCredentialByNameStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteCredentialByNameReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeCredhubClient) DeleteCredentialByNameCallCount() int {
+	fake.deleteCredentialByNameMutex.RLock()
+	defer fake.deleteCredentialByNameMutex.RUnlock()
+	return len(fake.deleteCredentialByNameArgsForCall)
+}
+
+func (fake *FakeCredhubClient) DeleteCredentialByNameCalls(stub func(context.Context, string) error) {
+	fake.deleteCredentialByNameMutex.Lock()
+	defer fake.deleteCredentialByNameMutex.Unlock()
+	fake.DeleteCredentialByNameStub = stub
+}
+
+func (fake *FakeCredhubClient) DeleteCredentialByNameArgsForCall(i int) (context.Context, string) {
+	fake.deleteCredentialByNameMutex.RLock()
+	defer fake.deleteCredentialByNameMutex.RUnlock()
+	argsForCall := fake.deleteCredentialByNameArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCredhubClient) DeleteCredentialByNameReturns(result1 error) {
+	fake.deleteCredentialByNameMutex.Lock()
+	defer fake.deleteCredentialByNameMutex.Unlock()
+	fake.DeleteCredentialByNameStub = nil
+	fake.deleteCredentialByNameReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCredhubClient) DeleteCredentialByNameReturnsOnCall(i int, result1 error) {
+	fake.deleteCredentialByNameMutex.Lock()
+	defer fake.deleteCredentialByNameMutex.Unlock()
+	fake.DeleteCredentialByNameStub = nil
+	if fake.deleteCredentialByNameReturnsOnCall == nil {
+		fake.deleteCredentialByNameReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteCredentialByNameReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCredhubClient) DeletePermission(arg1 context.Context, arg2 uuid.UUID) error {
+	fake.deletePermissionMutex.Lock()
+	ret, specificReturn := fake.deletePermissionReturnsOnCall[len(fake.deletePermissionArgsForCall)]
+	fake.deletePermissionArgsForCall = append(fake.deletePermissionArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}{arg1, arg2})
+	fake.recordInvocation("DeletePermission", []interface{}{arg1, arg2})
+	fake.deletePermissionMutex.Unlock()
+	if fake.DeletePermissionStub != nil {
+		return fake.DeletePermissionStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deletePermissionReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeCredhubClient) DeletePermissionCallCount() int {
+	fake.deletePermissionMutex.RLock()
+	defer fake.deletePermissionMutex.RUnlock()
+	return len(fake.deletePermissionArgsForCall)
+}
+
+func (fake *FakeCredhubClient) DeletePermissionCalls(stub func(context.Context, uuid.UUID) error) {
+	fake.deletePermissionMutex.Lock()
+	defer fake.deletePermissionMutex.Unlock()
+	fake.DeletePermissionStub = stub
+}
+
+func (fake *FakeCredhubClient) DeletePermissionArgsForCall(i int) (context.Context, uuid.UUID) {
+	fake.deletePermissionMutex.RLock()
+	defer fake.deletePermissionMutex.RUnlock()
+	argsForCall := fake.deletePermissionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCredhubClient) DeletePermissionReturns(result1 error) {
+	fake.deletePermissionMutex.Lock()
+	defer fake.deletePermissionMutex.Unlock()
+	fake.DeletePermissionStub = nil
+	fake.deletePermissionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCredhubClient) DeletePermissionReturnsOnCall(i int, result1 error) {
+	fake.deletePermissionMutex.Lock()
+	defer fake.deletePermissionMutex.Unlock()
+	fake.DeletePermissionStub = nil
+	if fake.deletePermissionReturnsOnCall == nil {
+		fake.deletePermissionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deletePermissionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCredhubClient) FindCredentialsByPath(arg1 context.Context, arg2 string) ([]credhub.Credential, error) {
+	fake.findCredentialsByPathMutex.Lock()
+	ret, specificReturn := fake.findCredentialsByPathReturnsOnCall[len(fake.findCredentialsByPathArgsForCall)]
+	fake.findCredentialsByPathArgsForCall = append(fake.findCredentialsByPathArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("FindCredentialsByPath", []interface{}{arg1, arg2})
+	fake.findCredentialsByPathMutex.Unlock()
+	if fake.FindCredentialsByPathStub != nil {
+		return fake.FindCredentialsByPathStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.findCredentialsByPathReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCredhubClient) FindCredentialsByPathCallCount() int {
+	fake.findCredentialsByPathMutex.RLock()
+	defer fake.findCredentialsByPathMutex.RUnlock()
+	return len(fake.findCredentialsByPathArgsForCall)
+}
+
+func (fake *FakeCredhubClient) FindCredentialsByPathCalls(stub func(context.Context, string) ([]credhub.Credential, error)) {
+	fake.findCredentialsByPathMutex.Lock()
+	defer fake.findCredentialsByPathMutex.Unlock()
+	fake.FindCredentialsByPathStub = stub
+}
+
+func (fake *FakeCredhubClient) FindCredentialsByPathArgsForCall(i int) (context.Context, string) {
+	fake.findCredentialsByPathMutex.RLock()
+	defer fake.findCredentialsByPathMutex.RUnlock()
+	argsForCall := fake.findCredentialsByPathArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCredhubClient) FindCredentialsByPathReturns(result1 []credhub.Credential, result2 error) {
+	fake.findCredentialsByPathMutex.Lock()
+	defer fake.findCredentialsByPathMutex.Unlock()
+	fake.FindCredentialsByPathStub = nil
+	fake.findCredentialsByPathReturns = struct {
+		result1 []credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) FindCredentialsByPathReturnsOnCall(i int, result1 []credhub.Credential, result2 error) {
+	fake.findCredentialsByPathMutex.Lock()
+	defer fake.findCredentialsByPathMutex.Unlock()
+	fake.FindCredentialsByPathStub = nil
+	if fake.findCredentialsByPathReturnsOnCall == nil {
+		fake.findCredentialsByPathReturnsOnCall = make(map[int]struct {
+			result1 []credhub.Credential
+			result2 error
+		})
+	}
+	fake.findCredentialsByPathReturnsOnCall[i] = struct {
+		result1 []credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) GenerateCredential(arg1 context.Context, arg2 string, arg3 credhub.GenerateParameters, arg4 credhub.GenerateMode) (credhub.Credential, error) {
+	fake.generateCredentialMutex.Lock()
+	ret, specificReturn := fake.generateCredentialReturnsOnCall[len(fake.generateCredentialArgsForCall)]
+	fake.generateCredentialArgsForCall = append(fake.generateCredentialArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 credhub.GenerateParameters
+		arg4 credhub.GenerateMode
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("GenerateCredential", []interface{}{arg1, arg2, arg3, arg4})
+	fake.generateCredentialMutex.Unlock()
+	if fake.GenerateCredentialStub != nil {
+		return fake.GenerateCredentialStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.generateCredentialReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCredhubClient) GenerateCredentialCallCount() int {
+	fake.generateCredentialMutex.RLock()
+	defer fake.generateCredentialMutex.RUnlock()
+	return len(fake.generateCredentialArgsForCall)
+}
+
+func (fake *FakeCredhubClient) GenerateCredentialCalls(stub func(context.Context, string, credhub.GenerateParameters, credhub.GenerateMode) (credhub.Credential, error)) {
+	fake.generateCredentialMutex.Lock()
+	defer fake.generateCredentialMutex.Unlock()
+	fake.GenerateCredentialStub = stub
+}
+
+func (fake *FakeCredhubClient) GenerateCredentialArgsForCall(i int) (context.Context, string, credhub.GenerateParameters, credhub.GenerateMode) {
+	fake.generateCredentialMutex.RLock()
+	defer fake.generateCredentialMutex.RUnlock()
+	argsForCall := fake.generateCredentialArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeCredhubClient) GenerateCredentialReturns(result1 credhub.Credential, result2 error) {
+	fake.generateCredentialMutex.Lock()
+	defer fake.generateCredentialMutex.Unlock()
+	fake.GenerateCredentialStub = nil
+	fake.generateCredentialReturns = struct {
+		result1 credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) GenerateCredentialReturnsOnCall(i int, result1 credhub.Credential, result2 error) {
+	fake.generateCredentialMutex.Lock()
+	defer fake.generateCredentialMutex.Unlock()
+	fake.GenerateCredentialStub = nil
+	if fake.generateCredentialReturnsOnCall == nil {
+		fake.generateCredentialReturnsOnCall = make(map[int]struct {
+			result1 credhub.Credential
+			result2 error
+		})
+	}
+	fake.generateCredentialReturnsOnCall[i] = struct {
+		result1 credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) GetCredentialByID(arg1 context.Context, arg2 uuid.UUID) (credhub.Credential, error) {
+	fake.getCredentialByIDMutex.Lock()
+	ret, specificReturn := fake.getCredentialByIDReturnsOnCall[len(fake.getCredentialByIDArgsForCall)]
+	fake.getCredentialByIDArgsForCall = append(fake.getCredentialByIDArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}{arg1, arg2})
+	fake.recordInvocation("GetCredentialByID", []interface{}{arg1, arg2})
+	fake.getCredentialByIDMutex.Unlock()
+	if fake.GetCredentialByIDStub != nil {
+		return fake.GetCredentialByIDStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getCredentialByIDReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCredhubClient) GetCredentialByIDCallCount() int {
+	fake.getCredentialByIDMutex.RLock()
+	defer fake.getCredentialByIDMutex.RUnlock()
+	return len(fake.getCredentialByIDArgsForCall)
+}
+
+func (fake *FakeCredhubClient) GetCredentialByIDCalls(stub func(context.Context, uuid.UUID) (credhub.Credential, error)) {
+	fake.getCredentialByIDMutex.Lock()
+	defer fake.getCredentialByIDMutex.Unlock()
+	fake.GetCredentialByIDStub = stub
+}
+
+func (fake *FakeCredhubClient) GetCredentialByIDArgsForCall(i int) (context.Context, uuid.UUID) {
+	fake.getCredentialByIDMutex.RLock()
+	defer fake.getCredentialByIDMutex.RUnlock()
+	argsForCall := fake.getCredentialByIDArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCredhubClient) GetCredentialByIDReturns(result1 credhub.Credential, result2 error) {
+	fake.getCredentialByIDMutex.Lock()
+	defer fake.getCredentialByIDMutex.Unlock()
+	fake.GetCredentialByIDStub = nil
+	fake.getCredentialByIDReturns = struct {
+		result1 credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) GetCredentialByIDReturnsOnCall(i int, result1 credhub.Credential, result2 error) {
+	fake.getCredentialByIDMutex.Lock()
+	defer fake.getCredentialByIDMutex.Unlock()
+	fake.GetCredentialByIDStub = nil
+	if fake.getCredentialByIDReturnsOnCall == nil {
+		fake.getCredentialByIDReturnsOnCall = make(map[int]struct {
+			result1 credhub.Credential
+			result2 error
+		})
+	}
+	fake.getCredentialByIDReturnsOnCall[i] = struct {
+		result1 credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) GetCredentialByName(arg1 context.Context, arg2 string) (credhub.Credential, error) {
+	fake.getCredentialByNameMutex.Lock()
+	ret, specificReturn := fake.getCredentialByNameReturnsOnCall[len(fake.getCredentialByNameArgsForCall)]
+	fake.getCredentialByNameArgsForCall = append(fake.getCredentialByNameArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("GetCredentialByName", []interface{}{arg1, arg2})
+	fake.getCredentialByNameMutex.Unlock()
+	if fake.GetCredentialByNameStub != nil {
+		return fake.GetCredentialByNameStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getCredentialByNameReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCredhubClient) GetCredentialByNameCallCount() int {
+	fake.getCredentialByNameMutex.RLock()
+	defer fake.getCredentialByNameMutex.RUnlock()
+	return len(fake.getCredentialByNameArgsForCall)
+}
+
+func (fake *FakeCredhubClient) GetCredentialByNameCalls(stub func(context.Context, string) (credhub.Credential, error)) {
+	fake.getCredentialByNameMutex.Lock()
+	defer fake.getCredentialByNameMutex.Unlock()
+	fake.GetCredentialByNameStub = stub
+}
+
+func (fake *FakeCredhubClient) GetCredentialByNameArgsForCall(i int) (context.Context, string) {
+	fake.getCredentialByNameMutex.RLock()
+	defer fake.getCredentialByNameMutex.RUnlock()
+	argsForCall := fake.getCredentialByNameArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCredhubClient) GetCredentialByNameReturns(result1 credhub.Credential, result2 error) {
+	fake.getCredentialByNameMutex.Lock()
+	defer fake.getCredentialByNameMutex.Unlock()
+	fake.GetCredentialByNameStub = nil
+	fake.getCredentialByNameReturns = struct {
+		result1 credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) GetCredentialByNameReturnsOnCall(i int, result1 credhub.Credential, result2 error) {
+	fake.getCredentialByNameMutex.Lock()
+	defer fake.getCredentialByNameMutex.Unlock()
+	fake.GetCredentialByNameStub = nil
+	if fake.getCredentialByNameReturnsOnCall == nil {
+		fake.getCredentialByNameReturnsOnCall = make(map[int]struct {
+			result1 credhub.Credential
+			result2 error
+		})
+	}
+	fake.getCredentialByNameReturnsOnCall[i] = struct {
+		result1 credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) GetCredentialVersions(arg1 context.Context, arg2 string, arg3 int) ([]credhub.Credential, error) {
+	fake.getCredentialVersionsMutex.Lock()
+	ret, specificReturn := fake.getCredentialVersionsReturnsOnCall[len(fake.getCredentialVersionsArgsForCall)]
+	fake.getCredentialVersionsArgsForCall = append(fake.getCredentialVersionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetCredentialVersions", []interface{}{arg1, arg2, arg3})
+	fake.getCredentialVersionsMutex.Unlock()
+	if fake.GetCredentialVersionsStub != nil {
+		return fake.GetCredentialVersionsStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getCredentialVersionsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCredhubClient) GetCredentialVersionsCallCount() int {
+	fake.getCredentialVersionsMutex.RLock()
+	defer fake.getCredentialVersionsMutex.RUnlock()
+	return len(fake.getCredentialVersionsArgsForCall)
+}
+
+func (fake *FakeCredhubClient) GetCredentialVersionsCalls(stub func(context.Context, string, int) ([]credhub.Credential, error)) {
+	fake.getCredentialVersionsMutex.Lock()
+	defer fake.getCredentialVersionsMutex.Unlock()
+	fake.GetCredentialVersionsStub = stub
+}
+
+func (fake *FakeCredhubClient) GetCredentialVersionsArgsForCall(i int) (context.Context, string, int) {
+	fake.getCredentialVersionsMutex.RLock()
+	defer fake.getCredentialVersionsMutex.RUnlock()
+	argsForCall := fake.getCredentialVersionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeCredhubClient) GetCredentialVersionsReturns(result1 []credhub.Credential, result2 error) {
+	fake.getCredentialVersionsMutex.Lock()
+	defer fake.getCredentialVersionsMutex.Unlock()
+	fake.GetCredentialVersionsStub = nil
+	fake.getCredentialVersionsReturns = struct {
+		result1 []credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) GetCredentialVersionsReturnsOnCall(i int, result1 []credhub.Credential, result2 error) {
+	fake.getCredentialVersionsMutex.Lock()
+	defer fake.getCredentialVersionsMutex.Unlock()
+	fake.GetCredentialVersionsStub = nil
+	if fake.getCredentialVersionsReturnsOnCall == nil {
+		fake.getCredentialVersionsReturnsOnCall = make(map[int]struct {
+			result1 []credhub.Credential
+			result2 error
+		})
+	}
+	fake.getCredentialVersionsReturnsOnCall[i] = struct {
+		result1 []credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) GetPermission(arg1 context.Context, arg2 string, arg3 string) (credhub.Permission, error) {
+	fake.getPermissionMutex.Lock()
+	ret, specificReturn := fake.getPermissionReturnsOnCall[len(fake.getPermissionArgsForCall)]
+	fake.getPermissionArgsForCall = append(fake.getPermissionArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetPermission", []interface{}{arg1, arg2, arg3})
+	fake.getPermissionMutex.Unlock()
+	if fake.GetPermissionStub != nil {
+		return fake.GetPermissionStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getPermissionReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCredhubClient) GetPermissionCallCount() int {
+	fake.getPermissionMutex.RLock()
+	defer fake.getPermissionMutex.RUnlock()
+	return len(fake.getPermissionArgsForCall)
+}
+
+func (fake *FakeCredhubClient) GetPermissionCalls(stub func(context.Context, string, string) (credhub.Permission, error)) {
+	fake.getPermissionMutex.Lock()
+	defer fake.getPermissionMutex.Unlock()
+	fake.GetPermissionStub = stub
+}
+
+func (fake *FakeCredhubClient) GetPermissionArgsForCall(i int) (context.Context, string, string) {
+	fake.getPermissionMutex.RLock()
+	defer fake.getPermissionMutex.RUnlock()
+	argsForCall := fake.getPermissionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeCredhubClient) GetPermissionReturns(result1 credhub.Permission, result2 error) {
+	fake.getPermissionMutex.Lock()
+	defer fake.getPermissionMutex.Unlock()
+	fake.GetPermissionStub = nil
+	fake.getPermissionReturns = struct {
+		result1 credhub.Permission
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) GetPermissionReturnsOnCall(i int, result1 credhub.Permission, result2 error) {
+	fake.getPermissionMutex.Lock()
+	defer fake.getPermissionMutex.Unlock()
+	fake.GetPermissionStub = nil
+	if fake.getPermissionReturnsOnCall == nil {
+		fake.getPermissionReturnsOnCall = make(map[int]struct {
+			result1 credhub.Permission
+			result2 error
+		})
+	}
+	fake.getPermissionReturnsOnCall[i] = struct {
+		result1 credhub.Permission
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) GetPermissions(arg1 context.Context, arg2 string) ([]credhub.Permission, error) {
+	fake.getPermissionsMutex.Lock()
+	ret, specificReturn := fake.getPermissionsReturnsOnCall[len(fake.getPermissionsArgsForCall)]
+	fake.getPermissionsArgsForCall = append(fake.getPermissionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("GetPermissions", []interface{}{arg1, arg2})
+	fake.getPermissionsMutex.Unlock()
+	if fake.GetPermissionsStub != nil {
+		return fake.GetPermissionsStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getPermissionsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCredhubClient) GetPermissionsCallCount() int {
+	fake.getPermissionsMutex.RLock()
+	defer fake.getPermissionsMutex.RUnlock()
+	return len(fake.getPermissionsArgsForCall)
+}
+
+func (fake *FakeCredhubClient) GetPermissionsCalls(stub func(context.Context, string) ([]credhub.Permission, error)) {
+	fake.getPermissionsMutex.Lock()
+	defer fake.getPermissionsMutex.Unlock()
+	fake.GetPermissionsStub = stub
+}
+
+func (fake *FakeCredhubClient) GetPermissionsArgsForCall(i int) (context.Context, string) {
+	fake.getPermissionsMutex.RLock()
+	defer fake.getPermissionsMutex.RUnlock()
+	argsForCall := fake.getPermissionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCredhubClient) GetPermissionsReturns(result1 []credhub.Permission, result2 error) {
+	fake.getPermissionsMutex.Lock()
+	defer fake.getPermissionsMutex.Unlock()
+	fake.GetPermissionsStub = nil
+	fake.getPermissionsReturns = struct {
+		result1 []credhub.Permission
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) GetPermissionsReturnsOnCall(i int, result1 []credhub.Permission, result2 error) {
+	fake.getPermissionsMutex.Lock()
+	defer fake.getPermissionsMutex.Unlock()
+	fake.GetPermissionsStub = nil
+	if fake.getPermissionsReturnsOnCall == nil {
+		fake.getPermissionsReturnsOnCall = make(map[int]struct {
+			result1 []credhub.Permission
+			result2 error
+		})
+	}
+	fake.getPermissionsReturnsOnCall[i] = struct {
+		result1 []credhub.Permission
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) RegenerateCredential(arg1 context.Context, arg2 string) (credhub.Credential, error) {
+	fake.regenerateCredentialMutex.Lock()
+	ret, specificReturn := fake.regenerateCredentialReturnsOnCall[len(fake.regenerateCredentialArgsForCall)]
+	fake.regenerateCredentialArgsForCall = append(fake.regenerateCredentialArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("RegenerateCredential", []interface{}{arg1, arg2})
+	fake.regenerateCredentialMutex.Unlock()
+	if fake.RegenerateCredentialStub != nil {
+		return fake.RegenerateCredentialStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.regenerateCredentialReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCredhubClient) RegenerateCredentialCallCount() int {
+	fake.regenerateCredentialMutex.RLock()
+	defer fake.regenerateCredentialMutex.RUnlock()
+	return len(fake.regenerateCredentialArgsForCall)
+}
+
+func (fake *FakeCredhubClient) RegenerateCredentialCalls(stub func(context.Context, string) (credhub.Credential, error)) {
+	fake.regenerateCredentialMutex.Lock()
+	defer fake.regenerateCredentialMutex.Unlock()
+	fake.RegenerateCredentialStub = stub
+}
+
+func (fake *FakeCredhubClient) RegenerateCredentialArgsForCall(i int) (context.Context, string) {
+	fake.regenerateCredentialMutex.RLock()
+	defer fake.regenerateCredentialMutex.RUnlock()
+	argsForCall := fake.regenerateCredentialArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCredhubClient) RegenerateCredentialReturns(result1 credhub.Credential, result2 error) {
+	fake.regenerateCredentialMutex.Lock()
+	defer fake.regenerateCredentialMutex.Unlock()
+	fake.RegenerateCredentialStub = nil
+	fake.regenerateCredentialReturns = struct {
+		result1 credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) RegenerateCredentialReturnsOnCall(i int, result1 credhub.Credential, result2 error) {
+	fake.regenerateCredentialMutex.Lock()
+	defer fake.regenerateCredentialMutex.Unlock()
+	fake.RegenerateCredentialStub = nil
+	if fake.regenerateCredentialReturnsOnCall == nil {
+		fake.regenerateCredentialReturnsOnCall = make(map[int]struct {
+			result1 credhub.Credential
+			result2 error
+		})
+	}
+	fake.regenerateCredentialReturnsOnCall[i] = struct {
+		result1 credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) SetCredential(arg1 context.Context, arg2 credhub.Credential) (credhub.Credential, error) {
+	fake.setCredentialMutex.Lock()
+	ret, specificReturn := fake.setCredentialReturnsOnCall[len(fake.setCredentialArgsForCall)]
+	fake.setCredentialArgsForCall = append(fake.setCredentialArgsForCall, struct {
+		arg1 context.Context
+		arg2 credhub.Credential
+	}{arg1, arg2})
+	fake.recordInvocation("SetCredential", []interface{}{arg1, arg2})
+	fake.setCredentialMutex.Unlock()
+	if fake.SetCredentialStub != nil {
+		return fake.SetCredentialStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.setCredentialReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCredhubClient) SetCredentialCallCount() int {
+	fake.setCredentialMutex.RLock()
+	defer fake.setCredentialMutex.RUnlock()
+	return len(fake.setCredentialArgsForCall)
+}
+
+func (fake *FakeCredhubClient) SetCredentialCalls(stub func(context.Context, credhub.Credential) (credhub.Credential, error)) {
+	fake.setCredentialMutex.Lock()
+	defer fake.setCredentialMutex.Unlock()
+	fake.SetCredentialStub = stub
+}
+
+func (fake *FakeCredhubClient) SetCredentialArgsForCall(i int) (context.Context, credhub.Credential) {
+	fake.setCredentialMutex.RLock()
+	defer fake.setCredentialMutex.RUnlock()
+	argsForCall := fake.setCredentialArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCredhubClient) SetCredentialReturns(result1 credhub.Credential, result2 error) {
+	fake.setCredentialMutex.Lock()
+	defer fake.setCredentialMutex.Unlock()
+	fake.SetCredentialStub = nil
+	fake.setCredentialReturns = struct {
+		result1 credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) SetCredentialReturnsOnCall(i int, result1 credhub.Credential, result2 error) {
+	fake.setCredentialMutex.Lock()
+	defer fake.setCredentialMutex.Unlock()
+	fake.SetCredentialStub = nil
+	if fake.setCredentialReturnsOnCall == nil {
+		fake.setCredentialReturnsOnCall = make(map[int]struct {
+			result1 credhub.Credential
+			result2 error
+		})
+	}
+	fake.setCredentialReturnsOnCall[i] = struct {
+		result1 credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) UpdatePermission(arg1 context.Context, arg2 credhub.Permission) (credhub.Permission, error) {
+	fake.updatePermissionMutex.Lock()
+	ret, specificReturn := fake.updatePermissionReturnsOnCall[len(fake.updatePermissionArgsForCall)]
+	fake.updatePermissionArgsForCall = append(fake.updatePermissionArgsForCall, struct {
+		arg1 context.Context
+		arg2 credhub.Permission
+	}{arg1, arg2})
+	fake.recordInvocation("UpdatePermission", []interface{}{arg1, arg2})
+	fake.updatePermissionMutex.Unlock()
+	if fake.UpdatePermissionStub != nil {
+		return fake.UpdatePermissionStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.updatePermissionReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCredhubClient) UpdatePermissionCallCount() int {
+	fake.updatePermissionMutex.RLock()
+	defer fake.updatePermissionMutex.RUnlock()
+	return len(fake.updatePermissionArgsForCall)
+}
+
+func (fake *FakeCredhubClient) UpdatePermissionCalls(stub func(context.Context, credhub.Permission) (credhub.Permission, error)) {
+	fake.updatePermissionMutex.Lock()
+	defer fake.updatePermissionMutex.Unlock()
+	fake.UpdatePermissionStub = stub
+}
+
+func (fake *FakeCredhubClient) UpdatePermissionArgsForCall(i int) (context.Context, credhub.Permission) {
+	fake.updatePermissionMutex.RLock()
+	defer fake.updatePermissionMutex.RUnlock()
+	argsForCall := fake.updatePermissionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCredhubClient) UpdatePermissionReturns(result1 credhub.Permission, result2 error) {
+	fake.updatePermissionMutex.Lock()
+	defer fake.updatePermissionMutex.Unlock()
+	fake.UpdatePermissionStub = nil
+	fake.updatePermissionReturns = struct {
+		result1 credhub.Permission
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) UpdatePermissionReturnsOnCall(i int, result1 credhub.Permission, result2 error) {
+	fake.updatePermissionMutex.Lock()
+	defer fake.updatePermissionMutex.Unlock()
+	fake.UpdatePermissionStub = nil
+	if fake.updatePermissionReturnsOnCall == nil {
+		fake.updatePermissionReturnsOnCall = make(map[int]struct {
+			result1 credhub.Permission
+			result2 error
+		})
+	}
+	fake.updatePermissionReturnsOnCall[i] = struct {
+		result1 credhub.Permission
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.authenticateMutex.RLock()
+	defer fake.authenticateMutex.RUnlock()
+	fake.bulkRegenerateMutex.RLock()
+	defer fake.bulkRegenerateMutex.RUnlock()
+	fake.createPermissionMutex.RLock()
+	defer fake.createPermissionMutex.RUnlock()
+	fake.deleteCredentialByNameMutex.RLock()
+	defer fake.deleteCredentialByNameMutex.RUnlock()
+	fake.deletePermissionMutex.RLock()
+	defer fake.deletePermissionMutex.RUnlock()
+	fake.findCredentialsByPathMutex.RLock()
+	defer fake.findCredentialsByPathMutex.RUnlock()
+	fake.generateCredentialMutex.RLock()
+	defer fake.generateCredentialMutex.RUnlock()
+	fake.getCredentialByIDMutex.RLock()
+	defer fake.getCredentialByIDMutex.RUnlock()
+	fake.getCredentialByNameMutex.RLock()
+	defer fake.getCredentialByNameMutex.RUnlock()
+	fake.getCredentialVersionsMutex.RLock()
+	defer fake.getCredentialVersionsMutex.RUnlock()
+	fake.getPermissionMutex.RLock()
+	defer fake.getPermissionMutex.RUnlock()
+	fake.getPermissionsMutex.RLock()
+	defer fake.getPermissionsMutex.RUnlock()
+	fake.regenerateCredentialMutex.RLock()
+	defer fake.regenerateCredentialMutex.RUnlock()
+	fake.setCredentialMutex.RLock()
+	defer fake.setCredentialMutex.RUnlock()
+	fake.updatePermissionMutex.RLock()
+	defer fake.updatePermissionMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeCredhubClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
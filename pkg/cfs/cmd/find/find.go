@@ -0,0 +1,313 @@
+package find
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const day = 24 * time.Hour
+
+var credentialTypes = []string{
+	credhub.TypeValue,
+	credhub.TypeJSON,
+	credhub.TypePassword,
+	credhub.TypeUser,
+	credhub.TypeCertificate,
+	credhub.TypeRSA,
+	credhub.TypeSSH,
+}
+
+type cmdFindRunner struct {
+	ctx           context.Context
+	credhubClient credhubClient
+	options       *findOptions
+}
+
+type findOptions struct {
+	path           string
+	namePattern    string
+	credentialType string
+	mtimeSign      int
+	mtimeDays      int
+	mtimeSet       bool
+	print0         bool
+	execCommands   [][]string
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+func NewCmdFind(dependencies cmdutil.Dependencies) *cobra.Command {
+	var options *findOptions
+
+	cmd := &cobra.Command{
+		Use:   `find [PATH] [-name GLOB] [-type TYPE] [-mtime +N|-N|N] [-print0] [-exec COMMAND {} \;]`,
+		Short: "Search for credentials by name, type and age",
+		// Flag parsing is disabled so that predicates can use find's
+		// single-dash syntax. Global flags are picked out of the arguments
+		// here, before the root command reads them.
+		DisableFlagParsing: true,
+		Args: func(cmd *cobra.Command, args []string) error {
+			var globalArgs []string
+			var err error
+			options, globalArgs, err = parseArgs(args, cmd.InheritedFlags())
+			if err != nil {
+				return err
+			}
+			return cmd.InheritedFlags().Parse(globalArgs)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if options == nil {
+				return cmd.Help()
+			}
+
+			cmd.SilenceUsage = true
+
+			c := &cmdFindRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+				options:       options,
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	return cmd
+}
+
+// parseArgs parses find's expression. It returns nil options if help was
+// requested, along with any global flags to be parsed by cobra. A global flag
+// given without '=' takes the next argument as its value unless it is a
+// boolean flag in globalFlags.
+func parseArgs(args []string, globalFlags *pflag.FlagSet) (*findOptions, []string, error) {
+	options := &findOptions{path: "/"}
+	var globalArgs []string
+
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		options.path = args[0]
+		args = args[1:]
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		value := func() (string, error) {
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("missing argument to '%s'", arg)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch {
+		case arg == "-h" || arg == "--help":
+			return nil, nil, nil
+		case arg == "-name":
+			pattern, err := value()
+			if err != nil {
+				return nil, nil, err
+			}
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, nil, fmt.Errorf("invalid argument '%s' to '-name'", pattern)
+			}
+			options.namePattern = pattern
+		case arg == "-type":
+			credentialType, err := value()
+			if err != nil {
+				return nil, nil, err
+			}
+			if !isCredentialType(credentialType) {
+				return nil, nil, fmt.Errorf("invalid argument '%s' to '-type': must be one of %s", credentialType, strings.Join(credentialTypes, ", "))
+			}
+			options.credentialType = credentialType
+		case arg == "-mtime":
+			mtime, err := value()
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := options.parseMtime(mtime); err != nil {
+				return nil, nil, err
+			}
+		case arg == "-print0":
+			options.print0 = true
+		case arg == "-exec":
+			end := i + 1
+			for end < len(args) && args[end] != ";" {
+				end++
+			}
+			if end == len(args) || end == i+1 {
+				return nil, nil, errors.New("missing argument to '-exec'")
+			}
+			options.execCommands = append(options.execCommands, args[i+1:end])
+			i = end
+		case strings.HasPrefix(arg, "--"):
+			globalArgs = append(globalArgs, arg)
+			flag := globalFlags.Lookup(strings.TrimPrefix(arg, "--"))
+			if flag != nil && flag.NoOptDefVal != "" {
+				continue
+			}
+			if !strings.Contains(arg, "=") && i+1 < len(args) {
+				i++
+				globalArgs = append(globalArgs, args[i])
+			}
+		case strings.HasPrefix(arg, "-"):
+			return nil, nil, fmt.Errorf("unknown predicate '%s'", arg)
+		default:
+			return nil, nil, fmt.Errorf("paths must precede expression: '%s'", arg)
+		}
+	}
+
+	return options, globalArgs, nil
+}
+
+func (o *findOptions) parseMtime(mtime string) error {
+	days := mtime
+	switch {
+	case strings.HasPrefix(mtime, "+"):
+		o.mtimeSign = 1
+		days = mtime[1:]
+	case strings.HasPrefix(mtime, "-"):
+		o.mtimeSign = -1
+		days = mtime[1:]
+	}
+
+	var err error
+	o.mtimeDays, err = strconv.Atoi(days)
+	if err != nil || o.mtimeDays < 0 {
+		return fmt.Errorf("invalid argument '%s' to '-mtime'", mtime)
+	}
+	o.mtimeSet = true
+	return nil
+}
+
+func isCredentialType(credentialType string) bool {
+	for _, t := range credentialTypes {
+		if t == credentialType {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *cmdFindRunner) Run(cmd *cobra.Command, args []string) error {
+	searchPath := c.options.path
+	if searchPath[0] != '/' {
+		searchPath = "/" + searchPath
+	}
+
+	credentials, err := c.credhubClient.FindCredentialsByPath(c.ctx, searchPath)
+	if err != nil {
+		return fmt.Errorf("failed to find credentials: %s", err.Error())
+	}
+
+	if len(credentials) == 0 && searchPath != "/" {
+		credential, err := c.credhubClient.GetCredentialByName(c.ctx, searchPath)
+		if err != nil {
+			switch err.(type) {
+			case *credhub.ErrCredentialNotFound:
+				return fmt.Errorf("'%s': no such credential or path", searchPath)
+			default:
+				return fmt.Errorf("failed to get credential: %s", err.Error())
+			}
+		}
+		credentials = []credhub.Credential{credential}
+	}
+
+	sort.Slice(credentials, func(i, j int) bool {
+		return credentials[i].Name < credentials[j].Name
+	})
+
+	now := time.Now()
+	matches := 0
+	failures := 0
+	for _, credential := range credentials {
+		matched, err := c.matches(credential, now)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+		matches++
+
+		if c.options.print0 {
+			fmt.Fprint(cmd.OutOrStdout(), credential.Name+"\x00")
+		}
+		for _, command := range c.options.execCommands {
+			if err := runCommand(cmd, command, credential.Name); err != nil {
+				fmt.Fprintf(cmd.OutOrStderr(), "failed to execute %s for %s: %s\n", command[0], credential.Name, err.Error())
+				failures++
+			}
+		}
+		if !c.options.print0 && len(c.options.execCommands) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), credential.Name)
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("failed to execute %d of %d commands", failures, matches*len(c.options.execCommands))
+	}
+	return nil
+}
+
+func (c *cmdFindRunner) matches(credential credhub.Credential, now time.Time) (bool, error) {
+	if c.options.namePattern != "" {
+		if matched, _ := path.Match(c.options.namePattern, path.Base(credential.Name)); !matched {
+			return false, nil
+		}
+	}
+
+	if c.options.mtimeSet {
+		age := int(now.Sub(credential.VersionCreatedAt) / day)
+		switch c.options.mtimeSign {
+		case 1:
+			if age <= c.options.mtimeDays {
+				return false, nil
+			}
+		case -1:
+			if age >= c.options.mtimeDays {
+				return false, nil
+			}
+		default:
+			if age != c.options.mtimeDays {
+				return false, nil
+			}
+		}
+	}
+
+	if c.options.credentialType != "" {
+		fullCredential, err := c.credhubClient.GetCredentialByName(c.ctx, credential.Name)
+		if err != nil {
+			return false, fmt.Errorf("failed to get credential: %s", err.Error())
+		}
+		if fullCredential.Type != c.options.credentialType {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func runCommand(cmd *cobra.Command, command []string, name string) error {
+	var args []string
+	for _, arg := range command[1:] {
+		args = append(args, strings.Replace(arg, "{}", name, -1))
+	}
+
+	execCmd := exec.Command(strings.Replace(command[0], "{}", name, -1), args...)
+	execCmd.Stdout = cmd.OutOrStdout()
+	execCmd.Stderr = cmd.OutOrStderr()
+	return execCmd.Run()
+}
@@ -0,0 +1,13 @@
+package find_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestFind(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Find Suite")
+}
@@ -0,0 +1,195 @@
+package find_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/find"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/find/findfakes"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/spf13/cobra"
+)
+
+var _ = Describe("Find", func() {
+	var (
+		fakeCredhubClient *findfakes.FakeCredhubClient
+		dependencies      cmdutil.Dependencies
+		daysAgo           func(days int) time.Time
+		runFind           func(args ...string) (string, error)
+	)
+
+	BeforeEach(func() {
+		fakeCredhubClient = &findfakes.FakeCredhubClient{}
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetCredhubClient(fakeCredhubClient)
+
+		daysAgo = func(days int) time.Time {
+			return time.Now().Add(-time.Duration(days)*24*time.Hour - time.Hour)
+		}
+
+		fakeCredhubClient.FindCredentialsByPathReturns([]credhub.Credential{
+			{Name: "/concourse/main/db-password", VersionCreatedAt: daysAgo(100)},
+			{Name: "/concourse/main/tls-cert", VersionCreatedAt: daysAgo(10)},
+			{Name: "/concourse/other/db-password", VersionCreatedAt: daysAgo(1)},
+		}, nil)
+		fakeCredhubClient.GetCredentialByNameStub = func(_ context.Context, name string) (credhub.Credential, error) {
+			if name == "/concourse/main/tls-cert" {
+				return credhub.Credential{Name: name, Type: "certificate"}, nil
+			}
+			return credhub.Credential{Name: name, Type: "password"}, nil
+		}
+
+		runFind = func(args ...string) (string, error) {
+			var output bytes.Buffer
+			cmd := find.NewCmdFind(dependencies)
+			cmd.SetArgs(append([]string{}, args...))
+			cmd.SetOutput(&output)
+			err := cmd.Execute()
+			return output.String(), err
+		}
+	})
+
+	It("prints every credential under the path, sorted", func() {
+		output, err := runFind("/concourse")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("/concourse/main/db-password\n/concourse/main/tls-cert\n/concourse/other/db-password\n"))
+
+		_, searchedPath := fakeCredhubClient.FindCredentialsByPathArgsForCall(0)
+		Expect(searchedPath).To(Equal("/concourse"))
+		Expect(fakeCredhubClient.GetCredentialByNameCallCount()).To(Equal(0))
+	})
+
+	It("searches from the root by default", func() {
+		_, err := runFind()
+		Expect(err).NotTo(HaveOccurred())
+		_, searchedPath := fakeCredhubClient.FindCredentialsByPathArgsForCall(0)
+		Expect(searchedPath).To(Equal("/"))
+	})
+
+	It("filters by base name with '-name'", func() {
+		output, err := runFind("/concourse", "-name", "db-*")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("/concourse/main/db-password\n/concourse/other/db-password\n"))
+	})
+
+	It("filters by credential type with '-type'", func() {
+		output, err := runFind("/concourse", "-type", "certificate")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("/concourse/main/tls-cert\n"))
+	})
+
+	It("filters by age in days with '-mtime'", func() {
+		output, err := runFind("/concourse", "-mtime", "+90")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("/concourse/main/db-password\n"))
+
+		output, err = runFind("/concourse", "-mtime", "-10")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("/concourse/other/db-password\n"))
+
+		output, err = runFind("/concourse", "-mtime", "10")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("/concourse/main/tls-cert\n"))
+	})
+
+	It("combines predicates", func() {
+		output, err := runFind("/concourse", "-name", "db-*", "-mtime", "-90")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("/concourse/other/db-password\n"))
+	})
+
+	It("separates names with NUL characters with '-print0'", func() {
+		output, err := runFind("/concourse", "-name", "db-*", "-print0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("/concourse/main/db-password\x00/concourse/other/db-password\x00"))
+	})
+
+	It("runs a command for each match with '-exec'", func() {
+		output, err := runFind("/concourse", "-name", "db-*", "-exec", "echo", "found:{}", ";")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("found:/concourse/main/db-password\nfound:/concourse/other/db-password\n"))
+	})
+
+	It("returns an error when '-exec' commands fail", func() {
+		output, err := runFind("/concourse", "-name", "db-*", "-exec", "false", ";")
+		Expect(err).To(MatchError("failed to execute 2 of 2 commands"))
+		Expect(output).To(ContainSubstring("failed to execute false for /concourse/main/db-password"))
+	})
+
+	Context("when the path is a single credential", func() {
+		It("prints the credential", func() {
+			fakeCredhubClient.FindCredentialsByPathReturns(nil, nil)
+
+			output, err := runFind("/concourse/main/db-password")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(Equal("/concourse/main/db-password\n"))
+		})
+	})
+
+	Context("when the path does not exist", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.FindCredentialsByPathReturns(nil, nil)
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{}, &credhub.ErrCredentialNotFound{})
+			fakeCredhubClient.GetCredentialByNameStub = nil
+
+			_, err := runFind("/missing")
+			Expect(err).To(MatchError("'/missing': no such credential or path"))
+		})
+	})
+
+	Context("when finding credentials fails", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.FindCredentialsByPathReturns(nil, errors.New("some-error"))
+
+			_, err := runFind("/concourse")
+			Expect(err).To(MatchError("failed to find credentials: some-error"))
+		})
+	})
+
+	It("parses global flags given among the expression", func() {
+		root := &cobra.Command{Use: "cfs"}
+		root.PersistentFlags().Bool("skip-tls-validation", false, "")
+		root.PersistentFlags().String("target", "", "")
+		findCmd := find.NewCmdFind(dependencies)
+		root.AddCommand(findCmd)
+
+		var output bytes.Buffer
+		root.SetArgs([]string{"find", "/concourse", "--skip-tls-validation", "--target", "some-target", "-name", "tls-*"})
+		root.SetOutput(&output)
+		Expect(root.Execute()).To(Succeed())
+		Expect(output.String()).To(Equal("/concourse/main/tls-cert\n"))
+
+		skipTLSValidation, _ := findCmd.InheritedFlags().GetBool("skip-tls-validation")
+		Expect(skipTLSValidation).To(BeTrue())
+		target, _ := findCmd.InheritedFlags().GetString("target")
+		Expect(target).To(Equal("some-target"))
+	})
+
+	Context("when the expression is invalid", func() {
+		It("returns an error", func() {
+			for expectedError, args := range map[string][]string{
+				"unknown predicate '-foo'":                 {"/", "-foo"},
+				"missing argument to '-name'":              {"/", "-name"},
+				"invalid argument '[' to '-name'":          {"/", "-name", "["},
+				"invalid argument 'soon' to '-mtime'":      {"/", "-mtime", "soon"},
+				"missing argument to '-exec'":              {"/", "-exec", "echo", "{}"},
+				"paths must precede expression: '/other'":  {"/", "-print0", "/other"},
+				"unknown flag: --some-unknown-global-flag": {"/", "--some-unknown-global-flag", "value"},
+			} {
+				_, err := runFind(args...)
+				Expect(err).To(MatchError(expectedError))
+			}
+		})
+
+		It("lists the valid types for an invalid '-type'", func() {
+			_, err := runFind("/", "-type", "secret")
+			Expect(err).To(MatchError("invalid argument 'secret' to '-type': must be one of value, json, password, user, certificate, rsa, ssh"))
+		})
+	})
+})
@@ -0,0 +1,178 @@
+package generate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type cmdGenerateRunner struct {
+	ctx           context.Context
+	credhubClient credhubClient
+	parameters    credhub.GenerateParameters
+	noClobber     bool
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+var generatableTypes = []string{
+	credhub.TypePassword,
+	credhub.TypeCertificate,
+	credhub.TypeRSA,
+	credhub.TypeSSH,
+}
+
+// typeFlags lists the flags that apply to each generatable type.
+var typeFlags = map[string][]string{
+	credhub.TypePassword:    {"length", "exclude-upper", "exclude-lower", "exclude-number", "include-special"},
+	credhub.TypeCertificate: {"common-name", "alternative-name", "ca", "is-ca", "self-sign", "key-length", "duration"},
+	credhub.TypeRSA:         {"key-length"},
+	credhub.TypeSSH:         {"key-length", "ssh-comment"},
+}
+
+func NewCmdGenerate(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate PATH --type TYPE",
+		Short: "Generate a new credential value in CredHub",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("must provide a credential path")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			noClobber, _ := cmd.Flags().GetBool("no-clobber")
+
+			parameters, err := parseParameters(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			c := &cmdGenerateRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+				parameters:    parameters,
+				noClobber:     noClobber,
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	cmd.Flags().StringP("type", "t", "", "type of credential to generate: "+strings.Join(generatableTypes, ", "))
+	cmd.Flags().BoolP("no-clobber", "n", false, "leave an existing credential unchanged instead of generating a new version")
+
+	cmd.Flags().Int("length", 0, "password length (default 30)")
+	cmd.Flags().Bool("exclude-upper", false, "exclude upper case letters from the password")
+	cmd.Flags().Bool("exclude-lower", false, "exclude lower case letters from the password")
+	cmd.Flags().Bool("exclude-number", false, "exclude numbers from the password")
+	cmd.Flags().Bool("include-special", false, "include special characters in the password")
+
+	cmd.Flags().String("common-name", "", "certificate common name")
+	cmd.Flags().StringArray("alternative-name", nil, "certificate alternative name, may be repeated")
+	cmd.Flags().String("ca", "", "name of the CA credential that signs the certificate")
+	cmd.Flags().Bool("is-ca", false, "generate a CA certificate")
+	cmd.Flags().Bool("self-sign", false, "generate a self-signed certificate")
+	cmd.Flags().Int("duration", 0, "certificate validity in days (default 365)")
+
+	cmd.Flags().Int("key-length", 0, "key length for certificate, rsa and ssh credentials: 2048, 3072 or 4096 (default 2048)")
+	cmd.Flags().String("ssh-comment", "", "comment appended to the SSH public key")
+
+	return cmd
+}
+
+func (c *cmdGenerateRunner) Run(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	mode := credhub.GenerateModeOverwrite
+	if c.noClobber {
+		mode = credhub.GenerateModeNoOverwrite
+	}
+
+	if _, err := c.credhubClient.GenerateCredential(c.ctx, name, c.parameters, mode); err != nil {
+		return fmt.Errorf("failed to generate %s: %s", name, err.Error())
+	}
+
+	return nil
+}
+
+func parseParameters(flags *pflag.FlagSet) (credhub.GenerateParameters, error) {
+	credentialType, _ := flags.GetString("type")
+	if credentialType == "" {
+		return nil, errors.New("must provide '--type'")
+	}
+
+	validFlags, generatable := typeFlags[credentialType]
+	if !generatable {
+		return nil, fmt.Errorf("invalid type '%s': must be one of %s", credentialType, strings.Join(generatableTypes, ", "))
+	}
+
+	var err error
+	flags.Visit(func(flag *pflag.Flag) {
+		if err == nil && isTypeFlag(flag.Name) && !contains(validFlags, flag.Name) {
+			err = fmt.Errorf("'--%s' is not valid for type '%s'", flag.Name, credentialType)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keyLength, _ := flags.GetInt("key-length")
+
+	switch credentialType {
+	case credhub.TypePassword:
+		parameters := credhub.PasswordParameters{}
+		parameters.Length, _ = flags.GetInt("length")
+		parameters.ExcludeUpper, _ = flags.GetBool("exclude-upper")
+		parameters.ExcludeLower, _ = flags.GetBool("exclude-lower")
+		parameters.ExcludeNumber, _ = flags.GetBool("exclude-number")
+		parameters.IncludeSpecial, _ = flags.GetBool("include-special")
+		return parameters, nil
+	case credhub.TypeCertificate:
+		parameters := credhub.CertificateParameters{KeyLength: keyLength}
+		parameters.CommonName, _ = flags.GetString("common-name")
+		parameters.AlternativeNames, _ = flags.GetStringArray("alternative-name")
+		parameters.CA, _ = flags.GetString("ca")
+		parameters.IsCA, _ = flags.GetBool("is-ca")
+		parameters.SelfSign, _ = flags.GetBool("self-sign")
+		parameters.Duration, _ = flags.GetInt("duration")
+		if parameters.CommonName == "" && len(parameters.AlternativeNames) == 0 {
+			return nil, errors.New("must provide '--common-name' or '--alternative-name' for type 'certificate'")
+		}
+		if parameters.CA == "" && !parameters.IsCA && !parameters.SelfSign {
+			return nil, errors.New("must provide '--ca', '--is-ca' or '--self-sign' for type 'certificate'")
+		}
+		return parameters, nil
+	case credhub.TypeRSA:
+		return credhub.RSAParameters{KeyLength: keyLength}, nil
+	default:
+		sshComment, _ := flags.GetString("ssh-comment")
+		return credhub.SSHParameters{KeyLength: keyLength, SSHComment: sshComment}, nil
+	}
+}
+
+func isTypeFlag(name string) bool {
+	for _, flags := range typeFlags {
+		if contains(flags, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package generate_test
+
+import (
+	"errors"
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/generate"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/generate/generatefakes"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+var _ = Describe("Generate", func() {
+	var fakeCredhubClient *generatefakes.FakeCredhubClient
+	var dependencies cmdutil.Dependencies
+
+	BeforeEach(func() {
+		fakeCredhubClient = &generatefakes.FakeCredhubClient{}
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetCredhubClient(fakeCredhubClient)
+	})
+
+	execute := func(args ...string) error {
+		cmd := generate.NewCmdGenerate(dependencies)
+		cmd.SetArgs(append([]string{}, args...))
+		cmd.SetOutput(ioutil.Discard)
+		return cmd.Execute()
+	}
+
+	It("generates passwords", func() {
+		path := "/some/path/to/cred"
+
+		Expect(execute(path, "--type", "password", "--length", "40", "--exclude-upper", "--exclude-lower", "--exclude-number", "--include-special")).To(Succeed())
+
+		Expect(fakeCredhubClient.GetCredentialByNameCallCount()).To(Equal(0))
+		Expect(fakeCredhubClient.GenerateCredentialCallCount()).To(Equal(1))
+		_, name, parameters, mode := fakeCredhubClient.GenerateCredentialArgsForCall(0)
+		Expect(name).To(Equal(path))
+		Expect(mode).To(Equal(credhub.GenerateModeOverwrite))
+		Expect(parameters).To(Equal(credhub.PasswordParameters{
+			Length:         40,
+			ExcludeUpper:   true,
+			ExcludeLower:   true,
+			ExcludeNumber:  true,
+			IncludeSpecial: true,
+		}))
+	})
+
+	It("generates certificates", func() {
+		Expect(execute(
+			"/some/cert", "-t", "certificate",
+			"--common-name", "example.com",
+			"--alternative-name", "www.example.com",
+			"--alternative-name", "10.0.0.1",
+			"--ca", "/some/ca",
+			"--key-length", "4096",
+			"--duration", "30",
+		)).To(Succeed())
+
+		_, _, parameters, _ := fakeCredhubClient.GenerateCredentialArgsForCall(0)
+		Expect(parameters).To(Equal(credhub.CertificateParameters{
+			CommonName:       "example.com",
+			AlternativeNames: []string{"www.example.com", "10.0.0.1"},
+			CA:               "/some/ca",
+			KeyLength:        4096,
+			Duration:         30,
+		}))
+	})
+
+	It("generates RSA keys", func() {
+		Expect(execute("/some/rsa", "--type", "rsa", "--key-length", "3072")).To(Succeed())
+
+		_, _, parameters, _ := fakeCredhubClient.GenerateCredentialArgsForCall(0)
+		Expect(parameters).To(Equal(credhub.RSAParameters{KeyLength: 3072}))
+	})
+
+	It("generates SSH keys", func() {
+		Expect(execute("/some/ssh", "--type", "ssh", "--ssh-comment", "some-comment")).To(Succeed())
+
+		_, _, parameters, _ := fakeCredhubClient.GenerateCredentialArgsForCall(0)
+		Expect(parameters).To(Equal(credhub.SSHParameters{SSHComment: "some-comment"}))
+	})
+
+	Context("when `no-clobber` is true", func() {
+		It("asks CredHub not to overwrite an existing credential", func() {
+			Expect(execute("-n", "/some/cred", "--type", "password")).To(Succeed())
+
+			Expect(fakeCredhubClient.GenerateCredentialCallCount()).To(Equal(1))
+			_, name, _, mode := fakeCredhubClient.GenerateCredentialArgsForCall(0)
+			Expect(name).To(Equal("/some/cred"))
+			Expect(mode).To(Equal(credhub.GenerateModeNoOverwrite))
+			Expect(fakeCredhubClient.GetCredentialByNameCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when generating fails", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.GenerateCredentialReturns(credhub.Credential{}, errors.New("some-error"))
+
+			Expect(execute("/some/cred", "--type", "rsa")).To(MatchError("failed to generate /some/cred: some-error"))
+		})
+	})
+
+	Context("when the flags are invalid", func() {
+		for _, c := range []struct {
+			description   string
+			args          []string
+			expectedError string
+		}{
+			{"no path is provided", []string{"--type", "password"}, "must provide a credential path"},
+			{"no type is provided", []string{"/some/cred"}, "must provide '--type'"},
+			{"the type cannot be generated", []string{"/some/cred", "--type", "value"}, "invalid type 'value': must be one of password, certificate, rsa, ssh"},
+			{"a flag does not apply to the type", []string{"/some/cred", "--type", "password", "--key-length", "2048"}, "'--key-length' is not valid for type 'password'"},
+			{"a certificate has no subject", []string{"/some/cred", "--type", "certificate", "--self-sign"}, "must provide '--common-name' or '--alternative-name' for type 'certificate'"},
+			{"a certificate has no signer", []string{"/some/cred", "--type", "certificate", "--common-name", "example.com"}, "must provide '--ca', '--is-ca' or '--self-sign' for type 'certificate'"},
+		} {
+			c := c
+			It("returns an error when "+c.description, func() {
+				Expect(execute(c.args...)).To(MatchError(c.expectedError))
+				Expect(fakeCredhubClient.GenerateCredentialCallCount()).To(Equal(0))
+			})
+		}
+	})
+})
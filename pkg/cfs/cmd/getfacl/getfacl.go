@@ -0,0 +1,105 @@
+package getfacl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/spf13/cobra"
+)
+
+type cmdGetfaclRunner struct {
+	ctx           context.Context
+	credhubClient credhubClient
+	actors        []string
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+func NewCmdGetfacl(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "getfacl PATH",
+		Short: "Prints the permissions granted on a credential path",
+		Long: "Prints the permissions granted on a credential path.\n\n" +
+			"CredHub can only list every permission on a single existing credential. " +
+			"For a wildcard path such as '/some/path/*', name the actors to show with '--actor'.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("must provide a credential path")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			actors, _ := cmd.Flags().GetStringArray("actor")
+
+			if strings.Contains(args[0], "*") && len(actors) == 0 {
+				return fmt.Errorf("'%s': CredHub cannot list permissions on a wildcard path; use '--actor'", args[0])
+			}
+
+			cmd.SilenceUsage = true
+
+			c := &cmdGetfaclRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+				actors:        actors,
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	cmd.Flags().StringArrayP("actor", "a", nil, "only show the permission of this actor; can be repeated")
+
+	return cmd
+}
+
+func (c *cmdGetfaclRunner) Run(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	permissions, err := c.permissions(path)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(permissions, func(i, j int) bool {
+		return permissions[i].Actor < permissions[j].Actor
+	})
+
+	fmt.Fprintf(cmd.OutOrStdout(), "# path: %s\n", path)
+	for _, permission := range permissions {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s:%s\n", permission.Actor, strings.Join(permission.Operations, ","))
+	}
+
+	return nil
+}
+
+// permissions lists every permission on a credential, or only those of the
+// given actors, which also works for wildcard paths.
+func (c *cmdGetfaclRunner) permissions(path string) ([]credhub.Permission, error) {
+	if len(c.actors) == 0 {
+		permissions, err := c.credhubClient.GetPermissions(c.ctx, path)
+		if err != nil {
+			if _, isNotFoundError := err.(*credhub.ErrCredentialNotFound); isNotFoundError {
+				return nil, fmt.Errorf("'%s': no such credential", path)
+			}
+			return nil, fmt.Errorf("failed to get permissions for %s: %s", path, err.Error())
+		}
+		return permissions, nil
+	}
+
+	var permissions []credhub.Permission
+	for _, actor := range c.actors {
+		permission, err := c.credhubClient.GetPermission(c.ctx, path, actor)
+		if err != nil {
+			if _, isNotFoundError := err.(*credhub.ErrPermissionNotFound); isNotFoundError {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get permission for %s on %s: %s", actor, path, err.Error())
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions, nil
+}
@@ -0,0 +1,13 @@
+package getfacl_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestGetfacl(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Getfacl Suite")
+}
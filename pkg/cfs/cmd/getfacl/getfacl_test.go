@@ -0,0 +1,139 @@
+package getfacl_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/getfacl"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/getfacl/getfaclfakes"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+var _ = Describe("Getfacl", func() {
+	var fakeCredhubClient *getfaclfakes.FakeCredhubClient
+	var dependencies cmdutil.Dependencies
+
+	BeforeEach(func() {
+		fakeCredhubClient = &getfaclfakes.FakeCredhubClient{}
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetCredhubClient(fakeCredhubClient)
+	})
+
+	It("prints the permissions on the credential sorted by actor", func() {
+		fakeCredhubClient.GetPermissionsReturns([]credhub.Permission{
+			{Actor: "uaa-client:b", Path: "/some/cred", Operations: []string{"read"}},
+			{Actor: "uaa-client:a", Path: "/some/cred", Operations: []string{"read", "write"}},
+		}, nil)
+
+		var output bytes.Buffer
+		cmd := getfacl.NewCmdGetfacl(dependencies)
+		cmd.SetArgs([]string{"/some/cred"})
+		cmd.SetOutput(&output)
+
+		Expect(cmd.Execute()).To(Succeed())
+
+		Expect(fakeCredhubClient.GetPermissionsCallCount()).To(Equal(1))
+		_, requestedPath := fakeCredhubClient.GetPermissionsArgsForCall(0)
+		Expect(requestedPath).To(Equal("/some/cred"))
+		Expect(output.String()).To(Equal(
+			"# path: /some/cred\n" +
+				"uaa-client:a:read,write\n" +
+				"uaa-client:b:read\n",
+		))
+	})
+
+	Context("when actors are provided", func() {
+		It("prints the permissions of those actors on a wildcard path", func() {
+			fakeCredhubClient.GetPermissionStub = func(_ context.Context, path, actor string) (credhub.Permission, error) {
+				switch actor {
+				case "uaa-client:a":
+					return credhub.Permission{Actor: actor, Path: path, Operations: []string{"read", "write"}}, nil
+				case "uaa-client:b":
+					return credhub.Permission{Actor: actor, Path: path, Operations: []string{"read"}}, nil
+				default:
+					return credhub.Permission{}, &credhub.ErrPermissionNotFound{}
+				}
+			}
+
+			var output bytes.Buffer
+			cmd := getfacl.NewCmdGetfacl(dependencies)
+			cmd.SetArgs([]string{"/some/*", "-a", "uaa-client:b", "--actor", "uaa-client:c", "-a", "uaa-client:a"})
+			cmd.SetOutput(&output)
+
+			Expect(cmd.Execute()).To(Succeed())
+
+			Expect(fakeCredhubClient.GetPermissionsCallCount()).To(Equal(0))
+			Expect(fakeCredhubClient.GetPermissionCallCount()).To(Equal(3))
+			_, requestedPath, _ := fakeCredhubClient.GetPermissionArgsForCall(0)
+			Expect(requestedPath).To(Equal("/some/*"))
+			Expect(output.String()).To(Equal(
+				"# path: /some/*\n" +
+					"uaa-client:a:read,write\n" +
+					"uaa-client:b:read\n",
+			))
+		})
+
+		Context("when getting a permission fails", func() {
+			It("returns an error", func() {
+				fakeCredhubClient.GetPermissionReturns(credhub.Permission{}, errors.New("some-error"))
+
+				cmd := getfacl.NewCmdGetfacl(dependencies)
+				cmd.SetArgs([]string{"/some/*", "-a", "uaa-client:a"})
+				cmd.SetOutput(ioutil.Discard)
+
+				Expect(cmd.Execute()).To(MatchError("failed to get permission for uaa-client:a on /some/*: some-error"))
+			})
+		})
+	})
+
+	Context("when the path is a wildcard and no actors are provided", func() {
+		It("returns an error", func() {
+			cmd := getfacl.NewCmdGetfacl(dependencies)
+			cmd.SetArgs([]string{"/some/*"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("'/some/*': CredHub cannot list permissions on a wildcard path; use '--actor'"))
+			Expect(fakeCredhubClient.GetPermissionsCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when no path is provided", func() {
+		It("returns an error", func() {
+			cmd := getfacl.NewCmdGetfacl(dependencies)
+			cmd.SetArgs([]string{})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("must provide a credential path"))
+		})
+	})
+
+	Context("when the credential does not exist", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.GetPermissionsReturns(nil, &credhub.ErrCredentialNotFound{})
+
+			cmd := getfacl.NewCmdGetfacl(dependencies)
+			cmd.SetArgs([]string{"/some/cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("'/some/cred': no such credential"))
+		})
+	})
+
+	Context("when getting permissions fails", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.GetPermissionsReturns(nil, errors.New("some-error"))
+
+			cmd := getfacl.NewCmdGetfacl(dependencies)
+			cmd.SetArgs([]string{"/some/cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("failed to get permissions for /some/cred: some-error"))
+		})
+	})
+})
@@ -0,0 +1,217 @@
+package grep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/spf13/cobra"
+)
+
+type cmdGrepRunner struct {
+	ctx            context.Context
+	credhubClient  credhubClient
+	recursive      bool
+	namesOnly      bool
+	ignoreCase     bool
+	extendedRegexp bool
+	showValues     bool
+	concurrency    int
+}
+
+type grepResult struct {
+	name  string
+	lines []string
+	err   error
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+func NewCmdGrep(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "grep PATTERN PATH",
+		Short: "Print the names of credentials whose values contain PATTERN",
+		Long: "Print the names of credentials whose values contain PATTERN. PATTERN is a fixed string unless '-E' is given. " +
+			"Structured credentials are searched in the same form that 'cfs cat' prints them.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("must provide a pattern and a credential path")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			recursive, _ := cmd.Flags().GetBool("recursive")
+			namesOnly, _ := cmd.Flags().GetBool("files-with-matches")
+			ignoreCase, _ := cmd.Flags().GetBool("ignore-case")
+			extendedRegexp, _ := cmd.Flags().GetBool("extended-regexp")
+			showValues, _ := cmd.Flags().GetBool("show-values")
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+
+			if concurrency < 1 {
+				return errors.New("'--concurrency' must be at least 1")
+			}
+
+			cmd.SilenceUsage = true
+
+			c := &cmdGrepRunner{
+				ctx:            dependencies.GetContext(),
+				credhubClient:  dependencies.GetCredhubClient(),
+				recursive:      recursive,
+				namesOnly:      namesOnly,
+				ignoreCase:     ignoreCase,
+				extendedRegexp: extendedRegexp,
+				showValues:     showValues,
+				concurrency:    concurrency,
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	cmd.Flags().BoolP("recursive", "r", false, "search every credential under PATH")
+	cmd.Flags().BoolP("files-with-matches", "l", false, "print only the names of matching credentials")
+	cmd.Flags().BoolP("ignore-case", "i", false, "ignore case when matching")
+	cmd.Flags().BoolP("extended-regexp", "E", false, "interpret PATTERN as a regular expression")
+	cmd.Flags().Bool("show-values", false, "print matching lines of credential values")
+	cmd.Flags().Int("concurrency", 10, "maximum number of credentials to fetch at once")
+
+	return cmd
+}
+
+func (c *cmdGrepRunner) Run(cmd *cobra.Command, args []string) error {
+	pattern, err := c.compilePattern(args[0])
+	if err != nil {
+		return err
+	}
+
+	path := args[1]
+	if path[0] != '/' {
+		path = "/" + path
+	}
+
+	names, err := c.credentialNames(path)
+	if err != nil {
+		return err
+	}
+
+	results := c.search(names, pattern)
+
+	failures := 0
+	for _, result := range results {
+		if result.err != nil {
+			fmt.Fprintf(cmd.OutOrStderr(), "failed to search %s: %s\n", result.name, result.err.Error())
+			failures++
+			continue
+		}
+		if len(result.lines) == 0 {
+			continue
+		}
+
+		if c.showValues && !c.namesOnly {
+			for _, line := range result.lines {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s:%s\n", result.name, line)
+			}
+		} else {
+			fmt.Fprintln(cmd.OutOrStdout(), result.name)
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("failed to search %d of %d credentials", failures, len(results))
+	}
+	return nil
+}
+
+func (c *cmdGrepRunner) compilePattern(pattern string) (*regexp.Regexp, error) {
+	if !c.extendedRegexp {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if c.ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %s", err.Error())
+	}
+	return compiled, nil
+}
+
+func (c *cmdGrepRunner) credentialNames(path string) ([]string, error) {
+	credentials, err := c.credhubClient.FindCredentialsByPath(c.ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find credentials: %s", err.Error())
+	}
+
+	if len(credentials) > 0 {
+		if !c.recursive {
+			return nil, fmt.Errorf("'%s': is a path, use '-r' to search it", path)
+		}
+
+		var names []string
+		for _, credential := range credentials {
+			names = append(names, credential.Name)
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+
+	if _, err := c.credhubClient.GetCredentialByName(c.ctx, path); err != nil {
+		switch err.(type) {
+		case *credhub.ErrCredentialNotFound:
+			return nil, fmt.Errorf("'%s': no such credential or path", path)
+		default:
+			return nil, fmt.Errorf("failed to get credential: %s", err.Error())
+		}
+	}
+	return []string{path}, nil
+}
+
+// search fetches and searches the named credentials, at most c.concurrency
+// at a time. Results are returned in the same order as names.
+func (c *cmdGrepRunner) search(names []string, pattern *regexp.Regexp) []grepResult {
+	results := make([]grepResult, len(names))
+	semaphore := make(chan struct{}, c.concurrency)
+	var wg sync.WaitGroup
+
+	for i, name := range names {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, name string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			results[i] = grepResult{name: name}
+			results[i].lines, results[i].err = c.searchCredential(name, pattern)
+		}(i, name)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func (c *cmdGrepRunner) searchCredential(name string, pattern *regexp.Regexp) ([]string, error) {
+	credential, err := c.credhubClient.GetCredentialByName(c.ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credential: %s", err.Error())
+	}
+
+	value, err := credhub.FormatValue(credential.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	var matchingLines []string
+	for _, line := range strings.Split(value, "\n") {
+		if pattern.MatchString(line) {
+			matchingLines = append(matchingLines, line)
+		}
+	}
+	return matchingLines, nil
+}
@@ -0,0 +1,13 @@
+package grep_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestGrep(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Grep Suite")
+}
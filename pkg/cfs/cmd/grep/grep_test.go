@@ -0,0 +1,180 @@
+package grep_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/grep"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/grep/grepfakes"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+var _ = Describe("Grep", func() {
+	var (
+		fakeCredhubClient *grepfakes.FakeCredhubClient
+		dependencies      cmdutil.Dependencies
+		credentials       map[string]credhub.Credential
+		runGrep           func(args ...string) (string, error)
+	)
+
+	BeforeEach(func() {
+		fakeCredhubClient = &grepfakes.FakeCredhubClient{}
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetCredhubClient(fakeCredhubClient)
+
+		credentials = map[string]credhub.Credential{
+			"/some-dir/password": {Type: "password", Value: credhub.Password("Leaked-Secret")},
+			"/some-dir/user":     {Type: "user", Value: credhub.User{Username: "admin", Password: "leaked-secret"}},
+			"/some-dir/json":     {Type: "json", Value: credhub.JSON{"token": "leaked-secret-123"}},
+			"/some-dir/other":    {Type: "value", Value: credhub.Value("something-else")},
+		}
+		fakeCredhubClient.FindCredentialsByPathStub = func(_ context.Context, path string) ([]credhub.Credential, error) {
+			if path != "/some-dir" {
+				return nil, nil
+			}
+			var found []credhub.Credential
+			for name := range credentials {
+				found = append(found, credhub.Credential{Name: name})
+			}
+			return found, nil
+		}
+		fakeCredhubClient.GetCredentialByNameStub = func(_ context.Context, name string) (credhub.Credential, error) {
+			credential, ok := credentials[name]
+			if !ok {
+				return credhub.Credential{}, &credhub.ErrCredentialNotFound{}
+			}
+			credential.Name = name
+			return credential, nil
+		}
+
+		runGrep = func(args ...string) (string, error) {
+			var output bytes.Buffer
+			cmd := grep.NewCmdGrep(dependencies)
+			cmd.SetArgs(args)
+			cmd.SetOutput(&output)
+			err := cmd.Execute()
+			return output.String(), err
+		}
+	})
+
+	It("prints the sorted names of matching credentials, including structured ones", func() {
+		output, err := runGrep("-r", "leaked-secret", "/some-dir")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("/some-dir/json\n/some-dir/user\n"))
+	})
+
+	It("ignores case with '-i'", func() {
+		output, err := runGrep("-r", "-i", "leaked-secret", "/some-dir")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("/some-dir/json\n/some-dir/password\n/some-dir/user\n"))
+	})
+
+	It("treats the pattern as a fixed string without '-E'", func() {
+		output, err := runGrep("-r", "leaked-secret-[0-9]+", "/some-dir")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(BeEmpty())
+	})
+
+	It("treats the pattern as a regular expression with '-E'", func() {
+		output, err := runGrep("-r", "-E", "leaked-secret-[0-9]+", "/some-dir")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("/some-dir/json\n"))
+	})
+
+	It("prints matching lines with '--show-values'", func() {
+		output, err := runGrep("-r", "--show-values", "leaked-secret", "/some-dir")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("/some-dir/json:  \"token\": \"leaked-secret-123\"\n/some-dir/user:password: leaked-secret\n"))
+	})
+
+	It("prints only names with '-l', even with '--show-values'", func() {
+		output, err := runGrep("-r", "-l", "--show-values", "leaked-secret", "/some-dir")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("/some-dir/json\n/some-dir/user\n"))
+	})
+
+	It("searches a single credential", func() {
+		output, err := runGrep("-i", "leaked", "/some-dir/password")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("/some-dir/password\n"))
+	})
+
+	It("fetches at most '--concurrency' credentials at once", func() {
+		for i := 0; i < 20; i++ {
+			credentials["/some-dir/cred-"+string(rune('a'+i))] = credhub.Credential{Type: "value", Value: credhub.Value("some-value")}
+		}
+		var inFlight, maxInFlight int32
+		fakeCredhubClient.GetCredentialByNameStub = func(_ context.Context, name string) (credhub.Credential, error) {
+			current := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return credhub.Credential{Name: name, Type: "value", Value: credhub.Value("some-value")}, nil
+		}
+
+		_, err := runGrep("-r", "--concurrency", "3", "some-value", "/some-dir")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeCredhubClient.GetCredentialByNameCallCount()).To(Equal(len(credentials)))
+		Expect(atomic.LoadInt32(&maxInFlight)).To(BeNumerically("<=", 3))
+		Expect(atomic.LoadInt32(&maxInFlight)).To(BeNumerically(">", 1))
+	})
+
+	Context("when the path contains credentials and '-r' is not provided", func() {
+		It("returns an error", func() {
+			_, err := runGrep("leaked-secret", "/some-dir")
+			Expect(err).To(MatchError("'/some-dir': is a path, use '-r' to search it"))
+		})
+	})
+
+	Context("when the path does not exist", func() {
+		It("returns an error", func() {
+			_, err := runGrep("-r", "leaked-secret", "/missing")
+			Expect(err).To(MatchError("'/missing': no such credential or path"))
+		})
+	})
+
+	Context("when fetching some credentials fails", func() {
+		It("prints the failures, keeps searching and returns an error", func() {
+			fakeCredhubClient.GetCredentialByNameStub = func(_ context.Context, name string) (credhub.Credential, error) {
+				if name == "/some-dir/other" {
+					return credhub.Credential{}, errors.New("some-error")
+				}
+				credential := credentials[name]
+				credential.Name = name
+				return credential, nil
+			}
+
+			output, err := runGrep("-r", "leaked-secret", "/some-dir")
+			Expect(err).To(MatchError("failed to search 1 of 4 credentials"))
+			Expect(output).To(ContainSubstring("/some-dir/json\n"))
+			Expect(output).To(ContainSubstring("failed to search /some-dir/other: failed to get credential: some-error\n"))
+			Expect(output).To(ContainSubstring("/some-dir/user\n"))
+		})
+	})
+
+	Context("when the pattern is an invalid regular expression", func() {
+		It("returns an error", func() {
+			_, err := runGrep("-r", "-E", "(", "/some-dir")
+			Expect(err).To(MatchError(HavePrefix("invalid pattern: ")))
+		})
+	})
+
+	Context("when '--concurrency' is less than 1", func() {
+		It("returns an error", func() {
+			_, err := runGrep("-r", "--concurrency", "0", "leaked-secret", "/some-dir")
+			Expect(err).To(MatchError("'--concurrency' must be at least 1"))
+		})
+	})
+})
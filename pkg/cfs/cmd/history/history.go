@@ -0,0 +1,74 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/spf13/cobra"
+)
+
+type cmdHistoryRunner struct {
+	ctx           context.Context
+	credhubClient credhubClient
+	n             int
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+func NewCmdHistory(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history /path/to/credential",
+		Short: "List the versions of a credential",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("must provide a credential path")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			n, _ := cmd.Flags().GetInt("n")
+
+			cmd.SilenceUsage = true
+
+			c := &cmdHistoryRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+				n:             n,
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	cmd.Flags().IntP("n", "n", 0, "number of versions to list (default all)")
+
+	return cmd
+}
+
+func (c *cmdHistoryRunner) Run(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	versions, err := c.credhubClient.GetCredentialVersions(c.ctx, name, c.n)
+	if err != nil {
+		switch err.(type) {
+		case *credhub.ErrCredentialNotFound:
+			return fmt.Errorf("'%s': no such credential or path", name)
+		default:
+			return fmt.Errorf("failed to get credential versions: %s", err.Error())
+		}
+	}
+
+	if len(versions) == 0 {
+		return fmt.Errorf("'%s': no such credential or path", name)
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	for i, version := range versions {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", i, version.ID, version.VersionCreatedAt.Format(time.RFC3339))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,104 @@
+package history_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/google/uuid"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/history"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/history/historyfakes"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+var _ = Describe("History", func() {
+	var fakeCredhubClient *historyfakes.FakeCredhubClient
+	var dependencies cmdutil.Dependencies
+
+	BeforeEach(func() {
+		fakeCredhubClient = &historyfakes.FakeCredhubClient{}
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetCredhubClient(fakeCredhubClient)
+	})
+
+	It("lists the versions of a credential, newest first", func() {
+		newID := uuid.New()
+		oldID := uuid.New()
+		newDate := time.Date(2019, 4, 2, 10, 30, 0, 0, time.UTC)
+		oldDate := time.Date(2019, 3, 1, 8, 0, 0, 0, time.UTC)
+		fakeCredhubClient.GetCredentialVersionsReturns([]credhub.Credential{
+			{ID: newID, VersionCreatedAt: newDate},
+			{ID: oldID, VersionCreatedAt: oldDate},
+		}, nil)
+
+		var output bytes.Buffer
+		cmd := history.NewCmdHistory(dependencies)
+		cmd.SetArgs([]string{"/some/cred"})
+		cmd.SetOutput(&output)
+
+		Expect(cmd.Execute()).To(Succeed())
+
+		Expect(output.String()).To(Equal(fmt.Sprintf(
+			"0  %s  2019-04-02T10:30:00Z\n1  %s  2019-03-01T08:00:00Z\n",
+			newID, oldID,
+		)))
+		_, name, n := fakeCredhubClient.GetCredentialVersionsArgsForCall(0)
+		Expect(name).To(Equal("/some/cred"))
+		Expect(n).To(Equal(0))
+	})
+
+	It("limits the number of versions listed with `n`", func() {
+		fakeCredhubClient.GetCredentialVersionsReturns([]credhub.Credential{{ID: uuid.New()}}, nil)
+
+		cmd := history.NewCmdHistory(dependencies)
+		cmd.SetArgs([]string{"-n", "1", "/some/cred"})
+		cmd.SetOutput(ioutil.Discard)
+
+		Expect(cmd.Execute()).To(Succeed())
+
+		_, _, n := fakeCredhubClient.GetCredentialVersionsArgsForCall(0)
+		Expect(n).To(Equal(1))
+	})
+
+	Context("when no credential is found", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.GetCredentialVersionsReturns(nil, &credhub.ErrCredentialNotFound{})
+
+			cmd := history.NewCmdHistory(dependencies)
+			cmd.SetArgs([]string{"/some-path"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("'/some-path': no such credential or path"))
+			Expect(cmd.SilenceUsage).To(BeTrue())
+		})
+	})
+
+	Context("when getting the versions fails", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.GetCredentialVersionsReturns(nil, errors.New("some-error"))
+
+			cmd := history.NewCmdHistory(dependencies)
+			cmd.SetArgs([]string{"/some-path"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("failed to get credential versions: some-error"))
+		})
+	})
+
+	Context("when no arguments are provided", func() {
+		It("returns an error and shows the usage", func() {
+			cmd := history.NewCmdHistory(dependencies)
+			cmd.SetArgs([]string{})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("must provide a credential path"))
+			Expect(cmd.SilenceUsage).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,44 @@
+package login
+
+import (
+	"context"
+	"fmt"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/spf13/cobra"
+)
+
+type cmdLoginRunner struct {
+	ctx           context.Context
+	credhubClient credhubClient
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+func NewCmdLogin(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Authenticates with the current target and caches the access token",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			c := &cmdLoginRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	return cmd
+}
+
+func (c *cmdLoginRunner) Run(cmd *cobra.Command, args []string) error {
+	if err := c.credhubClient.Authenticate(c.ctx); err != nil {
+		return fmt.Errorf("failed to log in: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package login_test
+
+import (
+	"errors"
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/login"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/login/loginfakes"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+)
+
+var _ = Describe("Login", func() {
+	var fakeCredhubClient *loginfakes.FakeCredhubClient
+	var dependencies cmdutil.Dependencies
+
+	BeforeEach(func() {
+		fakeCredhubClient = &loginfakes.FakeCredhubClient{}
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetCredhubClient(fakeCredhubClient)
+	})
+
+	It("authenticates", func() {
+		cmd := login.NewCmdLogin(dependencies)
+		cmd.SetArgs([]string{})
+		cmd.SetOutput(ioutil.Discard)
+
+		Expect(cmd.Execute()).To(Succeed())
+		Expect(fakeCredhubClient.AuthenticateCallCount()).To(Equal(1))
+	})
+
+	Context("when authenticating fails", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.AuthenticateReturns(errors.New("some-error"))
+
+			cmd := login.NewCmdLogin(dependencies)
+			cmd.SetArgs([]string{})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("failed to log in: some-error"))
+		})
+	})
+})
@@ -0,0 +1,42 @@
+package logout
+
+import (
+	"errors"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/tokencache"
+	"github.com/spf13/cobra"
+)
+
+type cmdLogoutRunner struct {
+	tokenCache *tokencache.Cache
+}
+
+func NewCmdLogout(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Removes cached access tokens",
+		Args:  cobra.NoArgs,
+		// Logging out does not talk to CredHub, so it does not need the
+		// credentials required by the root command.
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			c := &cmdLogoutRunner{
+				tokenCache: dependencies.GetTokenCache(),
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	return cmd
+}
+
+func (c *cmdLogoutRunner) Run(cmd *cobra.Command, args []string) error {
+	if c.tokenCache == nil {
+		return errors.New("could not determine the location of the token cache")
+	}
+
+	return c.tokenCache.Clear()
+}
@@ -0,0 +1,13 @@
+package logout_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestLogout(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Logout Suite")
+}
@@ -0,0 +1,60 @@
+package logout_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/logout"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/pkg/tokencache"
+)
+
+var _ = Describe("Logout", func() {
+	var (
+		tempDir      string
+		dependencies cmdutil.Dependencies
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "logout")
+		Expect(err).NotTo(HaveOccurred())
+		dependencies = cmdutil.NewDependencies()
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	It("removes cached tokens", func() {
+		tokenCache := tokencache.New(filepath.Join(tempDir, "tokens.json"))
+		Expect(tokenCache.SetToken("some-addr", "some-client", credhub.Token{
+			AccessToken: "some-token",
+			ExpiresAt:   time.Now().Add(time.Hour),
+		})).To(Succeed())
+		dependencies.SetTokenCache(tokenCache)
+
+		cmd := logout.NewCmdLogout(dependencies)
+		cmd.SetArgs([]string{})
+		cmd.SetOutput(ioutil.Discard)
+
+		Expect(cmd.Execute()).To(Succeed())
+		Expect(filepath.Join(tempDir, "tokens.json")).NotTo(BeAnExistingFile())
+	})
+
+	Context("when there is no token cache", func() {
+		It("returns an error", func() {
+			cmd := logout.NewCmdLogout(dependencies)
+			cmd.SetArgs([]string{})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("could not determine the location of the token cache"))
+		})
+	})
+})
@@ -1,6 +1,7 @@
 package ls
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"sort"
@@ -13,6 +14,7 @@ import (
 )
 
 type cmdLsRunner struct {
+	ctx           context.Context
 	credhubClient credhubClient
 	formatLong    bool
 	formatOne     bool
@@ -32,6 +34,7 @@ func NewCmdLs(dependencies cmdutil.Dependencies) *cobra.Command {
 			cmd.SilenceUsage = true
 
 			c := &cmdLsRunner{
+				ctx:           dependencies.GetContext(),
 				credhubClient: dependencies.GetCredhubClient(),
 				formatLong:    formatLong,
 				formatOne:     formatOne,
@@ -56,13 +59,13 @@ func (c *cmdLsRunner) Run(cmd *cobra.Command, args []string) error {
 		path = "/" + path
 	}
 
-	credentials, err := c.credhubClient.FindCredentialsByPath(path)
+	credentials, err := c.credhubClient.FindCredentialsByPath(c.ctx, path)
 	if err != nil {
 		return fmt.Errorf("failed to list credentials: %s", err.Error())
 	}
 
 	if len(credentials) == 0 && path != "/" {
-		credential, err := c.credhubClient.GetCredentialByName(path)
+		credential, err := c.credhubClient.GetCredentialByName(c.ctx, path)
 		if err != nil {
 			switch err.(type) {
 			case *credhub.ErrCredentialNotFound:
@@ -92,7 +95,7 @@ func (c *cmdLsRunner) formatCredentialPathsAndDates(credentials []credhub.Creden
 		credentialOutput := credential.Name
 		if credentialOutput != path {
 			if strings.Count(credentialOutput, "/") > 1 {
-				name := strings.TrimPrefix(credentialOutput, strings.TrimSuffix(path, "/"))
+				name := cmdutil.RelativeName(credentialOutput, path)
 				credentialOutput = filepath.Join(path, strings.Split(name, "/")[1])
 				if strings.Count(name, "/") > 1 {
 					credentialOutput = credentialOutput + "/"
@@ -43,7 +43,8 @@ var _ = Describe("Ls", func() {
 
 		Expect(output.String()).To(Equal("/some-cred  /some-dir/  /some-other-dir/\n"))
 		Expect(fakeCredhubClient.FindCredentialsByPathCallCount()).To(Equal(1))
-		Expect(fakeCredhubClient.FindCredentialsByPathArgsForCall(0)).To(Equal("/"))
+		_, searchedPath := fakeCredhubClient.FindCredentialsByPathArgsForCall(0)
+		Expect(searchedPath).To(Equal("/"))
 	})
 
 	Context("when a path is specified", func() {
@@ -65,7 +66,8 @@ var _ = Describe("Ls", func() {
 
 			Expect(output.String()).To(Equal("/some-dir/cred1  /some-dir/cred2  /some-dir/some-nested-dir/\n"))
 			Expect(fakeCredhubClient.FindCredentialsByPathCallCount()).To(Equal(1))
-			Expect(fakeCredhubClient.FindCredentialsByPathArgsForCall(0)).To(Equal(path))
+			_, searchedPath := fakeCredhubClient.FindCredentialsByPathArgsForCall(0)
+			Expect(searchedPath).To(Equal(path))
 		})
 	})
 
@@ -88,7 +90,8 @@ var _ = Describe("Ls", func() {
 
 			Expect(output.String()).To(Equal("/some-dir/cred1  /some-dir/cred2  /some-dir/some-nested-dir/\n"))
 			Expect(fakeCredhubClient.FindCredentialsByPathCallCount()).To(Equal(1))
-			Expect(fakeCredhubClient.FindCredentialsByPathArgsForCall(0)).To(Equal(path))
+			_, searchedPath := fakeCredhubClient.FindCredentialsByPathArgsForCall(0)
+			Expect(searchedPath).To(Equal(path))
 		})
 	})
 
@@ -111,7 +114,8 @@ var _ = Describe("Ls", func() {
 
 			Expect(output.String()).To(Equal("/some-dir/cred1  /some-dir/cred2  /some-dir/some-nested-dir/\n"))
 			Expect(fakeCredhubClient.FindCredentialsByPathCallCount()).To(Equal(1))
-			Expect(fakeCredhubClient.FindCredentialsByPathArgsForCall(0)).To(Equal("/" + path))
+			_, searchedPath := fakeCredhubClient.FindCredentialsByPathArgsForCall(0)
+			Expect(searchedPath).To(Equal("/" + path))
 		})
 	})
 
@@ -132,9 +136,11 @@ var _ = Describe("Ls", func() {
 			Expect(output.String()).To(Equal("/some-cred\n"))
 
 			Expect(fakeCredhubClient.FindCredentialsByPathCallCount()).To(Equal(1))
-			Expect(fakeCredhubClient.FindCredentialsByPathArgsForCall(0)).To(Equal(path))
+			_, searchedPath := fakeCredhubClient.FindCredentialsByPathArgsForCall(0)
+			Expect(searchedPath).To(Equal(path))
 			Expect(fakeCredhubClient.GetCredentialByNameCallCount()).To(Equal(1))
-			Expect(fakeCredhubClient.GetCredentialByNameArgsForCall(0)).To(Equal(path))
+			_, requestedName := fakeCredhubClient.GetCredentialByNameArgsForCall(0)
+			Expect(requestedName).To(Equal(path))
 		})
 	})
 
@@ -155,9 +161,11 @@ var _ = Describe("Ls", func() {
 			Expect(output.String()).To(Equal("/some-dir/some-cred\n"))
 
 			Expect(fakeCredhubClient.FindCredentialsByPathCallCount()).To(Equal(1))
-			Expect(fakeCredhubClient.FindCredentialsByPathArgsForCall(0)).To(Equal(path))
+			_, searchedPath := fakeCredhubClient.FindCredentialsByPathArgsForCall(0)
+			Expect(searchedPath).To(Equal(path))
 			Expect(fakeCredhubClient.GetCredentialByNameCallCount()).To(Equal(1))
-			Expect(fakeCredhubClient.GetCredentialByNameArgsForCall(0)).To(Equal(path))
+			_, requestedName := fakeCredhubClient.GetCredentialByNameArgsForCall(0)
+			Expect(requestedName).To(Equal(path))
 		})
 	})
 
@@ -0,0 +1,88 @@
+package mount
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/pkg/credhubfuse"
+	"github.com/spf13/cobra"
+)
+
+type cmdMountRunner struct {
+	ctx           context.Context
+	credhubClient credhubClient
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+func NewCmdMount(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mount MOUNTPOINT",
+		Short: "Mount CredHub as a FUSE filesystem until interrupted",
+		Long: "Mount CredHub as a FUSE filesystem until interrupted.\n\n" +
+			"Only value, password and json credentials can be written; other credential types are read-only. " +
+			"CredHub cannot store empty credentials, so empty files are not supported: a new file is only saved " +
+			"once it has contents, and truncating a credential to empty fails when the file is closed.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("must provide a mountpoint")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			c := &cmdMountRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	return cmd
+}
+
+func (c *cmdMountRunner) Run(cmd *cobra.Command, args []string) error {
+	mountpoint := args[0]
+
+	info, err := os.Stat(mountpoint)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("'%s': no such directory", mountpoint)
+		}
+		return fmt.Errorf("failed to stat mountpoint: %s", err.Error())
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("'%s': not a directory", mountpoint)
+	}
+
+	conn, err := fuse.Mount(mountpoint, fuse.FSName("credhub"), fuse.Subtype("cfs"))
+	if err != nil {
+		return fmt.Errorf("failed to mount %s: %s", mountpoint, err.Error())
+	}
+	defer conn.Close()
+
+	go func() {
+		<-c.ctx.Done()
+		fuse.Unmount(mountpoint)
+	}()
+
+	if err := fs.Serve(conn, credhubfuse.NewFS(c.credhubClient)); err != nil {
+		return fmt.Errorf("failed to serve %s: %s", mountpoint, err.Error())
+	}
+
+	<-conn.Ready
+	if err := conn.MountError; err != nil {
+		return fmt.Errorf("failed to mount %s: %s", mountpoint, err.Error())
+	}
+
+	return nil
+}
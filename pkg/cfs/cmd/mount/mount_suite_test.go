@@ -0,0 +1,13 @@
+package mount_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestMount(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Mount Suite")
+}
@@ -0,0 +1,72 @@
+package mount_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/mount"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/mount/mountfakes"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+)
+
+var _ = Describe("Mount", func() {
+	var (
+		fakeCredhubClient *mountfakes.FakeCredhubClient
+		dependencies      cmdutil.Dependencies
+		tempDir           string
+	)
+
+	BeforeEach(func() {
+		fakeCredhubClient = &mountfakes.FakeCredhubClient{}
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetCredhubClient(fakeCredhubClient)
+
+		var err error
+		tempDir, err = ioutil.TempDir("", "cfs-mount-test")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	Context("when no mountpoint is provided", func() {
+		It("returns an error", func() {
+			cmd := mount.NewCmdMount(dependencies)
+			cmd.SetArgs([]string{})
+			cmd.SetOutput(&bytes.Buffer{})
+
+			Expect(cmd.Execute()).To(MatchError("must provide a mountpoint"))
+		})
+	})
+
+	Context("when the mountpoint does not exist", func() {
+		It("returns an error", func() {
+			mountpoint := filepath.Join(tempDir, "missing")
+
+			cmd := mount.NewCmdMount(dependencies)
+			cmd.SetArgs([]string{mountpoint})
+			cmd.SetOutput(&bytes.Buffer{})
+
+			Expect(cmd.Execute()).To(MatchError("'" + mountpoint + "': no such directory"))
+		})
+	})
+
+	Context("when the mountpoint is not a directory", func() {
+		It("returns an error", func() {
+			mountpoint := filepath.Join(tempDir, "file")
+			Expect(ioutil.WriteFile(mountpoint, nil, 0600)).To(Succeed())
+
+			cmd := mount.NewCmdMount(dependencies)
+			cmd.SetArgs([]string{mountpoint})
+			cmd.SetOutput(&bytes.Buffer{})
+
+			Expect(cmd.Execute()).To(MatchError("'" + mountpoint + "': not a directory"))
+		})
+	})
+})
@@ -0,0 +1,61 @@
+package mv
+
+import (
+	"context"
+	"errors"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/pkg/transfer"
+	"github.com/spf13/cobra"
+)
+
+type cmdMvRunner struct {
+	ctx           context.Context
+	credhubClient credhubClient
+	recursive     bool
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+func NewCmdMv(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mv SOURCE DESTINATION",
+		Short: "Move credentials",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("must provide a source and destination path")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			recursive, _ := cmd.Flags().GetBool("recursive")
+
+			cmd.SilenceUsage = true
+
+			c := &cmdMvRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+				recursive:     recursive,
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	cmd.Flags().BoolP("recursive", "r", false, "recursively move credentials")
+
+	return cmd
+}
+
+func (c *cmdMvRunner) Run(cmd *cobra.Command, args []string) error {
+	transfers, sourceIsDir, err := transfer.Plan(c.ctx, c.credhubClient, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	if sourceIsDir && !c.recursive {
+		return errors.New("not moving recursively without '-r' flag")
+	}
+
+	return transfer.Move(c.ctx, c.credhubClient, transfers)
+}
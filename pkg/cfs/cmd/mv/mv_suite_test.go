@@ -0,0 +1,13 @@
+package mv_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestMv(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Mv Suite")
+}
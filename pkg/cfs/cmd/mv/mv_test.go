@@ -0,0 +1,281 @@
+package mv_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/mv"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/mv/mvfakes"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+var _ = Describe("Mv", func() {
+	var (
+		fakeCredhubClient *mvfakes.FakeCredhubClient
+		dependencies      cmdutil.Dependencies
+		storedValues      map[string]credhub.CredentialValue
+	)
+
+	BeforeEach(func() {
+		fakeCredhubClient = &mvfakes.FakeCredhubClient{}
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetCredhubClient(fakeCredhubClient)
+
+		storedValues = map[string]credhub.CredentialValue{}
+		fakeCredhubClient.GetCredentialByNameStub = func(_ context.Context, name string) (credhub.Credential, error) {
+			value, found := storedValues[name]
+			if !found {
+				return credhub.Credential{}, &credhub.ErrCredentialNotFound{}
+			}
+			return credhub.Credential{Name: name, Value: value}, nil
+		}
+		fakeCredhubClient.FindCredentialsByPathStub = func(_ context.Context, path string) ([]credhub.Credential, error) {
+			var credentials []credhub.Credential
+			for name := range storedValues {
+				if strings.HasPrefix(name, strings.TrimSuffix(path, "/")+"/") {
+					credentials = append(credentials, credhub.Credential{Name: name})
+				}
+			}
+			return credentials, nil
+		}
+		fakeCredhubClient.SetCredentialStub = func(ctx context.Context, credential credhub.Credential) (credhub.Credential, error) {
+			storedValues[credential.Name] = credential.Value
+			return credential, nil
+		}
+		fakeCredhubClient.DeleteCredentialByNameStub = func(_ context.Context, name string) error {
+			if _, found := storedValues[name]; !found {
+				return &credhub.ErrCredentialNotFound{}
+			}
+			delete(storedValues, name)
+			return nil
+		}
+	})
+
+	It("moves a credential", func() {
+		storedValues["/some/cred"] = credhub.Password("some-password")
+
+		cmd := mv.NewCmdMv(dependencies)
+		cmd.SetArgs([]string{"/some/cred", "/other/cred"})
+		cmd.SetOutput(ioutil.Discard)
+
+		Expect(cmd.Execute()).To(Succeed())
+
+		Expect(storedValues).To(Equal(map[string]credhub.CredentialValue{
+			"/other/cred": credhub.Password("some-password"),
+		}))
+	})
+
+	Context("when the source is a directory", func() {
+		BeforeEach(func() {
+			storedValues["/dep-a/cred1"] = credhub.Value("value1")
+			storedValues["/dep-a/nested/cred2"] = credhub.Value("value2")
+		})
+
+		It("moves the directory when `recursive` is true", func() {
+			cmd := mv.NewCmdMv(dependencies)
+			cmd.SetArgs([]string{"-r", "/dep-a", "/dep-b"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(Succeed())
+
+			Expect(storedValues).To(Equal(map[string]credhub.CredentialValue{
+				"/dep-b/cred1":        credhub.Value("value1"),
+				"/dep-b/nested/cred2": credhub.Value("value2"),
+			}))
+		})
+
+		It("returns an error when `recursive` is false", func() {
+			cmd := mv.NewCmdMv(dependencies)
+			cmd.SetArgs([]string{"/dep-a", "/dep-b"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("not moving recursively without '-r' flag"))
+			Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(0))
+			Expect(fakeCredhubClient.DeleteCredentialByNameCallCount()).To(Equal(0))
+		})
+
+		Context("when writing a destination fails", func() {
+			It("removes the written destinations and keeps the sources", func() {
+				storedValues["/dep-b/cred1"] = credhub.Value("existing-value")
+				setCredential := fakeCredhubClient.SetCredentialStub
+				fakeCredhubClient.SetCredentialStub = func(ctx context.Context, credential credhub.Credential) (credhub.Credential, error) {
+					if credential.Name == "/dep-b/nested/cred2" {
+						return credhub.Credential{}, errors.New("some-error")
+					}
+					return setCredential(ctx, credential)
+				}
+
+				cmd := mv.NewCmdMv(dependencies)
+				cmd.SetArgs([]string{"-r", "/dep-a/*", "/dep-b"})
+				cmd.SetOutput(ioutil.Discard)
+
+				Expect(cmd.Execute()).To(MatchError(ContainSubstring("failed to set credential: some-error")))
+
+				Expect(storedValues).To(Equal(map[string]credhub.CredentialValue{
+					"/dep-a/cred1":        credhub.Value("value1"),
+					"/dep-a/nested/cred2": credhub.Value("value2"),
+					"/dep-b/cred1":        credhub.Value("existing-value"),
+				}))
+			})
+		})
+
+		Context("when interrupted while writing destinations", func() {
+			It("rolls back the move", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				dependencies.SetContext(ctx)
+
+				setCredential := fakeCredhubClient.SetCredentialStub
+				fakeCredhubClient.SetCredentialStub = func(ctx context.Context, credential credhub.Credential) (credhub.Credential, error) {
+					if credential.Name == "/dep-b/nested/cred2" {
+						cancel()
+					}
+					if err := ctx.Err(); err != nil {
+						return credhub.Credential{}, err
+					}
+					return setCredential(ctx, credential)
+				}
+
+				cmd := mv.NewCmdMv(dependencies)
+				cmd.SetArgs([]string{"-r", "/dep-a/*", "/dep-b"})
+				cmd.SetOutput(ioutil.Discard)
+
+				Expect(cmd.Execute()).To(MatchError("interrupted before moving any credentials"))
+
+				Expect(storedValues).To(Equal(map[string]credhub.CredentialValue{
+					"/dep-a/cred1":        credhub.Value("value1"),
+					"/dep-a/nested/cred2": credhub.Value("value2"),
+				}))
+			})
+		})
+
+		Context("when interrupted while removing sources", func() {
+			It("reports how many credentials were moved", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				dependencies.SetContext(ctx)
+
+				deleteCredential := fakeCredhubClient.DeleteCredentialByNameStub
+				fakeCredhubClient.DeleteCredentialByNameStub = func(ctx context.Context, name string) error {
+					if fakeCredhubClient.DeleteCredentialByNameCallCount() == 2 {
+						cancel()
+						return ctx.Err()
+					}
+					return deleteCredential(ctx, name)
+				}
+
+				cmd := mv.NewCmdMv(dependencies)
+				cmd.SetArgs([]string{"-r", "/dep-a", "/dep-b"})
+				cmd.SetOutput(ioutil.Discard)
+
+				Expect(cmd.Execute()).To(MatchError(MatchRegexp(`^interrupted after moving 1 of 2 credentials; copied but did not remove /dep-a/(cred1|nested/cred2)$`)))
+				Expect(storedValues).To(HaveLen(3))
+			})
+		})
+
+		Context("when a written destination does not match its source", func() {
+			It("rolls back the move", func() {
+				fakeCredhubClient.SetCredentialStub = func(ctx context.Context, credential credhub.Credential) (credhub.Credential, error) {
+					storedValues[credential.Name] = credhub.Value("some-other-value")
+					return credential, nil
+				}
+
+				cmd := mv.NewCmdMv(dependencies)
+				cmd.SetArgs([]string{"-r", "/dep-a", "/dep-b"})
+				cmd.SetOutput(ioutil.Discard)
+
+				Expect(cmd.Execute()).To(MatchError(ContainSubstring("value does not match source")))
+
+				Expect(storedValues).To(Equal(map[string]credhub.CredentialValue{
+					"/dep-a/cred1":        credhub.Value("value1"),
+					"/dep-a/nested/cred2": credhub.Value("value2"),
+				}))
+			})
+		})
+
+		Context("when rolling back fails", func() {
+			It("returns an error listing the destinations that were not restored", func() {
+				fakeCredhubClient.SetCredentialStub = func(ctx context.Context, credential credhub.Credential) (credhub.Credential, error) {
+					return credhub.Credential{}, errors.New("some-error")
+				}
+				fakeCredhubClient.DeleteCredentialByNameStub = func(_ context.Context, name string) error {
+					return errors.New("some-delete-error")
+				}
+
+				cmd := mv.NewCmdMv(dependencies)
+				cmd.SetArgs([]string{"/dep-a/cred1", "/dep-b/cred1"})
+				cmd.SetOutput(ioutil.Discard)
+
+				Expect(cmd.Execute()).To(MatchError(ContainSubstring("rollback failed: could not restore /dep-b/cred1")))
+			})
+		})
+	})
+
+	Context("when the destination is one of the sources", func() {
+		It("returns an error", func() {
+			storedValues["/some/cred"] = credhub.Value("some-value")
+
+			cmd := mv.NewCmdMv(dependencies)
+			cmd.SetArgs([]string{"/some/cred", "/some/cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("cannot move /some/cred onto /some/cred"))
+			Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when removing a source fails", func() {
+		It("returns an error listing the sources which were not removed", func() {
+			storedValues["/dep-a/cred1"] = credhub.Value("value1")
+			storedValues["/dep-a/cred2"] = credhub.Value("value2")
+			storedValues["/dep-a/cred3"] = credhub.Value("value3")
+			fakeCredhubClient.FindCredentialsByPathReturns([]credhub.Credential{
+				{Name: "/dep-a/cred1"},
+				{Name: "/dep-a/cred2"},
+				{Name: "/dep-a/cred3"},
+			}, nil)
+			fakeCredhubClient.FindCredentialsByPathStub = nil
+			deleteCredential := fakeCredhubClient.DeleteCredentialByNameStub
+			fakeCredhubClient.DeleteCredentialByNameStub = func(ctx context.Context, name string) error {
+				if name == "/dep-a/cred2" {
+					return errors.New("some-error")
+				}
+				return deleteCredential(ctx, name)
+			}
+
+			cmd := mv.NewCmdMv(dependencies)
+			cmd.SetArgs([]string{"-r", "/dep-a", "/dep-b"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("failed to remove /dep-a/cred2: some-error; stopped after moving 1 of 3 credentials; copied but did not remove /dep-a/cred2, /dep-a/cred3"))
+		})
+	})
+
+	Context("when the source does not exist", func() {
+		It("returns an error", func() {
+			cmd := mv.NewCmdMv(dependencies)
+			cmd.SetArgs([]string{"/some-path", "/other-path"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("'/some-path': no such credential or path"))
+			Expect(cmd.SilenceUsage).To(BeTrue())
+		})
+	})
+
+	Context("when the wrong number of arguments are provided", func() {
+		It("returns an error and shows the usage", func() {
+			cmd := mv.NewCmdMv(dependencies)
+			cmd.SetArgs([]string{"/some-path"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("must provide a source and destination path"))
+			Expect(cmd.SilenceUsage).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,85 @@
+package regenerate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/spf13/cobra"
+)
+
+type cmdRegenerateRunner struct {
+	ctx           context.Context
+	credhubClient credhubClient
+	signedBy      string
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+func NewCmdRegenerate(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "regenerate [PATH | --signed-by CA]",
+		Short: "Regenerate a credential, or every certificate signed by a CA, and print the regenerated credentials",
+		Args: func(cmd *cobra.Command, args []string) error {
+			signedBy, _ := cmd.Flags().GetString("signed-by")
+			if len(args) > 1 {
+				return errors.New("must provide at most one credential path")
+			}
+			if len(args) == 0 && signedBy == "" {
+				return errors.New("must provide a credential path or '--signed-by'")
+			}
+			if len(args) == 1 && signedBy != "" {
+				return errors.New("cannot provide both a credential path and '--signed-by'")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			signedBy, _ := cmd.Flags().GetString("signed-by")
+
+			cmd.SilenceUsage = true
+
+			c := &cmdRegenerateRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+				signedBy:      signedBy,
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	cmd.Flags().String("signed-by", "", "regenerate every certificate signed by this CA")
+
+	return cmd
+}
+
+func (c *cmdRegenerateRunner) Run(cmd *cobra.Command, args []string) error {
+	if c.signedBy != "" {
+		names, err := c.credhubClient.BulkRegenerate(c.ctx, c.signedBy)
+		if err != nil {
+			if _, isNotFoundError := err.(*credhub.ErrCredentialNotFound); isNotFoundError {
+				return fmt.Errorf("'%s': no such credential", c.signedBy)
+			}
+			return fmt.Errorf("failed to regenerate certificates signed by %s: %s", c.signedBy, err.Error())
+		}
+
+		for _, name := range names {
+			fmt.Fprintln(cmd.OutOrStdout(), name)
+		}
+		return nil
+	}
+
+	name := args[0]
+	credential, err := c.credhubClient.RegenerateCredential(c.ctx, name)
+	if err != nil {
+		if _, isNotFoundError := err.(*credhub.ErrCredentialNotFound); isNotFoundError {
+			return fmt.Errorf("'%s': no such credential", name)
+		}
+		return fmt.Errorf("failed to regenerate %s: %s", name, err.Error())
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), credential.Name)
+	return nil
+}
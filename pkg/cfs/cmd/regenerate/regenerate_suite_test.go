@@ -0,0 +1,13 @@
+package regenerate_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRegenerate(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Regenerate Suite")
+}
@@ -0,0 +1,128 @@
+package regenerate_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/regenerate"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/regenerate/regeneratefakes"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+var _ = Describe("Regenerate", func() {
+	var fakeCredhubClient *regeneratefakes.FakeCredhubClient
+	var dependencies cmdutil.Dependencies
+
+	BeforeEach(func() {
+		fakeCredhubClient = &regeneratefakes.FakeCredhubClient{}
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetCredhubClient(fakeCredhubClient)
+	})
+
+	It("regenerates the credential and prints its name", func() {
+		fakeCredhubClient.RegenerateCredentialReturns(credhub.Credential{Name: "/some/cred"}, nil)
+
+		var output bytes.Buffer
+		cmd := regenerate.NewCmdRegenerate(dependencies)
+		cmd.SetArgs([]string{"/some/cred"})
+		cmd.SetOutput(&output)
+
+		Expect(cmd.Execute()).To(Succeed())
+
+		Expect(fakeCredhubClient.RegenerateCredentialCallCount()).To(Equal(1))
+		_, regeneratedName := fakeCredhubClient.RegenerateCredentialArgsForCall(0)
+		Expect(regeneratedName).To(Equal("/some/cred"))
+		Expect(fakeCredhubClient.BulkRegenerateCallCount()).To(Equal(0))
+		Expect(output.String()).To(Equal("/some/cred\n"))
+	})
+
+	It("regenerates every certificate signed by a CA and prints their names", func() {
+		fakeCredhubClient.BulkRegenerateReturns([]string{"/some/cert", "/some/other-cert"}, nil)
+
+		var output bytes.Buffer
+		cmd := regenerate.NewCmdRegenerate(dependencies)
+		cmd.SetArgs([]string{"--signed-by", "/some/ca"})
+		cmd.SetOutput(&output)
+
+		Expect(cmd.Execute()).To(Succeed())
+
+		Expect(fakeCredhubClient.BulkRegenerateCallCount()).To(Equal(1))
+		_, requestedSignedBy := fakeCredhubClient.BulkRegenerateArgsForCall(0)
+		Expect(requestedSignedBy).To(Equal("/some/ca"))
+		Expect(fakeCredhubClient.RegenerateCredentialCallCount()).To(Equal(0))
+		Expect(output.String()).To(Equal("/some/cert\n/some/other-cert\n"))
+	})
+
+	Context("when the credential does not exist", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.RegenerateCredentialReturns(credhub.Credential{}, &credhub.ErrCredentialNotFound{})
+
+			cmd := regenerate.NewCmdRegenerate(dependencies)
+			cmd.SetArgs([]string{"/some/cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("'/some/cred': no such credential"))
+		})
+	})
+
+	Context("when the CA does not exist", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.BulkRegenerateReturns(nil, &credhub.ErrCredentialNotFound{})
+
+			cmd := regenerate.NewCmdRegenerate(dependencies)
+			cmd.SetArgs([]string{"--signed-by", "/some/ca"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("'/some/ca': no such credential"))
+		})
+	})
+
+	Context("when regenerating fails", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.RegenerateCredentialReturns(credhub.Credential{}, errors.New("some-error"))
+
+			cmd := regenerate.NewCmdRegenerate(dependencies)
+			cmd.SetArgs([]string{"/some/cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("failed to regenerate /some/cred: some-error"))
+		})
+	})
+
+	Context("when bulk regenerating fails", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.BulkRegenerateReturns(nil, errors.New("some-error"))
+
+			cmd := regenerate.NewCmdRegenerate(dependencies)
+			cmd.SetArgs([]string{"--signed-by", "/some/ca"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("failed to regenerate certificates signed by /some/ca: some-error"))
+		})
+	})
+
+	Context("when neither a path nor a CA is provided", func() {
+		It("returns an error", func() {
+			cmd := regenerate.NewCmdRegenerate(dependencies)
+			cmd.SetArgs([]string{})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("must provide a credential path or '--signed-by'"))
+		})
+	})
+
+	Context("when both a path and a CA are provided", func() {
+		It("returns an error", func() {
+			cmd := regenerate.NewCmdRegenerate(dependencies)
+			cmd.SetArgs([]string{"/some/cred", "--signed-by", "/some/ca"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("cannot provide both a credential path and '--signed-by'"))
+		})
+	})
+})
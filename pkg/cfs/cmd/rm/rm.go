@@ -1,6 +1,7 @@
 package rm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -10,6 +11,7 @@ import (
 )
 
 type cmdRmRunner struct {
+	ctx           context.Context
 	credhubClient credhubClient
 	recursive     bool
 }
@@ -33,6 +35,7 @@ func NewCmdRm(dependencies cmdutil.Dependencies) *cobra.Command {
 			cmd.SilenceUsage = true
 
 			c := &cmdRmRunner{
+				ctx:           dependencies.GetContext(),
 				credhubClient: dependencies.GetCredhubClient(),
 				recursive:     recursive,
 			}
@@ -47,7 +50,7 @@ func NewCmdRm(dependencies cmdutil.Dependencies) *cobra.Command {
 
 func (c *cmdRmRunner) Run(cmd *cobra.Command, args []string) error {
 	path := args[0]
-	credential, err := c.credhubClient.GetCredentialByName(path)
+	credential, err := c.credhubClient.GetCredentialByName(c.ctx, path)
 	if err != nil {
 		if _, isNotFoundError := err.(*credhub.ErrCredentialNotFound); !isNotFoundError {
 			return fmt.Errorf("failed to get credential: %s", err.Error())
@@ -55,19 +58,22 @@ func (c *cmdRmRunner) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	if credential.Name != "" {
-		if err := c.credhubClient.DeleteCredentialByName(path); err != nil {
+		if err := c.credhubClient.DeleteCredentialByName(c.ctx, path); err != nil {
 			return fmt.Errorf("failed to remove %s: %s", path, err.Error())
 		}
 	} else {
-		credentials, err := c.credhubClient.FindCredentialsByPath(path)
+		credentials, err := c.credhubClient.FindCredentialsByPath(c.ctx, path)
 		if err != nil {
 			return fmt.Errorf("failed to find credentials: %s", err.Error())
 		}
 
 		if len(credentials) > 0 {
 			if c.recursive {
-				for _, credential := range credentials {
-					if err := c.credhubClient.DeleteCredentialByName(credential.Name); err != nil {
+				for i, credential := range credentials {
+					if err := c.credhubClient.DeleteCredentialByName(c.ctx, credential.Name); err != nil {
+						if interrupted := cmdutil.Interrupted(c.ctx, fmt.Sprintf("removing %d of %d credentials", i, len(credentials))); interrupted != nil {
+							return interrupted
+						}
 						return fmt.Errorf("failed to remove %s: %s", credential.Name, err.Error())
 					}
 				}
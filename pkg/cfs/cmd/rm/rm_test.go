@@ -2,6 +2,7 @@ package rm_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -38,9 +39,11 @@ var _ = Describe("Rm", func() {
 			Expect(cmd.Execute()).To(Succeed())
 
 			Expect(fakeCredhubClient.GetCredentialByNameCallCount()).To(Equal(1))
-			Expect(fakeCredhubClient.GetCredentialByNameArgsForCall(0)).To(Equal(path))
+			_, requestedName := fakeCredhubClient.GetCredentialByNameArgsForCall(0)
+			Expect(requestedName).To(Equal(path))
 			Expect(fakeCredhubClient.DeleteCredentialByNameCallCount()).To(Equal(1))
-			Expect(fakeCredhubClient.DeleteCredentialByNameArgsForCall(0)).To(Equal(path))
+			_, removedName := fakeCredhubClient.DeleteCredentialByNameArgsForCall(0)
+			Expect(removedName).To(Equal(path))
 		})
 
 		Context("when deleting the credential fails", func() {
@@ -76,12 +79,16 @@ var _ = Describe("Rm", func() {
 				Expect(cmd.Execute()).To(Succeed())
 
 				Expect(fakeCredhubClient.GetCredentialByNameCallCount()).To(Equal(1))
-				Expect(fakeCredhubClient.GetCredentialByNameArgsForCall(0)).To(Equal(rootPath))
+				_, requestedName := fakeCredhubClient.GetCredentialByNameArgsForCall(0)
+				Expect(requestedName).To(Equal(rootPath))
 				Expect(fakeCredhubClient.FindCredentialsByPathCallCount()).To(Equal(1))
-				Expect(fakeCredhubClient.FindCredentialsByPathArgsForCall(0)).To(Equal(rootPath))
+				_, searchedPath := fakeCredhubClient.FindCredentialsByPathArgsForCall(0)
+				Expect(searchedPath).To(Equal(rootPath))
 				Expect(fakeCredhubClient.DeleteCredentialByNameCallCount()).To(Equal(2))
-				Expect(fakeCredhubClient.DeleteCredentialByNameArgsForCall(0)).To(Equal(path1))
-				Expect(fakeCredhubClient.DeleteCredentialByNameArgsForCall(1)).To(Equal(path2))
+				_, removedName := fakeCredhubClient.DeleteCredentialByNameArgsForCall(0)
+				Expect(removedName).To(Equal(path1))
+				_, removedName = fakeCredhubClient.DeleteCredentialByNameArgsForCall(1)
+				Expect(removedName).To(Equal(path2))
 			})
 
 			Context("when deleting a credential fails", func() {
@@ -100,6 +107,32 @@ var _ = Describe("Rm", func() {
 				})
 			})
 
+			Context("when interrupted", func() {
+				It("stops and reports how many credentials were removed", func() {
+					ctx, cancel := context.WithCancel(context.Background())
+					defer cancel()
+					dependencies.SetContext(ctx)
+
+					fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{}, &credhub.ErrCredentialNotFound{})
+					fakeCredhubClient.FindCredentialsByPathReturns([]credhub.Credential{{Name: "/cred1"}, {Name: "/cred2"}, {Name: "/cred3"}}, nil)
+					fakeCredhubClient.DeleteCredentialByNameStub = func(ctx context.Context, name string) error {
+						if name == "/cred2" {
+							cancel()
+						}
+						return ctx.Err()
+					}
+
+					cmd := rm.NewCmdRm(dependencies)
+					cmd.SetArgs([]string{"-r", "/"})
+					cmd.SetOutput(ioutil.Discard)
+
+					Expect(cmd.Execute()).To(MatchError("interrupted after removing 1 of 3 credentials"))
+					Expect(fakeCredhubClient.DeleteCredentialByNameCallCount()).To(Equal(2))
+					requestCtx, _ := fakeCredhubClient.DeleteCredentialByNameArgsForCall(0)
+					Expect(requestCtx).To(Equal(ctx))
+				})
+			})
+
 			Context("when finding credentials fails", func() {
 				It("returns an error", func() {
 					fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{}, &credhub.ErrCredentialNotFound{})
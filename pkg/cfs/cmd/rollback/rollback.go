@@ -0,0 +1,124 @@
+package rollback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/spf13/cobra"
+)
+
+type cmdRollbackRunner struct {
+	ctx           context.Context
+	credhubClient credhubClient
+	toVersion     int
+	toID          string
+	dryRun        bool
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+func NewCmdRollback(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback /path/to/credential [--to-version N | --to-id ID]",
+		Short: "Restore a previous version of a credential",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("must provide a credential path")
+			}
+			if cmd.Flags().Changed("to-version") && cmd.Flags().Changed("to-id") {
+				return errors.New("cannot use '--to-version' with '--to-id'")
+			}
+			if toVersion, _ := cmd.Flags().GetInt("to-version"); toVersion < 1 {
+				return errors.New("'--to-version' must be at least 1")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			toVersion, _ := cmd.Flags().GetInt("to-version")
+			toID, _ := cmd.Flags().GetString("to-id")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			cmd.SilenceUsage = true
+
+			c := &cmdRollbackRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+				toVersion:     toVersion,
+				toID:          toID,
+				dryRun:        dryRun,
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	cmd.Flags().Int("to-version", 1, "number of versions before the current one to restore")
+	cmd.Flags().String("to-id", "", "ID of the credential version to restore")
+	cmd.Flags().Bool("dry-run", false, "show which version would be restored without restoring it")
+
+	return cmd
+}
+
+func (c *cmdRollbackRunner) Run(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	version, err := c.getVersion(name)
+	if err != nil {
+		switch err.(type) {
+		case *credhub.ErrCredentialNotFound:
+			return fmt.Errorf("'%s': no such credential or path", name)
+		default:
+			return err
+		}
+	}
+
+	description := fmt.Sprintf("%s to version %s created at %s", name, version.ID, version.VersionCreatedAt.Format(time.RFC3339))
+	if c.dryRun {
+		fmt.Fprintf(cmd.OutOrStdout(), "Would restore %s\n", description)
+		return nil
+	}
+
+	if _, err := c.credhubClient.SetCredential(c.ctx, credhub.Credential{Name: name, Value: version.Value}); err != nil {
+		return fmt.Errorf("failed to set %s: %s", name, err.Error())
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Restored %s\n", description)
+	return nil
+}
+
+func (c *cmdRollbackRunner) getVersion(name string) (credhub.Credential, error) {
+	if c.toID == "" {
+		version, err := cmdutil.GetCredentialVersion(c.ctx, c.credhubClient, name, c.toVersion)
+		if err != nil {
+			if _, isNotFoundError := err.(*credhub.ErrCredentialNotFound); isNotFoundError {
+				return credhub.Credential{}, err
+			}
+			return credhub.Credential{}, fmt.Errorf("failed to get credential: %s", err.Error())
+		}
+		return version, nil
+	}
+
+	id, err := uuid.Parse(c.toID)
+	if err != nil {
+		return credhub.Credential{}, fmt.Errorf("'%s': invalid credential ID", c.toID)
+	}
+
+	version, err := c.credhubClient.GetCredentialByID(c.ctx, id)
+	if err != nil {
+		if _, isNotFoundError := err.(*credhub.ErrCredentialNotFound); isNotFoundError {
+			return credhub.Credential{}, fmt.Errorf("'%s': no such credential version", c.toID)
+		}
+		return credhub.Credential{}, fmt.Errorf("failed to get credential: %s", err.Error())
+	}
+
+	if version.Name != name {
+		return credhub.Credential{}, fmt.Errorf("'%s': version belongs to %s, not %s", c.toID, version.Name, name)
+	}
+
+	return version, nil
+}
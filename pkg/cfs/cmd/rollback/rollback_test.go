@@ -0,0 +1,214 @@
+package rollback_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/google/uuid"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/rollback"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/rollback/rollbackfakes"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+var _ = Describe("Rollback", func() {
+	var (
+		fakeCredhubClient *rollbackfakes.FakeCredhubClient
+		dependencies      cmdutil.Dependencies
+		previousID        uuid.UUID
+		previousDate      time.Time
+	)
+
+	BeforeEach(func() {
+		fakeCredhubClient = &rollbackfakes.FakeCredhubClient{}
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetCredhubClient(fakeCredhubClient)
+
+		previousID = uuid.New()
+		previousDate = time.Date(2019, 3, 1, 8, 0, 0, 0, time.UTC)
+		fakeCredhubClient.GetCredentialVersionsReturns([]credhub.Credential{
+			{ID: uuid.New(), Name: "/some/cred", Value: credhub.Value("current-value")},
+			{ID: previousID, Name: "/some/cred", Value: credhub.Value("previous-value"), VersionCreatedAt: previousDate},
+		}, nil)
+	})
+
+	It("restores the previous version of a credential", func() {
+		var output bytes.Buffer
+		cmd := rollback.NewCmdRollback(dependencies)
+		cmd.SetArgs([]string{"/some/cred"})
+		cmd.SetOutput(&output)
+
+		Expect(cmd.Execute()).To(Succeed())
+
+		_, name, n := fakeCredhubClient.GetCredentialVersionsArgsForCall(0)
+		Expect(name).To(Equal("/some/cred"))
+		Expect(n).To(Equal(2))
+		Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(1))
+		_, written := fakeCredhubClient.SetCredentialArgsForCall(0)
+		Expect(written).To(Equal(credhub.Credential{
+			Name:  "/some/cred",
+			Value: credhub.Value("previous-value"),
+		}))
+		Expect(output.String()).To(Equal(fmt.Sprintf("Restored /some/cred to version %s created at 2019-03-01T08:00:00Z\n", previousID)))
+	})
+
+	It("restores an older version with `to-version`", func() {
+		olderID := uuid.New()
+		fakeCredhubClient.GetCredentialVersionsReturns([]credhub.Credential{
+			{Value: credhub.Value("current-value")},
+			{Value: credhub.Value("previous-value")},
+			{ID: olderID, Value: credhub.Value("older-value")},
+		}, nil)
+
+		var output bytes.Buffer
+		cmd := rollback.NewCmdRollback(dependencies)
+		cmd.SetArgs([]string{"--to-version", "2", "/some/cred"})
+		cmd.SetOutput(&output)
+
+		Expect(cmd.Execute()).To(Succeed())
+
+		_, _, n := fakeCredhubClient.GetCredentialVersionsArgsForCall(0)
+		Expect(n).To(Equal(3))
+		_, written := fakeCredhubClient.SetCredentialArgsForCall(0)
+		Expect(written.Value).To(Equal(credhub.Value("older-value")))
+		Expect(output.String()).To(ContainSubstring(olderID.String()))
+	})
+
+	Context("when the version with `to-version` does not exist", func() {
+		It("returns an error", func() {
+			cmd := rollback.NewCmdRollback(dependencies)
+			cmd.SetArgs([]string{"--to-version", "3", "/some/cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("failed to get credential: '/some/cred': version 3 does not exist"))
+			Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(0))
+		})
+	})
+
+	It("restores the version with `to-id`", func() {
+		id := uuid.New()
+		fakeCredhubClient.GetCredentialByIDReturns(credhub.Credential{
+			ID:    id,
+			Name:  "/some/cred",
+			Value: credhub.Password("some-password"),
+		}, nil)
+
+		cmd := rollback.NewCmdRollback(dependencies)
+		cmd.SetArgs([]string{"--to-id", id.String(), "/some/cred"})
+		cmd.SetOutput(ioutil.Discard)
+
+		Expect(cmd.Execute()).To(Succeed())
+
+		_, requestedID := fakeCredhubClient.GetCredentialByIDArgsForCall(0)
+		Expect(requestedID).To(Equal(id))
+		_, written := fakeCredhubClient.SetCredentialArgsForCall(0)
+		Expect(written).To(Equal(credhub.Credential{
+			Name:  "/some/cred",
+			Value: credhub.Password("some-password"),
+		}))
+	})
+
+	Context("when `dry-run` is true", func() {
+		It("shows the version that would be restored without restoring it", func() {
+			var output bytes.Buffer
+			cmd := rollback.NewCmdRollback(dependencies)
+			cmd.SetArgs([]string{"--dry-run", "/some/cred"})
+			cmd.SetOutput(&output)
+
+			Expect(cmd.Execute()).To(Succeed())
+
+			Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(0))
+			Expect(output.String()).To(Equal(fmt.Sprintf("Would restore /some/cred to version %s created at 2019-03-01T08:00:00Z\n", previousID)))
+		})
+	})
+
+	Context("when the version with `to-id` belongs to another credential", func() {
+		It("returns an error", func() {
+			id := uuid.New()
+			fakeCredhubClient.GetCredentialByIDReturns(credhub.Credential{ID: id, Name: "/other/cred"}, nil)
+
+			cmd := rollback.NewCmdRollback(dependencies)
+			cmd.SetArgs([]string{"--to-id", id.String(), "/some/cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError(fmt.Sprintf("'%s': version belongs to /other/cred, not /some/cred", id)))
+			Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the version with `to-id` does not exist", func() {
+		It("returns an error", func() {
+			id := uuid.New()
+			fakeCredhubClient.GetCredentialByIDReturns(credhub.Credential{}, &credhub.ErrCredentialNotFound{})
+
+			cmd := rollback.NewCmdRollback(dependencies)
+			cmd.SetArgs([]string{"--to-id", id.String(), "/some/cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError(fmt.Sprintf("'%s': no such credential version", id)))
+		})
+	})
+
+	Context("when the credential does not exist", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.GetCredentialVersionsReturns(nil, &credhub.ErrCredentialNotFound{})
+
+			cmd := rollback.NewCmdRollback(dependencies)
+			cmd.SetArgs([]string{"/some/cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("'/some/cred': no such credential or path"))
+		})
+	})
+
+	Context("when setting the credential fails", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.SetCredentialReturns(credhub.Credential{}, errors.New("some-error"))
+
+			cmd := rollback.NewCmdRollback(dependencies)
+			cmd.SetArgs([]string{"/some/cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("failed to set /some/cred: some-error"))
+		})
+	})
+
+	Context("when both `to-version` and `to-id` are provided", func() {
+		It("returns an error and shows the usage", func() {
+			cmd := rollback.NewCmdRollback(dependencies)
+			cmd.SetArgs([]string{"--to-version", "2", "--to-id", uuid.New().String(), "/some/cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("cannot use '--to-version' with '--to-id'"))
+			Expect(cmd.SilenceUsage).To(BeFalse())
+		})
+	})
+
+	Context("when `to-version` is less than 1", func() {
+		It("returns an error and shows the usage", func() {
+			cmd := rollback.NewCmdRollback(dependencies)
+			cmd.SetArgs([]string{"--to-version", "0", "/some/cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("'--to-version' must be at least 1"))
+			Expect(cmd.SilenceUsage).To(BeFalse())
+		})
+	})
+
+	Context("when no arguments are provided", func() {
+		It("returns an error and shows the usage", func() {
+			cmd := rollback.NewCmdRollback(dependencies)
+			cmd.SetArgs([]string{})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("must provide a credential path"))
+			Expect(cmd.SilenceUsage).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,133 @@
+package servewebdav
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/pkg/credhubdav"
+	"github.com/spf13/cobra"
+)
+
+type cmdServeWebdavRunner struct {
+	ctx           context.Context
+	credhubClient credhubClient
+	listenAddr    string
+	certPath      string
+	keyPath       string
+	writable      bool
+	username      string
+	password      string
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+func NewCmdServeWebdav(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve-webdav",
+		Short: "Serve CredHub over WebDAV until interrupted",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			listenAddr, _ := cmd.Flags().GetString("listen")
+			certPath, _ := cmd.Flags().GetString("cert-path")
+			keyPath, _ := cmd.Flags().GetString("key-path")
+			writable, _ := cmd.Flags().GetBool("writable")
+			username, _ := cmd.Flags().GetString("username")
+
+			if certPath == "" || keyPath == "" {
+				return errors.New("must provide '--cert-path' and '--key-path'")
+			}
+			if username == "" {
+				return errors.New("must provide a non-empty '--username'")
+			}
+
+			cmd.SilenceUsage = true
+
+			c := &cmdServeWebdavRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+				listenAddr:    listenAddr,
+				certPath:      certPath,
+				keyPath:       keyPath,
+				writable:      writable,
+				username:      username,
+				password:      os.Getenv("CFS_WEBDAV_PASSWORD"),
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	cmd.Flags().String("listen", "127.0.0.1:8080", "address to listen on")
+	cmd.Flags().String("cert-path", "", "path to the TLS certificate to serve with (required)")
+	cmd.Flags().String("key-path", "", "path to the private key for '--cert-path' (required)")
+	cmd.Flags().Bool("writable", false, "allow clients to create, modify and delete credentials")
+	cmd.Flags().String("username", "cfs", "username clients must authenticate as; the password is read from $CFS_WEBDAV_PASSWORD or generated and printed")
+
+	return cmd
+}
+
+func (c *cmdServeWebdavRunner) Run(cmd *cobra.Command, args []string) error {
+	certificate, err := tls.LoadX509KeyPair(c.certPath, c.keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %s", err.Error())
+	}
+
+	password := c.password
+	if password == "" {
+		if password, err = generatePassword(); err != nil {
+			return fmt.Errorf("failed to generate password: %s", err.Error())
+		}
+	}
+
+	listener, err := net.Listen("tcp", c.listenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to serve WebDAV: %s", err.Error())
+	}
+
+	httpServer := &http.Server{
+		Handler: credhubdav.NewHandler(c.credhubClient, c.writable, c.username, password),
+	}
+
+	go func() {
+		<-c.ctx.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(ctx)
+	}()
+
+	mode := "read-only"
+	if c.writable {
+		mode = "writable"
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Serving CredHub over WebDAV (%s) on https://%s\n", mode, listener.Addr())
+	fmt.Fprintf(cmd.OutOrStdout(), "Username: %s\n", c.username)
+	if c.password == "" {
+		fmt.Fprintf(cmd.OutOrStdout(), "Password: %s\n", password)
+	}
+
+	err = httpServer.Serve(tls.NewListener(listener, &tls.Config{Certificates: []tls.Certificate{certificate}}))
+	if err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("failed to serve WebDAV: %s", err.Error())
+	}
+	return nil
+}
+
+// generatePassword returns a random password for clients to authenticate
+// with when none is given in $CFS_WEBDAV_PASSWORD.
+func generatePassword() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,13 @@
+package servewebdav_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestServeWebdav(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ServeWebdav Suite")
+}
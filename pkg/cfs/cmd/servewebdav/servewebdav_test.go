@@ -0,0 +1,81 @@
+package servewebdav_test
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/servewebdav"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/servewebdav/servewebdavfakes"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+)
+
+var _ = Describe("ServeWebdav", func() {
+	var (
+		dependencies cmdutil.Dependencies
+		certPath     = filepath.Join("..", "..", "..", "..", "test", "fixtures", "127.0.0.1-cert.pem")
+		keyPath      = filepath.Join("..", "..", "..", "..", "test", "fixtures", "127.0.0.1-key.pem")
+	)
+
+	BeforeEach(func() {
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetCredhubClient(&servewebdavfakes.FakeCredhubClient{})
+	})
+
+	for _, args := range [][]string{
+		{},
+		{"--cert-path", certPath},
+		{"--key-path", keyPath},
+	} {
+		args := args
+		Context(fmt.Sprintf("when given %q", args), func() {
+			It("refuses to serve without TLS", func() {
+				cmd := servewebdav.NewCmdServeWebdav(dependencies)
+				cmd.SetArgs(args)
+				cmd.SetOutput(&bytes.Buffer{})
+
+				Expect(cmd.Execute()).To(MatchError("must provide '--cert-path' and '--key-path'"))
+			})
+		})
+	}
+
+	Context("when the username is empty", func() {
+		It("returns an error", func() {
+			cmd := servewebdav.NewCmdServeWebdav(dependencies)
+			cmd.SetArgs([]string{"--cert-path", certPath, "--key-path", keyPath, "--username", ""})
+			cmd.SetOutput(&bytes.Buffer{})
+
+			Expect(cmd.Execute()).To(MatchError("must provide a non-empty '--username'"))
+		})
+	})
+
+	Context("when the certificate cannot be loaded", func() {
+		It("returns an error", func() {
+			cmd := servewebdav.NewCmdServeWebdav(dependencies)
+			cmd.SetArgs([]string{"--cert-path", "some-missing-cert", "--key-path", keyPath})
+			cmd.SetOutput(&bytes.Buffer{})
+
+			Expect(cmd.Execute()).To(MatchError(HavePrefix("failed to load TLS certificate: ")))
+		})
+	})
+
+	Context("when the address cannot be listened on", func() {
+		It("returns an error without claiming to be serving", func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			defer listener.Close()
+
+			var output bytes.Buffer
+			cmd := servewebdav.NewCmdServeWebdav(dependencies)
+			cmd.SetArgs([]string{"--listen", listener.Addr().String(), "--cert-path", certPath, "--key-path", keyPath})
+			cmd.SetOutput(&output)
+
+			Expect(cmd.Execute()).To(MatchError(HavePrefix("failed to serve WebDAV: ")))
+			Expect(output.String()).NotTo(ContainSubstring("Serving"))
+		})
+	})
+})
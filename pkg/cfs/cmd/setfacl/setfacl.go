@@ -0,0 +1,161 @@
+package setfacl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/spf13/cobra"
+)
+
+type cmdSetfaclRunner struct {
+	ctx           context.Context
+	credhubClient credhubClient
+	modifications []credhub.Permission
+	removals      []string
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+func NewCmdSetfacl(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "setfacl (-m ACTOR:OPERATIONS | -x ACTOR)... PATH",
+		Short: "Grants or revokes permissions on a credential path",
+		Long: "Grants or revokes permissions on a credential path. OPERATIONS is a comma-separated\n" +
+			"list of " + strings.Join(credhub.Operations, ", ") + ". A PATH ending in '*'\n" +
+			"applies to every credential under it.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("must provide a credential path")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, _ := cmd.Flags().GetStringArray("modify")
+			removals, _ := cmd.Flags().GetStringArray("remove")
+
+			if len(entries) == 0 && len(removals) == 0 {
+				return errors.New("must provide '-m' or '-x'")
+			}
+
+			var modifications []credhub.Permission
+			for _, entry := range entries {
+				permission, err := parseEntry(entry)
+				if err != nil {
+					return err
+				}
+				permission.Path = args[0]
+				modifications = append(modifications, permission)
+			}
+
+			cmd.SilenceUsage = true
+
+			c := &cmdSetfaclRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+				modifications: modifications,
+				removals:      removals,
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	cmd.Flags().StringArrayP("modify", "m", nil, "grant ACTOR:OPERATIONS, replacing any operations the actor already has")
+	cmd.Flags().StringArrayP("remove", "x", nil, "revoke every operation granted to ACTOR")
+
+	return cmd
+}
+
+func (c *cmdSetfaclRunner) Run(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	total := len(c.modifications) + len(c.removals)
+
+	for i, modification := range c.modifications {
+		if err := c.modify(path, modification); err != nil {
+			if interrupted := cmdutil.Interrupted(c.ctx, fmt.Sprintf("changing %d of %d ACL entries", i, total)); interrupted != nil {
+				return interrupted
+			}
+			return err
+		}
+	}
+
+	for i, actor := range c.removals {
+		if err := c.remove(path, actor); err != nil {
+			if interrupted := cmdutil.Interrupted(c.ctx, fmt.Sprintf("changing %d of %d ACL entries", len(c.modifications)+i, total)); interrupted != nil {
+				return interrupted
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *cmdSetfaclRunner) modify(path string, modification credhub.Permission) error {
+	existing, err := c.credhubClient.GetPermission(c.ctx, path, modification.Actor)
+	if err != nil {
+		if _, isNotFoundError := err.(*credhub.ErrPermissionNotFound); !isNotFoundError {
+			return fmt.Errorf("failed to get permission for %s: %s", modification.Actor, err.Error())
+		}
+
+		if _, err := c.credhubClient.CreatePermission(c.ctx, modification); err != nil {
+			return fmt.Errorf("failed to grant permission to %s: %s", modification.Actor, err.Error())
+		}
+		return nil
+	}
+
+	modification.UUID = existing.UUID
+	if _, err := c.credhubClient.UpdatePermission(c.ctx, modification); err != nil {
+		return fmt.Errorf("failed to grant permission to %s: %s", modification.Actor, err.Error())
+	}
+	return nil
+}
+
+func (c *cmdSetfaclRunner) remove(path, actor string) error {
+	existing, err := c.credhubClient.GetPermission(c.ctx, path, actor)
+	if err != nil {
+		if _, isNotFoundError := err.(*credhub.ErrPermissionNotFound); isNotFoundError {
+			return fmt.Errorf("'%s': no ACL entry for '%s'", path, actor)
+		}
+		return fmt.Errorf("failed to get permission for %s: %s", actor, err.Error())
+	}
+
+	if err := c.credhubClient.DeletePermission(c.ctx, existing.UUID); err != nil {
+		return fmt.Errorf("failed to revoke permission from %s: %s", actor, err.Error())
+	}
+	return nil
+}
+
+// parseEntry splits an ACTOR:OPERATIONS entry at its last colon, since actors
+// such as "uaa-client:some-client" contain colons themselves.
+func parseEntry(entry string) (credhub.Permission, error) {
+	i := strings.LastIndex(entry, ":")
+	if i <= 0 || i == len(entry)-1 {
+		return credhub.Permission{}, fmt.Errorf("invalid entry '%s': must be ACTOR:OPERATIONS", entry)
+	}
+
+	actor := entry[:i]
+	operations := strings.Split(entry[i+1:], ",")
+	for _, operation := range operations {
+		if !isOperation(operation) {
+			return credhub.Permission{}, fmt.Errorf(
+				"invalid operation '%s': must be one of %s", operation, strings.Join(credhub.Operations, ", "),
+			)
+		}
+	}
+
+	return credhub.Permission{Actor: actor, Operations: operations}, nil
+}
+
+func isOperation(operation string) bool {
+	for _, o := range credhub.Operations {
+		if operation == o {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package setfacl_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestSetfacl(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Setfacl Suite")
+}
@@ -0,0 +1,169 @@
+package setfacl_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/setfacl"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/setfacl/setfaclfakes"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+var _ = Describe("Setfacl", func() {
+	var fakeCredhubClient *setfaclfakes.FakeCredhubClient
+	var dependencies cmdutil.Dependencies
+
+	BeforeEach(func() {
+		fakeCredhubClient = &setfaclfakes.FakeCredhubClient{}
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetCredhubClient(fakeCredhubClient)
+	})
+
+	Context("when modifying an entry", func() {
+		It("creates the permission when the actor has none", func() {
+			fakeCredhubClient.GetPermissionReturns(credhub.Permission{}, &credhub.ErrPermissionNotFound{})
+
+			cmd := setfacl.NewCmdSetfacl(dependencies)
+			cmd.SetArgs([]string{"-m", "uaa-client:some-client:read,write", "/some/*"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(Succeed())
+
+			Expect(fakeCredhubClient.GetPermissionCallCount()).To(Equal(1))
+			_, path, actor := fakeCredhubClient.GetPermissionArgsForCall(0)
+			Expect(path).To(Equal("/some/*"))
+			Expect(actor).To(Equal("uaa-client:some-client"))
+
+			Expect(fakeCredhubClient.CreatePermissionCallCount()).To(Equal(1))
+			_, created := fakeCredhubClient.CreatePermissionArgsForCall(0)
+			Expect(created).To(Equal(credhub.Permission{
+				Actor:      "uaa-client:some-client",
+				Path:       "/some/*",
+				Operations: []string{"read", "write"},
+			}))
+			Expect(fakeCredhubClient.UpdatePermissionCallCount()).To(Equal(0))
+		})
+
+		It("replaces the operations when the actor already has a permission", func() {
+			id := uuid.New()
+			fakeCredhubClient.GetPermissionReturns(credhub.Permission{
+				UUID:       id,
+				Actor:      "uaa-client:some-client",
+				Path:       "/some/cred",
+				Operations: []string{"read"},
+			}, nil)
+
+			cmd := setfacl.NewCmdSetfacl(dependencies)
+			cmd.SetArgs([]string{"-m", "uaa-client:some-client:write", "/some/cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(Succeed())
+
+			Expect(fakeCredhubClient.UpdatePermissionCallCount()).To(Equal(1))
+			_, updated := fakeCredhubClient.UpdatePermissionArgsForCall(0)
+			Expect(updated).To(Equal(credhub.Permission{
+				UUID:       id,
+				Actor:      "uaa-client:some-client",
+				Path:       "/some/cred",
+				Operations: []string{"write"},
+			}))
+			Expect(fakeCredhubClient.CreatePermissionCallCount()).To(Equal(0))
+		})
+
+		It("returns an error when granting fails", func() {
+			fakeCredhubClient.GetPermissionReturns(credhub.Permission{}, &credhub.ErrPermissionNotFound{})
+			fakeCredhubClient.CreatePermissionReturns(credhub.Permission{}, errors.New("some-error"))
+
+			cmd := setfacl.NewCmdSetfacl(dependencies)
+			cmd.SetArgs([]string{"-m", "some-actor:read", "/some/cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("failed to grant permission to some-actor: some-error"))
+		})
+	})
+
+	Context("when removing an entry", func() {
+		It("deletes the actor's permission", func() {
+			id := uuid.New()
+			fakeCredhubClient.GetPermissionReturns(credhub.Permission{UUID: id}, nil)
+
+			cmd := setfacl.NewCmdSetfacl(dependencies)
+			cmd.SetArgs([]string{"-x", "uaa-client:some-client", "/some/cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(Succeed())
+
+			Expect(fakeCredhubClient.DeletePermissionCallCount()).To(Equal(1))
+			_, deletedID := fakeCredhubClient.DeletePermissionArgsForCall(0)
+			Expect(deletedID).To(Equal(id))
+		})
+
+		It("returns an error when the actor has no permission", func() {
+			fakeCredhubClient.GetPermissionReturns(credhub.Permission{}, &credhub.ErrPermissionNotFound{})
+
+			cmd := setfacl.NewCmdSetfacl(dependencies)
+			cmd.SetArgs([]string{"-x", "some-actor", "/some/cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("'/some/cred': no ACL entry for 'some-actor'"))
+			Expect(fakeCredhubClient.DeletePermissionCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when interrupted", func() {
+		It("stops and reports how many entries were changed", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			dependencies.SetContext(ctx)
+
+			fakeCredhubClient.GetPermissionReturns(credhub.Permission{}, &credhub.ErrPermissionNotFound{})
+			fakeCredhubClient.CreatePermissionStub = func(ctx context.Context, permission credhub.Permission) (credhub.Permission, error) {
+				if permission.Actor == "other-actor" {
+					cancel()
+				}
+				return permission, ctx.Err()
+			}
+
+			cmd := setfacl.NewCmdSetfacl(dependencies)
+			cmd.SetArgs([]string{"-m", "some-actor:read", "-m", "other-actor:read", "-x", "third-actor", "/some/cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("interrupted after changing 1 of 3 ACL entries"))
+			Expect(fakeCredhubClient.DeletePermissionCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the arguments are invalid", func() {
+		for _, c := range []struct {
+			description string
+			args        []string
+			err         string
+		}{
+			{"no path", []string{"-m", "some-actor:read"}, "must provide a credential path"},
+			{"no entries", []string{"/some/cred"}, "must provide '-m' or '-x'"},
+			{"an entry without operations", []string{"-m", "some-actor", "/some/cred"}, "invalid entry 'some-actor': must be ACTOR:OPERATIONS"},
+			{"an entry without an actor", []string{"-m", ":read", "/some/cred"}, "invalid entry ':read': must be ACTOR:OPERATIONS"},
+			{
+				"an unknown operation",
+				[]string{"-m", "some-actor:read,execute", "/some/cred"},
+				"invalid operation 'execute': must be one of read, write, delete, read_acl, write_acl",
+			},
+		} {
+			c := c
+			It("returns an error for "+c.description, func() {
+				cmd := setfacl.NewCmdSetfacl(dependencies)
+				cmd.SetArgs(append([]string{}, c.args...))
+				cmd.SetOutput(ioutil.Discard)
+
+				Expect(cmd.Execute()).To(MatchError(c.err))
+				Expect(fakeCredhubClient.GetPermissionCallCount()).To(Equal(0))
+			})
+		}
+	})
+})
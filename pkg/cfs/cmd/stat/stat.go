@@ -0,0 +1,206 @@
+package stat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/spf13/cobra"
+)
+
+type cmdStatRunner struct {
+	ctx           context.Context
+	credhubClient credhubClient
+	format        *template.Template
+	skipVersions  bool
+}
+
+// credentialStat is the data available to '--format' templates.
+type credentialStat struct {
+	Name             string
+	ID               string
+	Type             string
+	VersionCreatedAt time.Time
+	Versions         int
+	Metadata         map[string]interface{}
+	Certificate      *certificateStat
+}
+
+type certificateStat struct {
+	Issuer    string
+	Subject   string
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+func NewCmdStat(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stat /path/to/credential...",
+		Short: "Show credential metadata",
+		Long: "Show credential metadata.\n\n" +
+			"'--format' takes a Go template with the fields .Name, .ID, .Type, .VersionCreatedAt, .Versions, .Metadata " +
+			"and, for certificates, .Certificate.Issuer, .Certificate.Subject, .Certificate.NotBefore and .Certificate.NotAfter. " +
+			"Counting versions downloads every version of the credential; '--no-versions' fetches only the current " +
+			"version and leaves .Versions unset.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("must provide at least one credential path")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			skipVersions, _ := cmd.Flags().GetBool("no-versions")
+
+			var formatTemplate *template.Template
+			if format != "" {
+				var err error
+				formatTemplate, err = template.New("format").Parse(format)
+				if err != nil {
+					return fmt.Errorf("invalid format: %s", err.Error())
+				}
+			}
+
+			cmd.SilenceUsage = true
+
+			c := &cmdStatRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+				format:        formatTemplate,
+				skipVersions:  skipVersions,
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	cmd.Flags().StringP("format", "c", "", "Go template to print instead of the default format")
+	cmd.Flags().Bool("no-versions", false, "do not count the versions of each credential")
+
+	return cmd
+}
+
+func (c *cmdStatRunner) Run(cmd *cobra.Command, args []string) error {
+	for i, name := range args {
+		stat, err := c.stat(name)
+		if err != nil {
+			return err
+		}
+
+		if c.format != nil {
+			if err := c.format.Execute(cmd.OutOrStdout(), stat); err != nil {
+				return fmt.Errorf("failed to format %s: %s", name, err.Error())
+			}
+			fmt.Fprintln(cmd.OutOrStdout())
+			continue
+		}
+
+		if i > 0 {
+			fmt.Fprintln(cmd.OutOrStdout())
+		}
+		if err := printStat(cmd.OutOrStdout(), stat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *cmdStatRunner) stat(name string) (*credentialStat, error) {
+	versions, err := c.versions(name)
+	if err != nil {
+		switch err.(type) {
+		case *credhub.ErrCredentialNotFound:
+			return nil, fmt.Errorf("'%s': no such credential", name)
+		default:
+			return nil, err
+		}
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("'%s': no such credential", name)
+	}
+
+	current := versions[0]
+	stat := &credentialStat{
+		Name:             current.Name,
+		ID:               current.ID.String(),
+		Type:             current.Type,
+		VersionCreatedAt: current.VersionCreatedAt,
+		Metadata:         current.Metadata,
+	}
+	if !c.skipVersions {
+		stat.Versions = len(versions)
+	}
+
+	if certificate, ok := current.Value.(credhub.Certificate); ok {
+		parsed, err := certificate.ParseCertificate()
+		if err != nil {
+			return nil, fmt.Errorf("'%s': %s", name, err.Error())
+		}
+		stat.Certificate = &certificateStat{
+			Issuer:    parsed.Issuer.String(),
+			Subject:   parsed.Subject.String(),
+			NotBefore: parsed.NotBefore,
+			NotAfter:  parsed.NotAfter,
+		}
+	}
+
+	return stat, nil
+}
+
+// versions returns every version of the credential, newest first, or only the
+// current version with '--no-versions'.
+func (c *cmdStatRunner) versions(name string) ([]credhub.Credential, error) {
+	if c.skipVersions {
+		current, err := c.credhubClient.GetCredentialByName(c.ctx, name)
+		if err != nil {
+			if _, isNotFoundError := err.(*credhub.ErrCredentialNotFound); isNotFoundError {
+				return nil, err
+			}
+			return nil, fmt.Errorf("failed to get credential: %s", err.Error())
+		}
+		return []credhub.Credential{current}, nil
+	}
+
+	versions, err := c.credhubClient.GetCredentialVersions(c.ctx, name, 0)
+	if err != nil {
+		if _, isNotFoundError := err.(*credhub.ErrCredentialNotFound); isNotFoundError {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get credential versions: %s", err.Error())
+	}
+	return versions, nil
+}
+
+func printStat(out io.Writer, stat *credentialStat) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", stat.Name)
+	fmt.Fprintf(w, "ID:\t%s\n", stat.ID)
+	fmt.Fprintf(w, "Type:\t%s\n", stat.Type)
+	fmt.Fprintf(w, "Created:\t%s\n", stat.VersionCreatedAt.Format(time.RFC3339))
+	if stat.Versions > 0 {
+		fmt.Fprintf(w, "Versions:\t%d\n", stat.Versions)
+	}
+	if len(stat.Metadata) > 0 {
+		metadata, err := json.Marshal(stat.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to format metadata: %s", err.Error())
+		}
+		fmt.Fprintf(w, "Metadata:\t%s\n", metadata)
+	}
+	if stat.Certificate != nil {
+		fmt.Fprintf(w, "Issuer:\t%s\n", stat.Certificate.Issuer)
+		fmt.Fprintf(w, "Subject:\t%s\n", stat.Certificate.Subject)
+		fmt.Fprintf(w, "Not Before:\t%s\n", stat.Certificate.NotBefore.Format(time.RFC3339))
+		fmt.Fprintf(w, "Expires:\t%s\n", stat.Certificate.NotAfter.Format(time.RFC3339))
+	}
+	return w.Flush()
+}
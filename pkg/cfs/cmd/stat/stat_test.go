@@ -0,0 +1,175 @@
+package stat_test
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/stat"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/stat/statfakes"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/test/helpers"
+)
+
+var _ = Describe("Stat", func() {
+	var (
+		fakeCredhubClient *statfakes.FakeCredhubClient
+		dependencies      cmdutil.Dependencies
+		id                uuid.UUID
+		createdAt         time.Time
+		runStat           func(args ...string) (string, error)
+	)
+
+	BeforeEach(func() {
+		fakeCredhubClient = &statfakes.FakeCredhubClient{}
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetCredhubClient(fakeCredhubClient)
+
+		id = uuid.New()
+		createdAt = time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		runStat = func(args ...string) (string, error) {
+			var output bytes.Buffer
+			cmd := stat.NewCmdStat(dependencies)
+			cmd.SetArgs(append([]string{}, args...))
+			cmd.SetOutput(&output)
+			err := cmd.Execute()
+			return output.String(), err
+		}
+	})
+
+	It("prints the credential metadata", func() {
+		fakeCredhubClient.GetCredentialVersionsReturns([]credhub.Credential{
+			{
+				ID:               id,
+				Name:             "/some-cred",
+				Type:             "password",
+				Value:            credhub.Password("some-password"),
+				Metadata:         map[string]interface{}{"owner": "some-team"},
+				VersionCreatedAt: createdAt,
+			},
+			{Name: "/some-cred", Type: "password"},
+		}, nil)
+
+		output, err := runStat("/some-cred")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal(`Name:      /some-cred
+ID:        ` + id.String() + `
+Type:      password
+Created:   2019-01-02T03:04:05Z
+Versions:  2
+Metadata:  {"owner":"some-team"}
+`))
+
+		_, name, n := fakeCredhubClient.GetCredentialVersionsArgsForCall(0)
+		Expect(name).To(Equal("/some-cred"))
+		Expect(n).To(Equal(0))
+	})
+
+	It("only gets the current version when '--no-versions' is provided", func() {
+		fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{
+			ID:               id,
+			Name:             "/some-cred",
+			Type:             "password",
+			VersionCreatedAt: createdAt,
+		}, nil)
+
+		output, err := runStat("--no-versions", "/some-cred")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal(`Name:     /some-cred
+ID:       ` + id.String() + `
+Type:     password
+Created:  2019-01-02T03:04:05Z
+`))
+
+		_, name := fakeCredhubClient.GetCredentialByNameArgsForCall(0)
+		Expect(name).To(Equal("/some-cred"))
+		Expect(fakeCredhubClient.GetCredentialVersionsCallCount()).To(Equal(0))
+	})
+
+	It("prints the issuer, subject and expiry of certificates", func() {
+		notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		fakeCredhubClient.GetCredentialVersionsReturns([]credhub.Credential{{
+			ID:               id,
+			Name:             "/some-cert",
+			Type:             "certificate",
+			Value:            credhub.Certificate{Certificate: helpers.GenerateCertificatePEM("some-common-name", notAfter)},
+			VersionCreatedAt: createdAt,
+		}}, nil)
+
+		output, err := runStat("/some-cert")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(ContainSubstring("Issuer:      CN=some-common-name\n"))
+		Expect(output).To(ContainSubstring("Subject:     CN=some-common-name\n"))
+		Expect(output).To(ContainSubstring("Expires:     2030-01-02T03:04:05Z\n"))
+	})
+
+	It("formats the output with a Go template when '--format' is provided", func() {
+		notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		fakeCredhubClient.GetCredentialVersionsReturns([]credhub.Credential{{
+			Name:  "/some-cert",
+			Type:  "certificate",
+			Value: credhub.Certificate{Certificate: helpers.GenerateCertificatePEM("some-common-name", notAfter)},
+		}}, nil)
+
+		output, err := runStat("--format", "{{.Name}} {{.Type}} {{.Versions}} {{.Certificate.NotAfter.Year}}", "/some-cert", "/some-cert")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("/some-cert certificate 1 2030\n/some-cert certificate 1 2030\n"))
+	})
+
+	Context("when the credential does not exist", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.GetCredentialVersionsReturns(nil, &credhub.ErrCredentialNotFound{})
+
+			_, err := runStat("/missing")
+			Expect(err).To(MatchError("'/missing': no such credential"))
+		})
+	})
+
+	Context("when getting the credential versions fails", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.GetCredentialVersionsReturns(nil, errors.New("some-error"))
+
+			_, err := runStat("/some-cred")
+			Expect(err).To(MatchError("failed to get credential versions: some-error"))
+		})
+	})
+
+	Context("when getting the current version fails", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{}, errors.New("some-error"))
+
+			_, err := runStat("--no-versions", "/some-cred")
+			Expect(err).To(MatchError("failed to get credential: some-error"))
+		})
+	})
+
+	Context("when the credential does not exist and '--no-versions' is provided", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{}, &credhub.ErrCredentialNotFound{})
+
+			_, err := runStat("--no-versions", "/missing")
+			Expect(err).To(MatchError("'/missing': no such credential"))
+		})
+	})
+
+	Context("when the format is not a valid template", func() {
+		It("returns an error", func() {
+			_, err := runStat("--format", "{{.Name", "/some-cred")
+			Expect(err).To(MatchError(HavePrefix("invalid format: ")))
+			Expect(fakeCredhubClient.GetCredentialVersionsCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when no path is provided", func() {
+		It("returns an error", func() {
+			_, err := runStat()
+			Expect(err).To(MatchError("must provide at least one credential path"))
+		})
+	})
+})
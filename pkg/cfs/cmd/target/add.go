@@ -0,0 +1,112 @@
+package target
+
+import (
+	"errors"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+type cmdAddRunner struct {
+	configPath string
+	target     config.Target
+}
+
+func newCmdAdd(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add NAME --credhub-addr ADDR (--client-id ID (--client-secret SECRET | --client-secret-env VAR | --client-secret-command COMMAND) | --client-cert CERT --client-key KEY)",
+		Short: "Add or replace a target, making it current if there is no current target",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("must provide a target name")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			credhubAddr, _ := cmd.Flags().GetString("credhub-addr")
+			clientID, _ := cmd.Flags().GetString("client-id")
+			clientSecret, _ := cmd.Flags().GetString("client-secret")
+			clientSecretEnv, _ := cmd.Flags().GetString("client-secret-env")
+			clientSecretCommand, _ := cmd.Flags().GetString("client-secret-command")
+			clientCert, _ := cmd.Flags().GetString("client-cert")
+			clientKey, _ := cmd.Flags().GetString("client-key")
+			caCerts, _ := cmd.Flags().GetStringArray("ca-cert")
+			skipTLSValidation, _ := cmd.Flags().GetBool("skip-tls-validation")
+
+			if credhubAddr == "" {
+				return errors.New("must provide '--credhub-addr'")
+			}
+			if clientCert != "" || clientKey != "" {
+				if clientCert == "" || clientKey == "" {
+					return errors.New("must provide both '--client-cert' and '--client-key'")
+				}
+				if clientID != "" || clientSecret != "" || clientSecretEnv != "" || clientSecretCommand != "" {
+					return errors.New("cannot provide a UAA client with '--client-cert' and '--client-key'")
+				}
+			} else {
+				if clientID == "" {
+					return errors.New("must provide '--client-id'")
+				}
+				secretSources := 0
+				for _, source := range []string{clientSecret, clientSecretEnv, clientSecretCommand} {
+					if source != "" {
+						secretSources++
+					}
+				}
+				if secretSources != 1 {
+					return errors.New("must provide one of '--client-secret', '--client-secret-env' or '--client-secret-command'")
+				}
+			}
+
+			cmd.SilenceUsage = true
+
+			c := &cmdAddRunner{
+				configPath: dependencies.GetConfigPath(),
+				target: config.Target{
+					Name:                args[0],
+					CredhubAddr:         credhubAddr,
+					ClientID:            clientID,
+					ClientSecret:        clientSecret,
+					ClientSecretEnv:     clientSecretEnv,
+					ClientSecretCommand: clientSecretCommand,
+					ClientCert:          clientCert,
+					ClientKey:           clientKey,
+					CACerts:             caCerts,
+					SkipTLSValidation:   skipTLSValidation,
+				},
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	cmd.Flags().String("credhub-addr", "", "address of CredHub server")
+	cmd.Flags().String("client-id", "", "UAA client ID")
+	cmd.Flags().String("client-secret", "", "UAA client secret, stored in plaintext")
+	cmd.Flags().String("client-secret-env", "", "environment variable to read the UAA client secret from")
+	cmd.Flags().String("client-secret-command", "", "shell command which prints the UAA client secret")
+	cmd.Flags().String("client-cert", "", "client certificate to authenticate with instead of a UAA client, as a path or PEM")
+	cmd.Flags().String("client-key", "", "private key of the client certificate, as a path or PEM")
+	cmd.Flags().StringArray("ca-cert", nil, "CA certificate to trust, as a path or PEM; can be repeated")
+	cmd.Flags().Bool("skip-tls-validation", false, "do not verify the certificates of CredHub and UAA")
+
+	return cmd
+}
+
+func (c *cmdAddRunner) Run(cmd *cobra.Command, args []string) error {
+	if c.configPath == "" {
+		return errNoConfigPath
+	}
+
+	cfg, err := config.Load(c.configPath)
+	if err != nil {
+		return err
+	}
+
+	cfg.SetTarget(c.target)
+	if cfg.CurrentTarget == "" {
+		cfg.CurrentTarget = c.target.Name
+	}
+
+	return cfg.Save(c.configPath)
+}
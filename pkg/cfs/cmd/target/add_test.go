@@ -0,0 +1,126 @@
+package target_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/target"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/config"
+)
+
+var _ = Describe("Add", func() {
+	var (
+		tempDir      string
+		configPath   string
+		dependencies cmdutil.Dependencies
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "target")
+		Expect(err).NotTo(HaveOccurred())
+		configPath = filepath.Join(tempDir, "config.yml")
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetConfigPath(configPath)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	It("adds the target and makes it current if there is no current target", func() {
+		cmd := target.NewCmdTarget(dependencies)
+		cmd.SetArgs([]string{"add", "some-target", "--credhub-addr", "some-addr", "--client-id", "some-client", "--client-secret-env", "SOME_ENV"})
+		cmd.SetOutput(ioutil.Discard)
+		Expect(cmd.Execute()).To(Succeed())
+
+		cmd = target.NewCmdTarget(dependencies)
+		cmd.SetArgs([]string{
+			"add", "other-target",
+			"--credhub-addr", "other-addr",
+			"--client-id", "other-client",
+			"--client-secret", "other-secret",
+			"--ca-cert", "/some/ca.pem",
+			"--ca-cert", "/other/ca.pem",
+			"--skip-tls-validation",
+		})
+		cmd.SetOutput(ioutil.Discard)
+		Expect(cmd.Execute()).To(Succeed())
+
+		cmd = target.NewCmdTarget(dependencies)
+		cmd.SetArgs([]string{"add", "mtls-target", "--credhub-addr", "mtls-addr", "--client-cert", "/some/cert.pem", "--client-key", "/some/key.pem"})
+		cmd.SetOutput(ioutil.Discard)
+		Expect(cmd.Execute()).To(Succeed())
+
+		Expect(config.Load(configPath)).To(Equal(config.Config{
+			CurrentTarget: "some-target",
+			Targets: []config.Target{
+				{Name: "some-target", CredhubAddr: "some-addr", ClientID: "some-client", ClientSecretEnv: "SOME_ENV"},
+				{
+					Name:              "other-target",
+					CredhubAddr:       "other-addr",
+					ClientID:          "other-client",
+					ClientSecret:      "other-secret",
+					CACerts:           []string{"/some/ca.pem", "/other/ca.pem"},
+					SkipTLSValidation: true,
+				},
+				{Name: "mtls-target", CredhubAddr: "mtls-addr", ClientCert: "/some/cert.pem", ClientKey: "/some/key.pem"},
+			},
+		}))
+	})
+
+	Context("when the arguments are invalid", func() {
+		for _, c := range []struct {
+			description string
+			args        []string
+			err         string
+		}{
+			{"no name", []string{"--credhub-addr", "a", "--client-id", "b", "--client-secret", "c"}, "must provide a target name"},
+			{"no CredHub address", []string{"t", "--client-id", "b", "--client-secret", "c"}, "must provide '--credhub-addr'"},
+			{"no client ID", []string{"t", "--credhub-addr", "a", "--client-secret", "c"}, "must provide '--client-id'"},
+			{
+				"no client secret",
+				[]string{"t", "--credhub-addr", "a", "--client-id", "b"},
+				"must provide one of '--client-secret', '--client-secret-env' or '--client-secret-command'",
+			},
+			{
+				"more than one client secret",
+				[]string{"t", "--credhub-addr", "a", "--client-id", "b", "--client-secret", "c", "--client-secret-env", "D"},
+				"must provide one of '--client-secret', '--client-secret-env' or '--client-secret-command'",
+			},
+			{"a client certificate without a key", []string{"t", "--credhub-addr", "a", "--client-cert", "e"}, "must provide both '--client-cert' and '--client-key'"},
+			{
+				"both a client certificate and a UAA client",
+				[]string{"t", "--credhub-addr", "a", "--client-cert", "e", "--client-key", "f", "--client-id", "b"},
+				"cannot provide a UAA client with '--client-cert' and '--client-key'",
+			},
+		} {
+			c := c
+			It("returns an error for "+c.description, func() {
+				cmd := target.NewCmdTarget(dependencies)
+				cmd.SetArgs(append([]string{"add"}, c.args...))
+				cmd.SetOutput(ioutil.Discard)
+
+				Expect(cmd.Execute()).To(MatchError(c.err))
+				Expect(configPath).NotTo(BeAnExistingFile())
+			})
+		}
+	})
+
+	Context("when the config path is unknown", func() {
+		It("returns an error", func() {
+			dependencies.SetConfigPath("")
+
+			cmd := target.NewCmdTarget(dependencies)
+			cmd.SetArgs([]string{"add", "t", "--credhub-addr", "a", "--client-id", "b", "--client-secret", "c"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("could not determine the location of the config file"))
+		})
+	})
+})
@@ -0,0 +1,57 @@
+package target
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+type cmdListRunner struct {
+	configPath string
+}
+
+func newCmdList(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List targets, marking the current target with '*'",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			c := &cmdListRunner{
+				configPath: dependencies.GetConfigPath(),
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	return cmd
+}
+
+func (c *cmdListRunner) Run(cmd *cobra.Command, args []string) error {
+	if c.configPath == "" {
+		return errNoConfigPath
+	}
+
+	cfg, err := config.Load(c.configPath)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	for _, target := range cfg.Targets {
+		marker := " "
+		if target.Name == cfg.CurrentTarget {
+			marker = "*"
+		}
+		client := target.ClientID
+		if target.ClientCert != "" {
+			client = "(client certificate)"
+		}
+		fmt.Fprintf(w, "%s %s\t%s\t%s\n", marker, target.Name, target.CredhubAddr, client)
+	}
+	return w.Flush()
+}
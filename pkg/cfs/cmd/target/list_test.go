@@ -0,0 +1,69 @@
+package target_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/target"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/config"
+)
+
+var _ = Describe("List", func() {
+	var (
+		tempDir      string
+		configPath   string
+		dependencies cmdutil.Dependencies
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "target")
+		Expect(err).NotTo(HaveOccurred())
+		configPath = filepath.Join(tempDir, "config.yml")
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetConfigPath(configPath)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	It("lists targets without their secrets, marking the current target", func() {
+		Expect(config.Config{
+			CurrentTarget: "other-target",
+			Targets: []config.Target{
+				{Name: "some-target", CredhubAddr: "some-addr:8844", ClientID: "some-client", ClientSecret: "some-secret"},
+				{Name: "other-target", CredhubAddr: "other-addr:8844", ClientID: "other-client", ClientSecretEnv: "SOME_ENV"},
+				{Name: "mtls-target", CredhubAddr: "mtls-addr:8844", ClientCert: "/some/cert.pem", ClientKey: "/some/key.pem"},
+			},
+		}.Save(configPath)).To(Succeed())
+
+		var output bytes.Buffer
+		cmd := target.NewCmdTarget(dependencies)
+		cmd.SetArgs([]string{"list"})
+		cmd.SetOutput(&output)
+
+		Expect(cmd.Execute()).To(Succeed())
+		Expect(output.String()).To(Equal(
+			"  some-target   some-addr:8844   some-client\n" +
+				"* other-target  other-addr:8844  other-client\n" +
+				"  mtls-target   mtls-addr:8844   (client certificate)\n",
+		))
+	})
+
+	It("prints nothing when there are no targets", func() {
+		var output bytes.Buffer
+		cmd := target.NewCmdTarget(dependencies)
+		cmd.SetArgs([]string{"list"})
+		cmd.SetOutput(&output)
+
+		Expect(cmd.Execute()).To(Succeed())
+		Expect(output.String()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,58 @@
+package target
+
+import (
+	"errors"
+	"fmt"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+type cmdRemoveRunner struct {
+	configPath string
+}
+
+func newCmdRemove(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove NAME",
+		Short: "Remove a target",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("must provide a target name")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			c := &cmdRemoveRunner{
+				configPath: dependencies.GetConfigPath(),
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	return cmd
+}
+
+func (c *cmdRemoveRunner) Run(cmd *cobra.Command, args []string) error {
+	if c.configPath == "" {
+		return errNoConfigPath
+	}
+
+	cfg, err := config.Load(c.configPath)
+	if err != nil {
+		return err
+	}
+
+	name := args[0]
+	if err := cfg.RemoveTarget(name); err != nil {
+		if _, isNotFoundError := err.(*config.ErrTargetNotFound); isNotFoundError {
+			return fmt.Errorf("'%s': no such target", name)
+		}
+		return err
+	}
+
+	return cfg.Save(c.configPath)
+}
@@ -0,0 +1,30 @@
+package target
+
+import (
+	"errors"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+var errNoConfigPath = errors.New("could not determine the location of the config file")
+
+func NewCmdTarget(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "target",
+		Short: "Manage named CredHub targets in ~/.cfs/config.yml",
+		// Managing targets does not talk to CredHub, so it does not need the
+		// credentials required by the root command.
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newCmdAdd(dependencies))
+	cmd.AddCommand(newCmdList(dependencies))
+	cmd.AddCommand(newCmdRemove(dependencies))
+	cmd.AddCommand(newCmdUse(dependencies))
+
+	return cmd
+}
@@ -0,0 +1,59 @@
+package target
+
+import (
+	"errors"
+	"fmt"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+type cmdUseRunner struct {
+	configPath string
+}
+
+func newCmdUse(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "use NAME",
+		Short: "Make a target current, so that it is used when no credentials or '--target' are given",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("must provide a target name")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			c := &cmdUseRunner{
+				configPath: dependencies.GetConfigPath(),
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	return cmd
+}
+
+func (c *cmdUseRunner) Run(cmd *cobra.Command, args []string) error {
+	if c.configPath == "" {
+		return errNoConfigPath
+	}
+
+	cfg, err := config.Load(c.configPath)
+	if err != nil {
+		return err
+	}
+
+	name := args[0]
+	if _, err := cfg.GetTarget(name); err != nil {
+		if _, isNotFoundError := err.(*config.ErrTargetNotFound); isNotFoundError {
+			return fmt.Errorf("'%s': no such target", name)
+		}
+		return err
+	}
+
+	cfg.CurrentTarget = name
+	return cfg.Save(c.configPath)
+}
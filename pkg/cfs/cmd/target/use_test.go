@@ -0,0 +1,62 @@
+package target_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/target"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/config"
+)
+
+var _ = Describe("Use", func() {
+	var (
+		tempDir      string
+		configPath   string
+		dependencies cmdutil.Dependencies
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "target")
+		Expect(err).NotTo(HaveOccurred())
+		configPath = filepath.Join(tempDir, "config.yml")
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetConfigPath(configPath)
+
+		Expect(config.Config{
+			CurrentTarget: "some-target",
+			Targets:       []config.Target{{Name: "some-target"}, {Name: "other-target"}},
+		}.Save(configPath)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	It("makes the target current", func() {
+		cmd := target.NewCmdTarget(dependencies)
+		cmd.SetArgs([]string{"use", "other-target"})
+		cmd.SetOutput(ioutil.Discard)
+
+		Expect(cmd.Execute()).To(Succeed())
+
+		cfg, err := config.Load(configPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.CurrentTarget).To(Equal("other-target"))
+	})
+
+	Context("when the target does not exist", func() {
+		It("returns an error", func() {
+			cmd := target.NewCmdTarget(dependencies)
+			cmd.SetArgs([]string{"use", "missing-target"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("'missing-target': no such target"))
+		})
+	})
+})
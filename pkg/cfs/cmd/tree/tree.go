@@ -0,0 +1,215 @@
+package tree
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/spf13/cobra"
+)
+
+type cmdTreeRunner struct {
+	ctx           context.Context
+	credhubClient credhubClient
+	maxDepth      int
+	dirsOnly      bool
+	cumulative    bool
+}
+
+// dirNode is a CredHub path in the tree built from flattened credential
+// names.
+type dirNode struct {
+	name        string
+	dirs        map[string]*dirNode
+	credentials []credhub.Credential
+	total       int
+	newest      time.Time
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+func NewCmdTree(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tree [PATH]",
+		Short: "List credentials under a path as a tree",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return errors.New("must provide at most one path")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			maxDepth, _ := cmd.Flags().GetInt("level")
+			dirsOnly, _ := cmd.Flags().GetBool("dirs-only")
+			cumulative, _ := cmd.Flags().GetBool("du")
+
+			if maxDepth < 0 {
+				return errors.New("'-L' must not be negative")
+			}
+
+			cmd.SilenceUsage = true
+
+			c := &cmdTreeRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+				maxDepth:      maxDepth,
+				dirsOnly:      dirsOnly,
+				cumulative:    cumulative,
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	cmd.Flags().IntP("level", "L", 0, "descend at most this many levels (0 for no limit)")
+	cmd.Flags().BoolP("dirs-only", "d", false, "list directories only")
+	cmd.Flags().Bool("du", false, "count every credential below a directory, not just those directly in it")
+
+	return cmd
+}
+
+func (c *cmdTreeRunner) Run(cmd *cobra.Command, args []string) error {
+	path := "/"
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if path[0] != '/' {
+		path = "/" + path
+	}
+
+	credentials, err := c.credhubClient.FindCredentialsByPath(c.ctx, path)
+	if err != nil {
+		return fmt.Errorf("failed to list credentials: %s", err.Error())
+	}
+	if len(credentials) == 0 && path != "/" {
+		return fmt.Errorf("'%s': no such path", path)
+	}
+
+	root := buildTree(path, credentials)
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "%s %s\n", root.name, c.dirSummary(root))
+	dirCount, credentialCount := c.render(out, root, "", 1)
+
+	if c.dirsOnly {
+		fmt.Fprintf(out, "\n%s\n", plural(dirCount, "directory", "directories"))
+	} else {
+		fmt.Fprintf(out, "\n%s, %s\n", plural(dirCount, "directory", "directories"), plural(credentialCount, "credential", "credentials"))
+	}
+	return nil
+}
+
+func buildTree(path string, credentials []credhub.Credential) *dirNode {
+	root := newDirNode(path)
+	for _, credential := range credentials {
+		relativeName := strings.TrimPrefix(cmdutil.RelativeName(credential.Name, path), "/")
+		parts := strings.Split(relativeName, "/")
+
+		node := root
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node.dirs[part]
+			if !ok {
+				child = newDirNode(part)
+				node.dirs[part] = child
+			}
+			node = child
+		}
+		node.credentials = append(node.credentials, credential)
+	}
+	root.summarize()
+	return root
+}
+
+func newDirNode(name string) *dirNode {
+	return &dirNode{name: name, dirs: map[string]*dirNode{}}
+}
+
+func (d *dirNode) summarize() {
+	d.total = len(d.credentials)
+	for _, credential := range d.credentials {
+		if credential.VersionCreatedAt.After(d.newest) {
+			d.newest = credential.VersionCreatedAt
+		}
+	}
+	for _, child := range d.dirs {
+		child.summarize()
+		d.total += child.total
+		if child.newest.After(d.newest) {
+			d.newest = child.newest
+		}
+	}
+}
+
+type treeEntry struct {
+	name       string
+	dir        *dirNode
+	credential credhub.Credential
+}
+
+func (c *cmdTreeRunner) render(out io.Writer, dir *dirNode, prefix string, depth int) (int, int) {
+	if c.maxDepth > 0 && depth > c.maxDepth {
+		return 0, 0
+	}
+
+	var entries []treeEntry
+	for name, child := range dir.dirs {
+		entries = append(entries, treeEntry{name: name, dir: child})
+	}
+	if !c.dirsOnly {
+		for _, credential := range dir.credentials {
+			entries = append(entries, treeEntry{name: credential.Name[strings.LastIndex(credential.Name, "/")+1:], credential: credential})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].name == entries[j].name {
+			return entries[i].dir == nil
+		}
+		return entries[i].name < entries[j].name
+	})
+
+	dirCount, credentialCount := 0, 0
+	for i, entry := range entries {
+		connector, childPrefix := "├── ", "│   "
+		if i == len(entries)-1 {
+			connector, childPrefix = "└── ", "    "
+		}
+
+		if entry.dir == nil {
+			fmt.Fprintf(out, "%s%s%s [%s]\n", prefix, connector, entry.name, formatTime(entry.credential.VersionCreatedAt))
+			credentialCount++
+			continue
+		}
+
+		fmt.Fprintf(out, "%s%s%s/ %s\n", prefix, connector, entry.name, c.dirSummary(entry.dir))
+		dirCount++
+		childDirs, childCredentials := c.render(out, entry.dir, prefix+childPrefix, depth+1)
+		dirCount += childDirs
+		credentialCount += childCredentials
+	}
+	return dirCount, credentialCount
+}
+
+func (c *cmdTreeRunner) dirSummary(dir *dirNode) string {
+	count := len(dir.credentials)
+	if c.cumulative {
+		count = dir.total
+	}
+	return fmt.Sprintf("[%s, newest %s]", plural(count, "credential", "credentials"), formatTime(dir.newest))
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func plural(count int, singular, plural string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, singular)
+	}
+	return fmt.Sprintf("%d %s", count, plural)
+}
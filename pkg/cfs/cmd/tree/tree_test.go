@@ -0,0 +1,113 @@
+package tree_test
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/tree"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/tree/treefakes"
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+var _ = Describe("Tree", func() {
+	var (
+		fakeCredhubClient *treefakes.FakeCredhubClient
+		dependencies      cmdutil.Dependencies
+		runTree           func(args ...string) (string, error)
+	)
+
+	BeforeEach(func() {
+		fakeCredhubClient = &treefakes.FakeCredhubClient{}
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetCredhubClient(fakeCredhubClient)
+
+		date := func(day int) time.Time {
+			return time.Date(2019, 1, day, 0, 0, 0, 0, time.UTC)
+		}
+		fakeCredhubClient.FindCredentialsByPathReturns([]credhub.Credential{
+			{Name: "/concourse/main/pipeline/db-password", VersionCreatedAt: date(4)},
+			{Name: "/concourse/main/db-password", VersionCreatedAt: date(2)},
+			{Name: "/concourse/main/tls-cert", VersionCreatedAt: date(3)},
+			{Name: "/concourse/admin", VersionCreatedAt: date(1)},
+		}, nil)
+
+		runTree = func(args ...string) (string, error) {
+			var output bytes.Buffer
+			cmd := tree.NewCmdTree(dependencies)
+			cmd.SetArgs(append([]string{}, args...))
+			cmd.SetOutput(&output)
+			err := cmd.Execute()
+			return output.String(), err
+		}
+	})
+
+	It("renders credentials as a tree with per-directory counts and the newest version", func() {
+		output, err := runTree("/concourse")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal(`/concourse [1 credential, newest 2019-01-04T00:00:00Z]
+├── admin [2019-01-01T00:00:00Z]
+└── main/ [2 credentials, newest 2019-01-04T00:00:00Z]
+    ├── db-password [2019-01-02T00:00:00Z]
+    ├── pipeline/ [1 credential, newest 2019-01-04T00:00:00Z]
+    │   └── db-password [2019-01-04T00:00:00Z]
+    └── tls-cert [2019-01-03T00:00:00Z]
+
+2 directories, 4 credentials
+`))
+
+		_, searchedPath := fakeCredhubClient.FindCredentialsByPathArgsForCall(0)
+		Expect(searchedPath).To(Equal("/concourse"))
+	})
+
+	It("counts every credential below each directory with '--du'", func() {
+		output, err := runTree("--du", "-d", "/concourse")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal(`/concourse [4 credentials, newest 2019-01-04T00:00:00Z]
+└── main/ [3 credentials, newest 2019-01-04T00:00:00Z]
+    └── pipeline/ [1 credential, newest 2019-01-04T00:00:00Z]
+
+2 directories
+`))
+	})
+
+	It("limits the depth with '-L'", func() {
+		output, err := runTree("-L", "1", "/concourse")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal(`/concourse [1 credential, newest 2019-01-04T00:00:00Z]
+├── admin [2019-01-01T00:00:00Z]
+└── main/ [2 credentials, newest 2019-01-04T00:00:00Z]
+
+1 directory, 1 credential
+`))
+	})
+
+	It("defaults to the root path", func() {
+		_, err := runTree()
+		Expect(err).NotTo(HaveOccurred())
+		_, searchedPath := fakeCredhubClient.FindCredentialsByPathArgsForCall(0)
+		Expect(searchedPath).To(Equal("/"))
+	})
+
+	Context("when the path does not contain any credentials", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.FindCredentialsByPathReturns(nil, nil)
+
+			_, err := runTree("/missing")
+			Expect(err).To(MatchError("'/missing': no such path"))
+		})
+	})
+
+	Context("when listing credentials fails", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.FindCredentialsByPathReturns(nil, errors.New("some-error"))
+
+			_, err := runTree("/concourse")
+			Expect(err).To(MatchError("failed to list credentials: some-error"))
+		})
+	})
+})
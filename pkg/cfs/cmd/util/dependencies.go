@@ -1,20 +1,48 @@
 package util
 
 import (
+	"context"
+	"io"
+	"os"
+
 	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/pkg/tokencache"
 )
 
 type Dependencies interface {
+	GetContext() context.Context
+	SetContext(context.Context)
 	GetCredhubClient() credhub.Client
 	SetCredhubClient(credhub.Client)
+	GetStdin() io.Reader
+	SetStdin(io.Reader)
+	GetTokenCache() *tokencache.Cache
+	SetTokenCache(*tokencache.Cache)
+	GetConfigPath() string
+	SetConfigPath(string)
 }
 
 type dependencies struct {
+	ctx           context.Context
 	credhubClient credhub.Client
+	stdin         io.Reader
+	tokenCache    *tokencache.Cache
+	configPath    string
 }
 
 func NewDependencies() Dependencies {
-	return &dependencies{}
+	return &dependencies{
+		ctx:   context.Background(),
+		stdin: os.Stdin,
+	}
+}
+
+func (c *dependencies) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+func (c *dependencies) GetContext() context.Context {
+	return c.ctx
 }
 
 func (c *dependencies) SetCredhubClient(credhubClient credhub.Client) {
@@ -24,3 +52,27 @@ func (c *dependencies) SetCredhubClient(credhubClient credhub.Client) {
 func (c *dependencies) GetCredhubClient() credhub.Client {
 	return c.credhubClient
 }
+
+func (c *dependencies) SetStdin(stdin io.Reader) {
+	c.stdin = stdin
+}
+
+func (c *dependencies) GetStdin() io.Reader {
+	return c.stdin
+}
+
+func (c *dependencies) SetTokenCache(tokenCache *tokencache.Cache) {
+	c.tokenCache = tokenCache
+}
+
+func (c *dependencies) GetTokenCache() *tokencache.Cache {
+	return c.tokenCache
+}
+
+func (c *dependencies) SetConfigPath(configPath string) {
+	c.configPath = configPath
+}
+
+func (c *dependencies) GetConfigPath() string {
+	return c.configPath
+}
@@ -0,0 +1,18 @@
+package util
+
+import (
+	"context"
+	"fmt"
+)
+
+// Interrupted returns an error reporting the progress a command made, such as
+// "removing 3 of 5 credentials", if ctx has been cancelled. Commands which
+// make several changes check it when a request fails, because every request
+// made after an interrupt fails with an error that says nothing about what
+// was already done.
+func Interrupted(ctx context.Context, progress string) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+	return fmt.Errorf("interrupted after %s", progress)
+}
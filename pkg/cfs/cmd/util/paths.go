@@ -0,0 +1,11 @@
+package util
+
+import (
+	"strings"
+)
+
+// RelativeName returns the part of a credential name below the given path,
+// including its leading slash.
+func RelativeName(name, path string) string {
+	return strings.TrimPrefix(name, strings.TrimSuffix(path, "/"))
+}
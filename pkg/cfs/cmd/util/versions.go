@@ -0,0 +1,27 @@
+package util
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+// GetCredentialVersion returns the named credential as it was the given
+// number of versions ago, where version 0 is the current version.
+func GetCredentialVersion(ctx context.Context, credhubClient credhub.Client, name string, version int) (credhub.Credential, error) {
+	if version < 0 {
+		return credhub.Credential{}, fmt.Errorf("invalid version %d", version)
+	}
+
+	versions, err := credhubClient.GetCredentialVersions(ctx, name, version+1)
+	if err != nil {
+		return credhub.Credential{}, err
+	}
+
+	if len(versions) <= version {
+		return credhub.Credential{}, fmt.Errorf("'%s': version %d does not exist", name, version)
+	}
+
+	return versions[version], nil
+}
@@ -0,0 +1,132 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/spf13/cobra"
+)
+
+type cmdWriteRunner struct {
+	ctx           context.Context
+	credhubClient credhubClient
+	stdin         io.Reader
+	noClobber     bool
+	tee           bool
+}
+
+// noClobberUsage explains that '--no-clobber' is a check followed by a set,
+// since CredHub cannot be told not to overwrite a credential when setting it.
+const noClobberUsage = "do not overwrite an existing credential; this is checked before writing, " +
+	"so a credential created concurrently may still be overwritten"
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+func NewCmdWrite(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "write /path/to/credential [value]",
+		Short: "Set the value of a credential from an argument or stdin",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 || len(args) > 2 {
+				return errors.New("must provide a credential path and optionally a value")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			noClobber, _ := cmd.Flags().GetBool("no-clobber")
+
+			cmd.SilenceUsage = true
+
+			c := &cmdWriteRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+				stdin:         dependencies.GetStdin(),
+				noClobber:     noClobber,
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	cmd.Flags().BoolP("no-clobber", "n", false, noClobberUsage)
+
+	return cmd
+}
+
+// NewCmdTee sets a credential to the value read from stdin and copies stdin to
+// stdout, like tee(1).
+func NewCmdTee(dependencies cmdutil.Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tee /path/to/credential",
+		Short: "Set the value of a credential from stdin and copy stdin to stdout",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("must provide a credential path")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			noClobber, _ := cmd.Flags().GetBool("no-clobber")
+
+			cmd.SilenceUsage = true
+
+			c := &cmdWriteRunner{
+				ctx:           dependencies.GetContext(),
+				credhubClient: dependencies.GetCredhubClient(),
+				stdin:         dependencies.GetStdin(),
+				noClobber:     noClobber,
+				tee:           true,
+			}
+			return c.Run(cmd, args)
+		},
+	}
+
+	cmd.Flags().BoolP("no-clobber", "n", false, noClobberUsage)
+
+	return cmd
+}
+
+func (c *cmdWriteRunner) Run(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	var value string
+	if len(args) > 1 {
+		value = args[1]
+	} else {
+		input, err := ioutil.ReadAll(c.stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read value: %s", err.Error())
+		}
+		if c.tee {
+			cmd.OutOrStdout().Write(input)
+		}
+		value = strings.TrimSuffix(strings.TrimSuffix(string(input), "\n"), "\r")
+	}
+
+	if value == "" {
+		return errors.New("must provide a non-empty value")
+	}
+
+	if c.noClobber {
+		_, err := c.credhubClient.GetCredentialByName(c.ctx, name)
+		if err == nil {
+			return fmt.Errorf("'%s': credential already exists", name)
+		}
+		if _, isNotFoundError := err.(*credhub.ErrCredentialNotFound); !isNotFoundError {
+			return fmt.Errorf("failed to get credential: %s", err.Error())
+		}
+	}
+
+	credential := credhub.Credential{Name: name, Value: credhub.Value(value)}
+	if _, err := c.credhubClient.SetCredential(c.ctx, credential); err != nil {
+		return fmt.Errorf("failed to set %s: %s", name, err.Error())
+	}
+
+	return nil
+}
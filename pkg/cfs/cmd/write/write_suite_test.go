@@ -0,0 +1,13 @@
+package write_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestWrite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Write Suite")
+}
@@ -0,0 +1,182 @@
+package write_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cmdutil "github.com/mdelillo/credhub-fs/pkg/cfs/cmd/util"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/write"
+	"github.com/mdelillo/credhub-fs/pkg/cfs/cmd/write/writefakes"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+var _ = Describe("Write", func() {
+	var fakeCredhubClient *writefakes.FakeCredhubClient
+	var dependencies cmdutil.Dependencies
+
+	BeforeEach(func() {
+		fakeCredhubClient = &writefakes.FakeCredhubClient{}
+		dependencies = cmdutil.NewDependencies()
+		dependencies.SetCredhubClient(fakeCredhubClient)
+	})
+
+	It("sets the credential to the value argument", func() {
+		path := "/some/path/to/cred"
+		value := "some-value"
+
+		var output bytes.Buffer
+		cmd := write.NewCmdWrite(dependencies)
+		cmd.SetArgs([]string{path, value})
+		cmd.SetOutput(&output)
+
+		Expect(cmd.Execute()).To(Succeed())
+
+		Expect(fakeCredhubClient.GetCredentialByNameCallCount()).To(Equal(0))
+		Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(1))
+		_, written := fakeCredhubClient.SetCredentialArgsForCall(0)
+		Expect(written).To(Equal(credhub.Credential{
+			Name:  path,
+			Value: credhub.Value(value),
+		}))
+	})
+
+	It("sets the credential to the value read from stdin", func() {
+		path := "/some/path/to/cred"
+
+		dependencies.SetStdin(strings.NewReader("some-stdin-value\n"))
+		cmd := write.NewCmdWrite(dependencies)
+		cmd.SetArgs([]string{path})
+		cmd.SetOutput(ioutil.Discard)
+
+		Expect(cmd.Execute()).To(Succeed())
+
+		Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(1))
+		_, written := fakeCredhubClient.SetCredentialArgsForCall(0)
+		Expect(written.Value).To(Equal(credhub.Value("some-stdin-value")))
+	})
+
+	Context("when `no-clobber` is true", func() {
+		It("sets the credential if it does not exist", func() {
+			path := "/some/path/to/cred"
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{}, &credhub.ErrCredentialNotFound{})
+
+			cmd := write.NewCmdWrite(dependencies)
+			cmd.SetArgs([]string{"-n", path, "some-value"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(Succeed())
+
+			Expect(fakeCredhubClient.GetCredentialByNameCallCount()).To(Equal(1))
+			_, requestedName := fakeCredhubClient.GetCredentialByNameArgsForCall(0)
+			Expect(requestedName).To(Equal(path))
+			Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(1))
+		})
+
+		It("returns an error if the credential exists", func() {
+			path := "/some/path/to/cred"
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{Name: path}, nil)
+
+			cmd := write.NewCmdWrite(dependencies)
+			cmd.SetArgs([]string{"--no-clobber", path, "some-value"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("'/some/path/to/cred': credential already exists"))
+			Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(0))
+		})
+
+		Context("when getting the credential fails", func() {
+			It("returns an error", func() {
+				fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{}, errors.New("some-error"))
+
+				cmd := write.NewCmdWrite(dependencies)
+				cmd.SetArgs([]string{"-n", "/some-cred", "some-value"})
+				cmd.SetOutput(ioutil.Discard)
+
+				Expect(cmd.Execute()).To(MatchError("failed to get credential: some-error"))
+				Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("tee", func() {
+		It("sets the credential to the value read from stdin and copies stdin to stdout", func() {
+			dependencies.SetStdin(strings.NewReader("some-stdin-value\n"))
+
+			var output bytes.Buffer
+			cmd := write.NewCmdTee(dependencies)
+			cmd.SetArgs([]string{"/some-cred"})
+			cmd.SetOutput(&output)
+
+			Expect(cmd.Execute()).To(Succeed())
+			Expect(output.String()).To(Equal("some-stdin-value\n"))
+
+			Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(1))
+			_, written := fakeCredhubClient.SetCredentialArgsForCall(0)
+			Expect(written).To(Equal(credhub.Credential{
+				Name:  "/some-cred",
+				Value: credhub.Value("some-stdin-value"),
+			}))
+		})
+
+		It("does not overwrite an existing credential with '-n'", func() {
+			dependencies.SetStdin(strings.NewReader("some-stdin-value\n"))
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{Name: "/some-cred"}, nil)
+
+			cmd := write.NewCmdTee(dependencies)
+			cmd.SetArgs([]string{"-n", "/some-cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("'/some-cred': credential already exists"))
+			Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(0))
+		})
+
+		It("does not take a value argument", func() {
+			cmd := write.NewCmdTee(dependencies)
+			cmd.SetArgs([]string{"/some-cred", "some-value"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("must provide a credential path"))
+		})
+	})
+
+	Context("when no arguments are provided", func() {
+		It("returns an error and shows the usage", func() {
+			cmd := write.NewCmdWrite(dependencies)
+			cmd.SetArgs([]string{})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError(ContainSubstring("must provide a credential path")))
+			Expect(cmd.SilenceUsage).To(BeFalse())
+		})
+	})
+
+	Context("when the value is empty", func() {
+		It("returns an error", func() {
+			dependencies.SetStdin(strings.NewReader(""))
+			cmd := write.NewCmdWrite(dependencies)
+			cmd.SetArgs([]string{"/some-cred"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("must provide a non-empty value"))
+			Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when setting the credential fails", func() {
+		It("returns an error", func() {
+			fakeCredhubClient.SetCredentialReturns(credhub.Credential{}, errors.New("some-error"))
+
+			cmd := write.NewCmdWrite(dependencies)
+			cmd.SetArgs([]string{"/some-cred", "some-value"})
+			cmd.SetOutput(ioutil.Discard)
+
+			Expect(cmd.Execute()).To(MatchError("failed to set /some-cred: some-error"))
+			Expect(cmd.SilenceUsage).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,157 @@
+// Package config reads and writes the cfs config file, which holds named
+// targets so that switching between CredHubs does not require exporting
+// credentials in every shell.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+type Config struct {
+	CurrentTarget string   `yaml:"current-target,omitempty"`
+	Targets       []Target `yaml:"targets,omitempty"`
+}
+
+// Target is a CredHub and the UAA client used to access it. The client secret
+// is given by exactly one of ClientSecret, ClientSecretEnv or
+// ClientSecretCommand, so that it need not be stored in plaintext. A target
+// with a ClientCert and ClientKey authenticates with them instead of a UAA
+// client.
+type Target struct {
+	Name                string   `yaml:"name"`
+	CredhubAddr         string   `yaml:"credhub-addr"`
+	ClientID            string   `yaml:"client-id,omitempty"`
+	ClientSecret        string   `yaml:"client-secret,omitempty"`
+	ClientSecretEnv     string   `yaml:"client-secret-env,omitempty"`
+	ClientSecretCommand string   `yaml:"client-secret-command,omitempty"`
+	ClientCert          string   `yaml:"client-cert,omitempty"`
+	ClientKey           string   `yaml:"client-key,omitempty"`
+	CACerts             []string `yaml:"ca-certs,omitempty"`
+	SkipTLSValidation   bool     `yaml:"skip-tls-validation,omitempty"`
+}
+
+type ErrTargetNotFound struct {
+	name string
+}
+
+func (e *ErrTargetNotFound) Error() string {
+	return fmt.Sprintf("could not find target %s", e.name)
+}
+
+// DefaultPath returns the location of the config file in the user's home
+// directory.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cfs", "config.yml"), nil
+}
+
+// Load reads the config file at path, returning an empty config if it does
+// not exist.
+func Load(path string) (Config, error) {
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Config{}, nil
+	} else if err != nil {
+		return Config{}, fmt.Errorf("failed to read config: %s", err.Error())
+	}
+
+	var config Config
+	if err := yaml.UnmarshalStrict(contents, &config); err != nil {
+		return Config{}, fmt.Errorf("failed to parse config %s: %s", path, err.Error())
+	}
+	return config, nil
+}
+
+// Save writes the config file readable only by its owner, since targets may
+// hold client secrets.
+func (c Config) Save(path string) error {
+	contents, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %s", err.Error())
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %s", err.Error())
+	}
+
+	if err := ioutil.WriteFile(path, contents, 0600); err != nil {
+		return fmt.Errorf("failed to write config: %s", err.Error())
+	}
+	return os.Chmod(path, 0600)
+}
+
+func (c Config) GetTarget(name string) (Target, error) {
+	for _, target := range c.Targets {
+		if target.Name == name {
+			return target, nil
+		}
+	}
+	return Target{}, &ErrTargetNotFound{name}
+}
+
+// SetTarget adds the target, replacing any target with the same name.
+func (c *Config) SetTarget(target Target) {
+	for i := range c.Targets {
+		if c.Targets[i].Name == target.Name {
+			c.Targets[i] = target
+			return
+		}
+	}
+	c.Targets = append(c.Targets, target)
+}
+
+// RemoveTarget removes the named target and unsets it if it is current.
+func (c *Config) RemoveTarget(name string) error {
+	for i := range c.Targets {
+		if c.Targets[i].Name == name {
+			c.Targets = append(c.Targets[:i], c.Targets[i+1:]...)
+			if c.CurrentTarget == name {
+				c.CurrentTarget = ""
+			}
+			return nil
+		}
+	}
+	return &ErrTargetNotFound{name}
+}
+
+// Secret resolves the client secret of the target, reading it from the
+// environment or running a command through the shell if configured to.
+func (t Target) Secret() (string, error) {
+	switch {
+	case t.ClientSecretEnv != "":
+		secret := os.Getenv(t.ClientSecretEnv)
+		if secret == "" {
+			return "", fmt.Errorf("environment variable %s is not set", t.ClientSecretEnv)
+		}
+		return secret, nil
+	case t.ClientSecretCommand != "":
+		var stderr bytes.Buffer
+		cmd := exec.Command("sh", "-c", t.ClientSecretCommand)
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("failed to run '%s': %s: %s", t.ClientSecretCommand, err.Error(), strings.TrimSpace(stderr.String()))
+		}
+		secret := strings.TrimRight(string(output), "\r\n")
+		if secret == "" {
+			return "", fmt.Errorf("'%s' printed no secret", t.ClientSecretCommand)
+		}
+		return secret, nil
+	case t.ClientSecret != "":
+		return t.ClientSecret, nil
+	default:
+		return "", errors.New("no client secret configured")
+	}
+}
@@ -0,0 +1,126 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mdelillo/credhub-fs/pkg/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config", func() {
+	var (
+		tempDir string
+		path    string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "config")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(tempDir, ".cfs", "config.yml")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	It("saves and loads targets", func() {
+		c := config.Config{CurrentTarget: "some-target"}
+		c.SetTarget(config.Target{Name: "some-target", CredhubAddr: "some-addr", ClientID: "some-client", ClientSecret: "some-secret"})
+		c.SetTarget(config.Target{Name: "other-target", CredhubAddr: "other-addr", ClientID: "other-client", ClientSecretEnv: "SOME_ENV"})
+		Expect(c.Save(path)).To(Succeed())
+
+		loaded, err := config.Load(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded).To(Equal(c))
+
+		info, err := os.Stat(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+
+	It("returns an empty config when the file does not exist", func() {
+		Expect(config.Load(path)).To(Equal(config.Config{}))
+	})
+
+	Context("when the file contains unknown keys", func() {
+		It("returns an error", func() {
+			Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(path, []byte("targets:\n- name: some-target\n  client-secert: typo\n"), 0600)).To(Succeed())
+
+			_, err := config.Load(path)
+			Expect(err).To(MatchError(ContainSubstring("failed to parse config")))
+		})
+	})
+
+	Describe("SetTarget", func() {
+		It("replaces a target with the same name", func() {
+			c := config.Config{}
+			c.SetTarget(config.Target{Name: "some-target", CredhubAddr: "some-addr"})
+			c.SetTarget(config.Target{Name: "some-target", CredhubAddr: "other-addr"})
+
+			Expect(c.Targets).To(Equal([]config.Target{{Name: "some-target", CredhubAddr: "other-addr"}}))
+		})
+	})
+
+	Describe("RemoveTarget", func() {
+		It("removes the target and unsets it if it is current", func() {
+			c := config.Config{
+				CurrentTarget: "some-target",
+				Targets:       []config.Target{{Name: "some-target"}, {Name: "other-target"}},
+			}
+
+			Expect(c.RemoveTarget("some-target")).To(Succeed())
+			Expect(c.Targets).To(Equal([]config.Target{{Name: "other-target"}}))
+			Expect(c.CurrentTarget).To(BeEmpty())
+		})
+
+		It("returns an error when the target does not exist", func() {
+			c := config.Config{}
+			err := c.RemoveTarget("some-target")
+			Expect(err).To(BeAssignableToTypeOf(&config.ErrTargetNotFound{}))
+			Expect(err).To(MatchError("could not find target some-target"))
+		})
+	})
+
+	Describe("Secret", func() {
+		It("returns a plaintext secret", func() {
+			Expect(config.Target{ClientSecret: "some-secret"}.Secret()).To(Equal("some-secret"))
+		})
+
+		It("reads the secret from an environment variable", func() {
+			os.Setenv("CFS_CONFIG_TEST_SECRET", "some-secret")
+			defer os.Unsetenv("CFS_CONFIG_TEST_SECRET")
+
+			Expect(config.Target{ClientSecretEnv: "CFS_CONFIG_TEST_SECRET"}.Secret()).To(Equal("some-secret"))
+		})
+
+		It("reads the secret from the output of a command", func() {
+			Expect(config.Target{ClientSecretCommand: "echo some-secret"}.Secret()).To(Equal("some-secret"))
+		})
+
+		Context("when the environment variable is not set", func() {
+			It("returns an error", func() {
+				_, err := config.Target{ClientSecretEnv: "CFS_CONFIG_TEST_UNSET"}.Secret()
+				Expect(err).To(MatchError("environment variable CFS_CONFIG_TEST_UNSET is not set"))
+			})
+		})
+
+		Context("when the command fails", func() {
+			It("returns an error including its stderr", func() {
+				_, err := config.Target{ClientSecretCommand: "echo some-error >&2; exit 1"}.Secret()
+				Expect(err).To(MatchError(ContainSubstring("some-error")))
+			})
+		})
+
+		Context("when no secret is configured", func() {
+			It("returns an error", func() {
+				_, err := config.Target{}.Secret()
+				Expect(err).To(MatchError("no client secret configured"))
+			})
+		})
+	})
+})
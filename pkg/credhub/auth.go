@@ -0,0 +1,141 @@
+package credhub
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// userClientID is the public UAA client which CredHub deployments provide for
+// users to log in with, as the credhub CLI does. It has no secret.
+const userClientID = "credhub_cli"
+
+// Authenticator obtains access tokens for a Client, either by asking UAA for
+// one with an OAuth grant or by supplying a token obtained some other way. A
+// Client never calls Token concurrently.
+type Authenticator interface {
+	// Token returns a new access token, calling requestToken to ask UAA for
+	// one if necessary.
+	Token(requestToken TokenRequester) (Token, error)
+
+	// Identity names the UAA client or user that tokens are issued to, so that
+	// a TokenStore can keep their tokens apart. Tokens are not stored when it
+	// is empty.
+	Identity() string
+}
+
+// TokenRequester posts a form to the UAA token endpoint, returning the access
+// token in the response and, for grants which issue one, the refresh token.
+type TokenRequester func(form url.Values) (token Token, refreshToken string, err error)
+
+type clientCredentialsAuthenticator struct {
+	clientID     string
+	clientSecret string
+}
+
+// NewClientCredentialsAuthenticator returns an Authenticator which uses the
+// client_credentials grant to obtain tokens for a UAA client.
+func NewClientCredentialsAuthenticator(clientID, clientSecret string) Authenticator {
+	return &clientCredentialsAuthenticator{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+	}
+}
+
+func (a *clientCredentialsAuthenticator) Token(requestToken TokenRequester) (Token, error) {
+	token, _, err := requestToken(url.Values{
+		"client_id":     {a.clientID},
+		"client_secret": {a.clientSecret},
+		"grant_type":    {"client_credentials"},
+	})
+	return token, err
+}
+
+func (a *clientCredentialsAuthenticator) Identity() string {
+	return a.clientID
+}
+
+type passwordAuthenticator struct {
+	username string
+	password string
+}
+
+// NewPasswordAuthenticator returns an Authenticator which uses the password
+// grant to obtain tokens for a UAA user.
+func NewPasswordAuthenticator(username, password string) Authenticator {
+	return &passwordAuthenticator{
+		username: username,
+		password: password,
+	}
+}
+
+func (a *passwordAuthenticator) Token(requestToken TokenRequester) (Token, error) {
+	token, _, err := requestToken(url.Values{
+		"client_id":     {userClientID},
+		"client_secret": {""},
+		"grant_type":    {"password"},
+		"username":      {a.username},
+		"password":      {a.password},
+	})
+	return token, err
+}
+
+func (a *passwordAuthenticator) Identity() string {
+	return "user:" + a.username
+}
+
+type refreshTokenAuthenticator struct {
+	refreshToken string
+}
+
+// NewRefreshTokenAuthenticator returns an Authenticator which uses the
+// refresh_token grant to obtain tokens for the UAA user that the refresh token
+// was issued to. If UAA issues a new refresh token, it is used from then on.
+func NewRefreshTokenAuthenticator(refreshToken string) Authenticator {
+	return &refreshTokenAuthenticator{refreshToken: refreshToken}
+}
+
+func (a *refreshTokenAuthenticator) Token(requestToken TokenRequester) (Token, error) {
+	token, refreshToken, err := requestToken(url.Values{
+		"client_id":     {userClientID},
+		"client_secret": {""},
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {a.refreshToken},
+	})
+	if err != nil {
+		return Token{}, err
+	}
+
+	if refreshToken != "" {
+		a.refreshToken = refreshToken
+	}
+	return token, nil
+}
+
+// Identity is empty because the user a refresh token belongs to is not known
+// without asking UAA.
+func (a *refreshTokenAuthenticator) Identity() string {
+	return ""
+}
+
+type staticTokenAuthenticator struct {
+	accessToken string
+}
+
+// NewStaticTokenAuthenticator returns an Authenticator which always supplies
+// the given access token and never contacts UAA.
+func NewStaticTokenAuthenticator(accessToken string) Authenticator {
+	return &staticTokenAuthenticator{accessToken: accessToken}
+}
+
+func (a *staticTokenAuthenticator) Token(TokenRequester) (Token, error) {
+	expiresAt := tokenExpiry(a.accessToken)
+	if !expiresAt.IsZero() && !time.Now().Before(expiresAt) {
+		return Token{}, fmt.Errorf("access token expired at %s", expiresAt.Format(time.RFC3339))
+	}
+	return Token{AccessToken: a.accessToken, ExpiresAt: expiresAt}, nil
+}
+
+func (a *staticTokenAuthenticator) Identity() string {
+	return ""
+}
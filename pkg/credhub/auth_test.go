@@ -0,0 +1,175 @@
+package credhub_test
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/test/helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Authenticators", func() {
+	var (
+		credhubServer           *ghttp.Server
+		uaaServer               *ghttp.Server
+		skipTLSVerifyHttpClient = &http.Client{
+			Timeout: 5 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+				Dial:                (&net.Dialer{Timeout: 5 * time.Second}).Dial,
+				TLSHandshakeTimeout: 5 * time.Second,
+			},
+		}
+		credhubURL string
+		tokenStore *memoryTokenStore
+	)
+
+	jwtExpiringAt := func(exp time.Time) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"jti": helpers.RandomString(),
+			"exp": exp.Unix(),
+		}).SignedString([]byte("some-key"))
+		Expect(err).NotTo(HaveOccurred())
+		return token
+	}
+
+	respondWithInfo := func() http.HandlerFunc {
+		return ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", "/info"),
+			ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"auth-server": {"url": "%s"}}`, uaaServer.URL())),
+		)
+	}
+
+	respondWithCredential := func(token string) http.HandlerFunc {
+		return ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", "/api/v1/data", "name=%2Fsome-name&current=true"),
+			ghttp.VerifyHeaderKV("Authorization", "Bearer "+token),
+			ghttp.RespondWith(http.StatusOK, `{"data": [{"name": "/some-name", "type": "value", "value": "some-value"}]}`),
+		)
+	}
+
+	BeforeEach(func() {
+		credhubServer = ghttp.NewTLSServer()
+		uaaServer = ghttp.NewTLSServer()
+		credhubURL = strings.TrimPrefix(credhubServer.URL(), "https://")
+		tokenStore = &memoryTokenStore{tokens: map[string]credhub.Token{}}
+	})
+
+	AfterEach(func() {
+		credhubServer.Close()
+		uaaServer.Close()
+	})
+
+	Describe("NewPasswordAuthenticator", func() {
+		It("uses the password grant with the public credhub_cli client", func() {
+			token := jwtExpiringAt(time.Now().Add(time.Hour))
+			credhubServer.AppendHandlers(respondWithInfo(), respondWithCredential(token))
+			uaaServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/oauth/token"),
+				ghttp.VerifyFormKV("grant_type", "password"),
+				ghttp.VerifyFormKV("username", "some-user"),
+				ghttp.VerifyFormKV("password", "some-password"),
+				ghttp.VerifyFormKV("client_id", "credhub_cli"),
+				ghttp.VerifyFormKV("client_secret", ""),
+				ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"access_token": "%s", "refresh_token": "some-refresh-token"}`, token)),
+			))
+
+			authenticator := credhub.NewPasswordAuthenticator("some-user", "some-password")
+			client := credhub.NewClient(credhubURL, authenticator, skipTLSVerifyHttpClient, tokenStore)
+			_, err := client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).NotTo(HaveOccurred())
+
+			stored, err := tokenStore.GetToken(credhubURL, "user:some-user")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.AccessToken).To(Equal(token))
+		})
+	})
+
+	Describe("NewRefreshTokenAuthenticator", func() {
+		It("uses the refresh_token grant, switching to any new refresh token UAA issues", func() {
+			expiringToken := jwtExpiringAt(time.Now().Add(10 * time.Second))
+			newToken := jwtExpiringAt(time.Now().Add(time.Hour))
+			credhubServer.AppendHandlers(respondWithInfo(), respondWithCredential(expiringToken), respondWithCredential(newToken))
+			uaaServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/oauth/token"),
+					ghttp.VerifyFormKV("grant_type", "refresh_token"),
+					ghttp.VerifyFormKV("refresh_token", "some-refresh-token"),
+					ghttp.VerifyFormKV("client_id", "credhub_cli"),
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"access_token": "%s", "refresh_token": "other-refresh-token"}`, expiringToken)),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyFormKV("grant_type", "refresh_token"),
+					ghttp.VerifyFormKV("refresh_token", "other-refresh-token"),
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"access_token": "%s"}`, newToken)),
+				),
+			)
+
+			client := credhub.NewClient(credhubURL, credhub.NewRefreshTokenAuthenticator("some-refresh-token"), skipTLSVerifyHttpClient, tokenStore)
+			_, err := client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(uaaServer.ReceivedRequests()).To(HaveLen(2))
+			Expect(tokenStore.tokens).To(BeEmpty())
+		})
+	})
+
+	Describe("NewStaticTokenAuthenticator", func() {
+		It("sends the token without contacting UAA", func() {
+			token := jwtExpiringAt(time.Now().Add(time.Hour))
+			credhubServer.AppendHandlers(respondWithCredential(token))
+
+			client := credhub.NewClient(credhubURL, credhub.NewStaticTokenAuthenticator(token), skipTLSVerifyHttpClient, tokenStore)
+			Expect(client.Authenticate(context.Background())).To(Succeed())
+			_, err := client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(uaaServer.ReceivedRequests()).To(BeEmpty())
+			Expect(tokenStore.tokens).To(BeEmpty())
+		})
+
+		It("accepts tokens which are not JWTs", func() {
+			credhubServer.AppendHandlers(respondWithCredential("some-opaque-token"), respondWithCredential("some-opaque-token"))
+
+			client := credhub.NewClient(credhubURL, credhub.NewStaticTokenAuthenticator("some-opaque-token"), skipTLSVerifyHttpClient, nil)
+			_, err := client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the token has expired", func() {
+			It("returns an error without making a request", func() {
+				expiresAt := time.Now().Add(-time.Minute)
+				client := credhub.NewClient(credhubURL, credhub.NewStaticTokenAuthenticator(jwtExpiringAt(expiresAt)), skipTLSVerifyHttpClient, nil)
+
+				Expect(client.Authenticate(context.Background())).To(MatchError("access token expired at " + expiresAt.Format(time.RFC3339)))
+				Expect(credhubServer.ReceivedRequests()).To(BeEmpty())
+			})
+		})
+
+		Context("when CredHub rejects the token", func() {
+			It("does not send it again", func() {
+				token := jwtExpiringAt(time.Now().Add(time.Hour))
+				credhubServer.AppendHandlers(ghttp.RespondWith(http.StatusUnauthorized, `{"error": "invalid_token"}`))
+
+				client := credhub.NewClient(credhubURL, credhub.NewStaticTokenAuthenticator(token), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialByName(context.Background(), "/some-name")
+				Expect(err).To(MatchError(ContainSubstring("access token was rejected by CredHub")))
+
+				Expect(credhubServer.ReceivedRequests()).To(HaveLen(1))
+			})
+		})
+	})
+})
@@ -0,0 +1,30 @@
+package credhub_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/test/helpers"
+)
+
+var _ = Describe("Certificate", func() {
+	Describe("ParseCertificate", func() {
+		It("parses the leaf certificate", func() {
+			notAfter := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+			certificate := credhub.Certificate{Certificate: helpers.GenerateCertificatePEM("some-common-name", notAfter)}
+
+			parsed, err := certificate.ParseCertificate()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed.Subject.CommonName).To(Equal("some-common-name"))
+			Expect(parsed.NotAfter).To(Equal(notAfter))
+		})
+
+		It("returns an error when the certificate is not PEM", func() {
+			_, err := credhub.Certificate{Certificate: "some-certificate"}.ParseCertificate()
+			Expect(err).To(MatchError("failed to decode certificate PEM"))
+		})
+	})
+})
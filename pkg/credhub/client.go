@@ -1,52 +1,103 @@
 package credhub
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sync"
+
+	"github.com/google/uuid"
 )
 
 type client struct {
-	credhubAddr  string
-	clientID     string
-	clientSecret string
-	uaaURL       string
-	httpClient   *http.Client
+	credhubAddr   string
+	authenticator Authenticator
+	httpClient    *http.Client
+	tokenStore    TokenStore
+	mutualTLS     bool
+	retryPolicy   RetryPolicy
+	rateLimiter   *RateLimiter
+
+	uaaURLMutex sync.Mutex
+	uaaURL      string
+
+	tokenMutex    sync.Mutex
+	token         Token
+	rejectedToken string
 }
 
+// Client makes requests to CredHub. It is safe for concurrent use.
 type Client interface {
-	DeleteCredentialByName(name string) error
-	GetCredentialByName(name string) (Credential, error)
-	FindCredentialsByPath(path string) ([]Credential, error)
+	Authenticate(ctx context.Context) error
+	DeleteCredentialByName(ctx context.Context, name string) error
+	GetCredentialByName(ctx context.Context, name string) (Credential, error)
+	GetCredentialByID(ctx context.Context, id uuid.UUID) (Credential, error)
+	GetCredentialVersions(ctx context.Context, name string, n int) ([]Credential, error)
+	FindCredentialsByPath(ctx context.Context, path string) ([]Credential, error)
+	GenerateCredential(ctx context.Context, name string, parameters GenerateParameters, mode GenerateMode) (Credential, error)
+	RegenerateCredential(ctx context.Context, name string) (Credential, error)
+	BulkRegenerate(ctx context.Context, signedBy string) ([]string, error)
+	GetPermissions(ctx context.Context, path string) ([]Permission, error)
+	GetPermission(ctx context.Context, path, actor string) (Permission, error)
+	CreatePermission(ctx context.Context, permission Permission) (Permission, error)
+	UpdatePermission(ctx context.Context, permission Permission) (Permission, error)
+	DeletePermission(ctx context.Context, id uuid.UUID) error
+	SetCredential(ctx context.Context, credential Credential) (Credential, error)
 }
 
-func NewClient(credhubAddr, clientID, clientSecret string, httpClient *http.Client) Client {
-	return &client{
-		credhubAddr:  credhubAddr,
-		clientID:     clientID,
-		clientSecret: clientSecret,
-		httpClient:   httpClient,
+// NewClient returns a Client that authenticates with access tokens from the
+// given authenticator. If tokenStore is not nil, access tokens are shared
+// through it with other clients for the same CredHub and identity.
+func NewClient(credhubAddr string, authenticator Authenticator, httpClient *http.Client, tokenStore TokenStore, options ...ClientOption) Client {
+	c := &client{
+		credhubAddr:   credhubAddr,
+		authenticator: authenticator,
+		httpClient:    httpClient,
+		tokenStore:    tokenStore,
+	}
+	for _, option := range options {
+		option(c)
 	}
+	return c
 }
 
-func (c *client) DeleteCredentialByName(name string) error {
-	url := fmt.Sprintf("https://%s/api/v1/data?name=%s", c.credhubAddr, name)
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+// NewClientWithCertificate returns a Client that authenticates with a client
+// certificate and key, each either a path to a PEM file or PEM data, instead of
+// a UAA client. No access token is obtained or sent, so UAA is never contacted.
+func NewClientWithCertificate(credhubAddr, clientCert, clientKey string, httpClient *http.Client, options ...ClientOption) (Client, error) {
+	httpClient, err := withClientCertificate(httpClient, clientCert, clientKey)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %s", err.Error())
+		return nil, err
 	}
 
-	authToken, err := c.getToken()
+	c := &client{
+		credhubAddr: credhubAddr,
+		httpClient:  httpClient,
+		mutualTLS:   true,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c, nil
+}
+
+func (c *client) DeleteCredentialByName(ctx context.Context, name string) error {
+	requestURL := fmt.Sprintf("https://%s/api/v1/data?name=%s", c.credhubAddr, url.QueryEscape(name))
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, requestURL, nil)
 	if err != nil {
-		return fmt.Errorf("failed to get token: %s", err.Error())
+		return fmt.Errorf("failed to create request: %s", err.Error())
 	}
 
-	req.Header.Add("Authorization", "Bearer "+authToken)
+	if err := c.authorize(req); err != nil {
+		return fmt.Errorf("failed to get token: %s", err.Error())
+	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to make request: %s", err.Error())
 	}
@@ -60,21 +111,18 @@ func (c *client) DeleteCredentialByName(name string) error {
 	return nil
 }
 
-func (c *client) GetCredentialByName(name string) (Credential, error) {
-	url := fmt.Sprintf("https://%s/api/v1/data?name=%s", c.credhubAddr, name)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+func (c *client) GetCredentialByName(ctx context.Context, name string) (Credential, error) {
+	requestURL := fmt.Sprintf("https://%s/api/v1/data?name=%s&current=true", c.credhubAddr, url.QueryEscape(name))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
 	if err != nil {
 		return Credential{}, fmt.Errorf("failed to create request: %s", err.Error())
 	}
 
-	authToken, err := c.getToken()
-	if err != nil {
+	if err := c.authorize(req); err != nil {
 		return Credential{}, fmt.Errorf("failed to get token: %s", err.Error())
 	}
 
-	req.Header.Add("Authorization", "Bearer "+authToken)
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return Credential{}, fmt.Errorf("failed to make request: %s", err.Error())
 	}
@@ -106,21 +154,98 @@ func (c *client) GetCredentialByName(name string) (Credential, error) {
 	return credentials.Data[0], nil
 }
 
-func (c *client) FindCredentialsByPath(path string) ([]Credential, error) {
-	url := fmt.Sprintf("https://%s/api/v1/data?path=%s", c.credhubAddr, path)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+func (c *client) GetCredentialByID(ctx context.Context, id uuid.UUID) (Credential, error) {
+	requestURL := fmt.Sprintf("https://%s/api/v1/data/%s", c.credhubAddr, id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %s", err.Error())
+		return Credential{}, fmt.Errorf("failed to create request: %s", err.Error())
+	}
+
+	if err := c.authorize(req); err != nil {
+		return Credential{}, fmt.Errorf("failed to get token: %s", err.Error())
 	}
 
-	authToken, err := c.getToken()
+	resp, err := c.do(req)
 	if err != nil {
+		return Credential{}, fmt.Errorf("failed to make request: %s", err.Error())
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return Credential{}, &ErrCredentialNotFound{id.String()}
+	} else if resp.StatusCode != http.StatusOK {
+		return Credential{}, fmt.Errorf("got %s", resp.Status)
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Credential{}, fmt.Errorf("failed to read body: %s", err.Error())
+	}
+
+	var credential Credential
+	if err := json.Unmarshal(body, &credential); err != nil {
+		return Credential{}, fmt.Errorf("failed to parse response body: %s\n%s", err.Error(), string(body))
+	}
+
+	return credential, nil
+}
+
+// GetCredentialVersions returns the n most recent versions of the named
+// credential, newest first. If n is not positive, all versions are returned.
+func (c *client) GetCredentialVersions(ctx context.Context, name string, n int) ([]Credential, error) {
+	requestURL := fmt.Sprintf("https://%s/api/v1/data?name=%s", c.credhubAddr, url.QueryEscape(name))
+	if n > 0 {
+		requestURL = fmt.Sprintf("%s&versions=%d", requestURL, n)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %s", err.Error())
+	}
+
+	if err := c.authorize(req); err != nil {
 		return nil, fmt.Errorf("failed to get token: %s", err.Error())
 	}
 
-	req.Header.Add("Authorization", "Bearer "+authToken)
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %s", err.Error())
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &ErrCredentialNotFound{name}
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got %s", resp.Status)
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %s", err.Error())
+	}
+
+	var credentials struct {
+		Data []Credential `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &credentials); err != nil {
+		return nil, fmt.Errorf("failed to parse response body: %s\n%s", err.Error(), string(body))
+	}
+
+	return credentials.Data, nil
+}
+
+func (c *client) FindCredentialsByPath(ctx context.Context, path string) ([]Credential, error) {
+	requestURL := fmt.Sprintf("https://%s/api/v1/data?path=%s", c.credhubAddr, url.QueryEscape(path))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %s", err.Error())
+	}
+
+	if err := c.authorize(req); err != nil {
+		return nil, fmt.Errorf("failed to get token: %s", err.Error())
+	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %s", err.Error())
 	}
@@ -146,71 +271,224 @@ func (c *client) FindCredentialsByPath(path string) ([]Credential, error) {
 	return credentials.Credentials, nil
 }
 
-func (c *client) getToken() (string, error) {
-	if c.uaaURL == "" {
-		uaaURL, err := c.getUAAURL()
-		if err != nil {
-			return "", fmt.Errorf("failed to get UAA URL: %s", err.Error())
-		}
-		c.uaaURL = uaaURL
+func (c *client) SetCredential(ctx context.Context, credential Credential) (Credential, error) {
+	if credential.Value == nil {
+		return Credential{}, errors.New("credential value must be set")
+	}
+
+	requestBody, err := json.Marshal(struct {
+		Name     string                 `json:"name"`
+		Type     string                 `json:"type"`
+		Value    CredentialValue        `json:"value"`
+		Metadata map[string]interface{} `json:"metadata,omitempty"`
+	}{
+		Name:     credential.Name,
+		Type:     credential.Value.Type(),
+		Value:    credential.Value,
+		Metadata: credential.Metadata,
+	})
+	if err != nil {
+		return Credential{}, fmt.Errorf("failed to create request body: %s", err.Error())
+	}
+
+	requestURL := fmt.Sprintf("https://%s/api/v1/data", c.credhubAddr)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, requestURL, bytes.NewReader(requestBody))
+	if err != nil {
+		return Credential{}, fmt.Errorf("failed to create request: %s", err.Error())
 	}
 
-	tokenURL := fmt.Sprintf("%s/oauth/token", c.uaaURL)
-	values := url.Values{
-		"client_id":     {c.clientID},
-		"client_secret": {c.clientSecret},
-		"grant_type":    {"client_credentials"},
+	if err := c.authorize(req); err != nil {
+		return Credential{}, fmt.Errorf("failed to get token: %s", err.Error())
 	}
-	resp, err := c.httpClient.PostForm(tokenURL, values)
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := c.do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to make request: %s", err.Error())
+		return Credential{}, fmt.Errorf("failed to make request: %s", err.Error())
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("got %s", resp.Status)
+		return Credential{}, fmt.Errorf("got %s", resp.Status)
 	}
 
 	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read body: %s", err.Error())
+		return Credential{}, fmt.Errorf("failed to read body: %s", err.Error())
 	}
 
-	var tokenResponse struct {
-		AccessToken string `json:"access_token"`
-	}
-	if err := json.Unmarshal(body, &tokenResponse); err != nil {
-		return "", fmt.Errorf("failed to parse response body: %s\n%s", err.Error(), string(body))
+	var createdCredential Credential
+	if err := json.Unmarshal(body, &createdCredential); err != nil {
+		return Credential{}, fmt.Errorf("failed to parse response body: %s\n%s", err.Error(), string(body))
 	}
 
-	return tokenResponse.AccessToken, nil
+	return createdCredential, nil
 }
 
-func (c *client) getUAAURL() (string, error) {
-	infoURL := fmt.Sprintf("https://%s/info", c.credhubAddr)
-	resp, err := c.httpClient.Get(infoURL)
+// GenerateCredential asks CredHub to generate a new version of the named
+// credential, with a type and value determined by the given parameters. With
+// GenerateModeNoOverwrite, an existing credential is returned unchanged.
+func (c *client) GenerateCredential(ctx context.Context, name string, parameters GenerateParameters, mode GenerateMode) (Credential, error) {
+	if parameters == nil {
+		return Credential{}, errors.New("generate parameters must be set")
+	}
+
+	requestBody, err := json.Marshal(struct {
+		Name       string             `json:"name"`
+		Type       string             `json:"type"`
+		Parameters GenerateParameters `json:"parameters"`
+		Mode       GenerateMode       `json:"mode"`
+	}{
+		Name:       name,
+		Type:       parameters.Type(),
+		Parameters: parameters,
+		Mode:       mode,
+	})
+	if err != nil {
+		return Credential{}, fmt.Errorf("failed to create request body: %s", err.Error())
+	}
+
+	requestURL := fmt.Sprintf("https://%s/api/v1/data", c.credhubAddr)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, bytes.NewReader(requestBody))
+	if err != nil {
+		return Credential{}, fmt.Errorf("failed to create request: %s", err.Error())
+	}
+
+	if err := c.authorize(req); err != nil {
+		return Credential{}, fmt.Errorf("failed to get token: %s", err.Error())
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return Credential{}, fmt.Errorf("failed to make request: %s", err.Error())
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %s", err.Error())
+		return Credential{}, fmt.Errorf("failed to read body: %s", err.Error())
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("got %s", resp.Status)
+		return Credential{}, fmt.Errorf("got %s%s", resp.Status, errorDescription(body))
+	}
+
+	var generatedCredential Credential
+	if err := json.Unmarshal(body, &generatedCredential); err != nil {
+		return Credential{}, fmt.Errorf("failed to parse response body: %s\n%s", err.Error(), string(body))
+	}
+
+	return generatedCredential, nil
+}
+
+// RegenerateCredential asks CredHub to generate a new version of the named
+// credential using the parameters it was originally generated with.
+func (c *client) RegenerateCredential(ctx context.Context, name string) (Credential, error) {
+	requestBody, err := json.Marshal(struct {
+		Name string `json:"name"`
+	}{
+		Name: name,
+	})
+	if err != nil {
+		return Credential{}, fmt.Errorf("failed to create request body: %s", err.Error())
+	}
+
+	requestURL := fmt.Sprintf("https://%s/api/v1/regenerate", c.credhubAddr)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, bytes.NewReader(requestBody))
+	if err != nil {
+		return Credential{}, fmt.Errorf("failed to create request: %s", err.Error())
+	}
+
+	if err := c.authorize(req); err != nil {
+		return Credential{}, fmt.Errorf("failed to get token: %s", err.Error())
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return Credential{}, fmt.Errorf("failed to make request: %s", err.Error())
 	}
 
 	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read body: %s", err.Error())
+		return Credential{}, fmt.Errorf("failed to read body: %s", err.Error())
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return Credential{}, &ErrCredentialNotFound{name}
+	} else if resp.StatusCode != http.StatusOK {
+		return Credential{}, fmt.Errorf("got %s%s", resp.Status, errorDescription(body))
+	}
+
+	var regeneratedCredential Credential
+	if err := json.Unmarshal(body, &regeneratedCredential); err != nil {
+		return Credential{}, fmt.Errorf("failed to parse response body: %s\n%s", err.Error(), string(body))
+	}
+
+	return regeneratedCredential, nil
+}
+
+// BulkRegenerate regenerates every certificate signed by the named CA,
+// including those signed by any regenerated intermediate CAs, and returns the
+// names of the regenerated certificates.
+func (c *client) BulkRegenerate(ctx context.Context, signedBy string) ([]string, error) {
+	requestBody, err := json.Marshal(struct {
+		SignedBy string `json:"signed_by"`
+	}{
+		SignedBy: signedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request body: %s", err.Error())
+	}
+
+	requestURL := fmt.Sprintf("https://%s/api/v1/bulk-regenerate", c.credhubAddr)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %s", err.Error())
+	}
+
+	if err := c.authorize(req); err != nil {
+		return nil, fmt.Errorf("failed to get token: %s", err.Error())
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %s", err.Error())
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %s", err.Error())
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &ErrCredentialNotFound{signedBy}
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got %s%s", resp.Status, errorDescription(body))
 	}
 
-	var infoResponse struct {
-		AuthServer struct {
-			URL string `json:"url"`
-		} `json:"auth-server"`
+	var regenerated struct {
+		RegeneratedCredentials []string `json:"regenerated_credentials"`
 	}
-	if err := json.Unmarshal(body, &infoResponse); err != nil {
-		return "", fmt.Errorf("failed to parse response body: %s\n%s", err.Error(), string(body))
+	if err := json.Unmarshal(body, &regenerated); err != nil {
+		return nil, fmt.Errorf("failed to parse response body: %s\n%s", err.Error(), string(body))
 	}
 
-	return infoResponse.AuthServer.URL, nil
+	return regenerated.RegeneratedCredentials, nil
+}
+
+// errorDescription extracts the message from a CredHub error response so that
+// it can be appended to the status, or returns an empty string.
+func errorDescription(body []byte) string {
+	var errorResponse struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errorResponse); err != nil || errorResponse.Error == "" {
+		return ""
+	}
+	return ": " + errorResponse.Error
 }
@@ -1,10 +1,12 @@
 package credhub_test
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -81,15 +83,15 @@ var _ = Describe("Client", func() {
 			)
 
 			credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-			client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
+			client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
 
-			Expect(client.DeleteCredentialByName(credentialName)).To(Succeed())
+			Expect(client.DeleteCredentialByName(context.Background(), credentialName)).To(Succeed())
 		})
 
 		Context("when getting the UAA URL fails", func() {
 			It("returns an error", func() {
-				client := credhub.NewClient("some-bad-url", clientID, clientSecret, skipTLSVerifyHttpClient)
-				err := client.DeleteCredentialByName("some-name")
+				client := credhub.NewClient("some-bad-url", credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				err := client.DeleteCredentialByName(context.Background(), "some-name")
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -104,8 +106,8 @@ var _ = Describe("Client", func() {
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				err := client.DeleteCredentialByName("some-name")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				err := client.DeleteCredentialByName(context.Background(), "some-name")
 				Expect(err).To(MatchError(ContainSubstring(http.StatusText(http.StatusInternalServerError))))
 			})
 		})
@@ -120,8 +122,8 @@ var _ = Describe("Client", func() {
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				err := client.DeleteCredentialByName("some-name")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				err := client.DeleteCredentialByName(context.Background(), "some-name")
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -136,8 +138,8 @@ var _ = Describe("Client", func() {
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				err := client.DeleteCredentialByName("some-name")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				err := client.DeleteCredentialByName(context.Background(), "some-name")
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -158,8 +160,8 @@ var _ = Describe("Client", func() {
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				err := client.DeleteCredentialByName("some-name")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				err := client.DeleteCredentialByName(context.Background(), "some-name")
 				Expect(err).To(MatchError(ContainSubstring(http.StatusText(http.StatusInternalServerError))))
 			})
 		})
@@ -180,8 +182,8 @@ var _ = Describe("Client", func() {
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				err := client.DeleteCredentialByName("some-name")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				err := client.DeleteCredentialByName(context.Background(), "some-name")
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -200,8 +202,8 @@ var _ = Describe("Client", func() {
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				err := client.DeleteCredentialByName(credentialName)
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				err := client.DeleteCredentialByName(context.Background(), credentialName)
 				Expect(err).To(BeAssignableToTypeOf(&credhub.ErrCredentialNotFound{}))
 			})
 		})
@@ -220,8 +222,8 @@ var _ = Describe("Client", func() {
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				err := client.DeleteCredentialByName(credentialName)
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				err := client.DeleteCredentialByName(context.Background(), credentialName)
 				Expect(err).To(MatchError(ContainSubstring(http.StatusText(http.StatusInternalServerError))))
 			})
 		})
@@ -231,7 +233,7 @@ var _ = Describe("Client", func() {
 		It("returns the named credential", func() {
 			credentialID := uuid.New()
 			credentialName := "some-name"
-			credentialType := "some-type"
+			credentialType := "value"
 			credentialValue := "some-value"
 			credentialVersionCreatedAt := time.Now().UTC()
 
@@ -239,7 +241,7 @@ var _ = Describe("Client", func() {
 
 			credhubServer.AppendHandlers(
 				ghttp.CombineHandlers(
-					ghttp.VerifyRequest("GET", "/api/v1/data", "name="+credentialName),
+					ghttp.VerifyRequest("GET", "/api/v1/data", "name="+credentialName+"&current=true"),
 					ghttp.VerifyHeaderKV("Authorization", "Bearer "+token),
 					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(
 						`{"data": [{
@@ -254,21 +256,123 @@ var _ = Describe("Client", func() {
 			)
 
 			credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-			client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-			credential, err := client.GetCredentialByName(credentialName)
+			client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+			credential, err := client.GetCredentialByName(context.Background(), credentialName)
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(credential.Name).To(Equal(credentialName))
 			Expect(credential.ID).To(Equal(credentialID))
 			Expect(credential.Type).To(Equal(credentialType))
-			Expect(credential.Value).To(Equal(credentialValue))
+			Expect(credential.Value).To(Equal(credhub.Value(credentialValue)))
 			Expect(credential.VersionCreatedAt).To(BeTemporally("~", credentialVersionCreatedAt, time.Second))
 		})
 
+		It("escapes the name", func() {
+			token := configureTokenHandlers()
+
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/data", "name=%2Fsome+dir%2Fa%26b%3Dc&current=true"),
+					ghttp.VerifyHeaderKV("Authorization", "Bearer "+token),
+					ghttp.RespondWith(http.StatusOK, `{"data": [{"name": "/some dir/a&b=c", "type": "value", "value": "some-value"}]}`),
+				),
+			)
+
+			credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+			client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+			credential, err := client.GetCredentialByName(context.Background(), "/some dir/a&b=c")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credential.Name).To(Equal("/some dir/a&b=c"))
+		})
+
+		for _, structuredCredential := range []struct {
+			credentialType string
+			value          string
+			expectedValue  credhub.CredentialValue
+		}{
+			{
+				credentialType: "json",
+				value:          `{"some-key": ["some-value"]}`,
+				expectedValue:  credhub.JSON{"some-key": []interface{}{"some-value"}},
+			},
+			{
+				credentialType: "password",
+				value:          `"some-password"`,
+				expectedValue:  credhub.Password("some-password"),
+			},
+			{
+				credentialType: "user",
+				value:          `{"username": "some-username", "password": "some-password", "password_hash": "some-hash"}`,
+				expectedValue:  credhub.User{Username: "some-username", Password: "some-password", PasswordHash: "some-hash"},
+			},
+			{
+				credentialType: "certificate",
+				value:          `{"ca": "some-ca", "certificate": "some-certificate", "private_key": "some-private-key"}`,
+				expectedValue:  credhub.Certificate{CA: "some-ca", Certificate: "some-certificate", PrivateKey: "some-private-key"},
+			},
+			{
+				credentialType: "rsa",
+				value:          `{"public_key": "some-public-key", "private_key": "some-private-key"}`,
+				expectedValue:  credhub.RSA{PublicKey: "some-public-key", PrivateKey: "some-private-key"},
+			},
+			{
+				credentialType: "ssh",
+				value:          `{"public_key": "some-public-key", "private_key": "some-private-key", "public_key_fingerprint": "some-fingerprint"}`,
+				expectedValue:  credhub.SSH{PublicKey: "some-public-key", PrivateKey: "some-private-key", PublicKeyFingerprint: "some-fingerprint"},
+			},
+		} {
+			structuredCredential := structuredCredential
+
+			It(fmt.Sprintf("parses %s credential values", structuredCredential.credentialType), func() {
+				credentialName := "some-name"
+
+				configureTokenHandlers()
+
+				credhubServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/api/v1/data", "name="+credentialName+"&current=true"),
+						ghttp.RespondWith(http.StatusOK, fmt.Sprintf(
+							`{"data": [{"name": "%s", "type": "%s", "value": %s}]}`,
+							credentialName, structuredCredential.credentialType, structuredCredential.value,
+						)),
+					),
+				)
+
+				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				credential, err := client.GetCredentialByName(context.Background(), credentialName)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(credential.Type).To(Equal(structuredCredential.credentialType))
+				Expect(credential.Value).To(Equal(structuredCredential.expectedValue))
+			})
+		}
+
+		Context("when the credential type is not supported", func() {
+			It("returns an error", func() {
+				credentialName := "some-name"
+
+				configureTokenHandlers()
+
+				credhubServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/api/v1/data", "name="+credentialName+"&current=true"),
+						ghttp.RespondWith(http.StatusOK, `{"data": [{"name": "some-name", "type": "some-type", "value": "some-value"}]}`),
+					),
+				)
+
+				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialByName(context.Background(), credentialName)
+				Expect(err).To(MatchError(ContainSubstring("unsupported credential type 'some-type'")))
+			})
+		})
+
 		Context("when getting the UAA URL fails", func() {
 			It("returns an error", func() {
-				client := credhub.NewClient("some-bad-url", clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.GetCredentialByName("some-name")
+				client := credhub.NewClient("some-bad-url", credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialByName(context.Background(), "some-name")
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -283,8 +387,8 @@ var _ = Describe("Client", func() {
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.GetCredentialByName("some-name")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialByName(context.Background(), "some-name")
 				Expect(err).To(MatchError(ContainSubstring(http.StatusText(http.StatusInternalServerError))))
 			})
 		})
@@ -299,8 +403,8 @@ var _ = Describe("Client", func() {
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.GetCredentialByName("some-name")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialByName(context.Background(), "some-name")
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -315,8 +419,8 @@ var _ = Describe("Client", func() {
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.GetCredentialByName("some-name")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialByName(context.Background(), "some-name")
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -337,8 +441,8 @@ var _ = Describe("Client", func() {
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.GetCredentialByName("some-name")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialByName(context.Background(), "some-name")
 				Expect(err).To(MatchError(ContainSubstring(http.StatusText(http.StatusInternalServerError))))
 			})
 		})
@@ -359,8 +463,8 @@ var _ = Describe("Client", func() {
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.GetCredentialByName("some-name")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialByName(context.Background(), "some-name")
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -373,14 +477,14 @@ var _ = Describe("Client", func() {
 
 				credhubServer.AppendHandlers(
 					ghttp.CombineHandlers(
-						ghttp.VerifyRequest("GET", "/api/v1/data", "name="+credentialName),
+						ghttp.VerifyRequest("GET", "/api/v1/data", "name="+credentialName+"&current=true"),
 						ghttp.RespondWith(http.StatusNotFound, "some-error"),
 					),
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.GetCredentialByName(credentialName)
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialByName(context.Background(), credentialName)
 				Expect(err).To(BeAssignableToTypeOf(&credhub.ErrCredentialNotFound{}))
 			})
 		})
@@ -393,14 +497,14 @@ var _ = Describe("Client", func() {
 
 				credhubServer.AppendHandlers(
 					ghttp.CombineHandlers(
-						ghttp.VerifyRequest("GET", "/api/v1/data", "name="+credentialName),
+						ghttp.VerifyRequest("GET", "/api/v1/data", "name="+credentialName+"&current=true"),
 						ghttp.RespondWith(http.StatusInternalServerError, "some-error"),
 					),
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.GetCredentialByName(credentialName)
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialByName(context.Background(), credentialName)
 				Expect(err).To(MatchError(ContainSubstring(http.StatusText(http.StatusInternalServerError))))
 			})
 		})
@@ -413,14 +517,14 @@ var _ = Describe("Client", func() {
 
 				credhubServer.AppendHandlers(
 					ghttp.CombineHandlers(
-						ghttp.VerifyRequest("GET", "/api/v1/data", "name="+credentialName),
+						ghttp.VerifyRequest("GET", "/api/v1/data", "name="+credentialName+"&current=true"),
 						ghttp.RespondWith(http.StatusOK, "some-non-json-response"),
 					),
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.GetCredentialByName(credentialName)
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialByName(context.Background(), credentialName)
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -433,14 +537,233 @@ var _ = Describe("Client", func() {
 
 				credhubServer.AppendHandlers(
 					ghttp.CombineHandlers(
-						ghttp.VerifyRequest("GET", "/api/v1/data", "name="+credentialName),
+						ghttp.VerifyRequest("GET", "/api/v1/data", "name="+credentialName+"&current=true"),
 						ghttp.RespondWith(http.StatusOK, `{"data": []}`),
 					),
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.GetCredentialByName(credentialName)
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialByName(context.Background(), credentialName)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("GetCredentialByID", func() {
+		It("returns the credential version with the given ID", func() {
+			credentialID := uuid.New()
+			credentialVersionCreatedAt := time.Now().UTC()
+
+			token := configureTokenHandlers()
+
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/data/"+credentialID.String()),
+					ghttp.VerifyHeaderKV("Authorization", "Bearer "+token),
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(
+						`{
+							"id": "%s",
+							"name": "some-name",
+							"type": "value",
+							"value": "some-value",
+							"version_created_at": "%s"
+						}`, credentialID, credentialVersionCreatedAt.Format(time.RFC3339),
+					)),
+				),
+			)
+
+			credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+			client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+			credential, err := client.GetCredentialByID(context.Background(), credentialID)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credential.ID).To(Equal(credentialID))
+			Expect(credential.Name).To(Equal("some-name"))
+			Expect(credential.Value).To(Equal(credhub.Value("some-value")))
+			Expect(credential.VersionCreatedAt).To(BeTemporally("~", credentialVersionCreatedAt, time.Second))
+		})
+
+		Context("when getting the token fails", func() {
+			It("returns an error", func() {
+				client := credhub.NewClient("some-bad-url", credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialByID(context.Background(), uuid.New())
+				Expect(err).To(MatchError(ContainSubstring("failed to get token")))
+			})
+		})
+
+		Context("when the data response is 404", func() {
+			It("returns an ErrCredentialNotFound", func() {
+				credentialID := uuid.New()
+
+				configureTokenHandlers()
+
+				credhubServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/api/v1/data/"+credentialID.String()),
+						ghttp.RespondWith(http.StatusNotFound, "some-error"),
+					),
+				)
+
+				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialByID(context.Background(), credentialID)
+				Expect(err).To(BeAssignableToTypeOf(&credhub.ErrCredentialNotFound{}))
+			})
+		})
+
+		Context("when the data response is not 200 or 404", func() {
+			It("returns an error", func() {
+				credentialID := uuid.New()
+
+				configureTokenHandlers()
+
+				credhubServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/api/v1/data/"+credentialID.String()),
+						ghttp.RespondWith(http.StatusInternalServerError, "some-error"),
+					),
+				)
+
+				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialByID(context.Background(), credentialID)
+				Expect(err).To(MatchError(ContainSubstring(http.StatusText(http.StatusInternalServerError))))
+			})
+		})
+
+		Context("when the data response is not valid JSON", func() {
+			It("returns an error", func() {
+				credentialID := uuid.New()
+
+				configureTokenHandlers()
+
+				credhubServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/api/v1/data/"+credentialID.String()),
+						ghttp.RespondWith(http.StatusOK, "some-non-json-response"),
+					),
+				)
+
+				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialByID(context.Background(), credentialID)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("GetCredentialVersions", func() {
+		It("returns the requested number of versions of the named credential", func() {
+			credentialName := "some-name"
+			newID := uuid.New()
+			oldID := uuid.New()
+
+			token := configureTokenHandlers()
+
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/data", "name="+credentialName+"&versions=2"),
+					ghttp.VerifyHeaderKV("Authorization", "Bearer "+token),
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(
+						`{"data": [
+							{"id": "%s", "name": "%s", "type": "value", "value": "new-value"},
+							{"id": "%s", "name": "%s", "type": "value", "value": "old-value"}
+						]}`, newID, credentialName, oldID, credentialName,
+					)),
+				),
+			)
+
+			credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+			client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+			credentials, err := client.GetCredentialVersions(context.Background(), credentialName, 2)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credentials).To(HaveLen(2))
+			Expect(credentials[0].ID).To(Equal(newID))
+			Expect(credentials[0].Value).To(Equal(credhub.Value("new-value")))
+			Expect(credentials[1].ID).To(Equal(oldID))
+			Expect(credentials[1].Value).To(Equal(credhub.Value("old-value")))
+		})
+
+		It("requests all versions when n is not positive", func() {
+			credentialName := "some-name"
+
+			configureTokenHandlers()
+
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/data", "name="+credentialName),
+					ghttp.RespondWith(http.StatusOK, `{"data": []}`),
+				),
+			)
+
+			credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+			client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+			credentials, err := client.GetCredentialVersions(context.Background(), credentialName, 0)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credentials).To(BeEmpty())
+		})
+
+		Context("when getting the token fails", func() {
+			It("returns an error", func() {
+				client := credhub.NewClient("some-bad-url", credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialVersions(context.Background(), "some-name", 1)
+				Expect(err).To(MatchError(ContainSubstring("failed to get token")))
+			})
+		})
+
+		Context("when the data response is 404", func() {
+			It("returns an ErrCredentialNotFound", func() {
+				configureTokenHandlers()
+
+				credhubServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/api/v1/data"),
+						ghttp.RespondWith(http.StatusNotFound, "some-error"),
+					),
+				)
+
+				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialVersions(context.Background(), "some-name", 1)
+				Expect(err).To(BeAssignableToTypeOf(&credhub.ErrCredentialNotFound{}))
+			})
+		})
+
+		Context("when the data response is not 200 or 404", func() {
+			It("returns an error", func() {
+				configureTokenHandlers()
+
+				credhubServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/api/v1/data"),
+						ghttp.RespondWith(http.StatusInternalServerError, "some-error"),
+					),
+				)
+
+				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialVersions(context.Background(), "some-name", 1)
+				Expect(err).To(MatchError(ContainSubstring(http.StatusText(http.StatusInternalServerError))))
+			})
+		})
+
+		Context("when the data response is not valid JSON", func() {
+			It("returns an error", func() {
+				configureTokenHandlers()
+
+				credhubServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/api/v1/data"),
+						ghttp.RespondWith(http.StatusOK, "some-non-json-response"),
+					),
+				)
+
+				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GetCredentialVersions(context.Background(), "some-name", 1)
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -458,7 +781,7 @@ var _ = Describe("Client", func() {
 
 			credhubServer.AppendHandlers(
 				ghttp.CombineHandlers(
-					ghttp.VerifyRequest("GET", "/api/v1/data", "path="+path),
+					ghttp.VerifyRequest("GET", "/api/v1/data", "path="+url.QueryEscape(path)),
 					ghttp.VerifyHeaderKV("Authorization", "Bearer "+token),
 					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(
 						`{"credentials": [
@@ -472,8 +795,8 @@ var _ = Describe("Client", func() {
 			)
 
 			credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-			client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-			credentials, err := client.FindCredentialsByPath(path)
+			client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+			credentials, err := client.FindCredentialsByPath(context.Background(), path)
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(credentials).To(HaveLen(2))
@@ -481,18 +804,18 @@ var _ = Describe("Client", func() {
 			Expect(credentials[0].VersionCreatedAt).To(BeTemporally("~", credential1VersionCreatedAt, time.Second))
 			Expect(credentials[0].ID).To(Equal(emptyUUID))
 			Expect(credentials[0].Type).To(BeEmpty())
-			Expect(credentials[0].Value).To(BeEmpty())
+			Expect(credentials[0].Value).To(BeNil())
 			Expect(credentials[1].Name).To(Equal(credential2Name))
 			Expect(credentials[1].VersionCreatedAt).To(BeTemporally("~", credential2VersionCreatedAt, time.Second))
 			Expect(credentials[1].ID).To(Equal(emptyUUID))
 			Expect(credentials[1].Type).To(BeEmpty())
-			Expect(credentials[1].Value).To(BeEmpty())
+			Expect(credentials[1].Value).To(BeNil())
 		})
 
 		Context("when getting the UAA URL fails", func() {
 			It("returns an error", func() {
-				client := credhub.NewClient("some-bad-url", clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.FindCredentialsByPath("some-path")
+				client := credhub.NewClient("some-bad-url", credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.FindCredentialsByPath(context.Background(), "some-path")
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -507,8 +830,8 @@ var _ = Describe("Client", func() {
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.FindCredentialsByPath("some-path")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.FindCredentialsByPath(context.Background(), "some-path")
 				Expect(err).To(MatchError(ContainSubstring(http.StatusText(http.StatusInternalServerError))))
 			})
 		})
@@ -523,8 +846,8 @@ var _ = Describe("Client", func() {
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.FindCredentialsByPath("some-path")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.FindCredentialsByPath(context.Background(), "some-path")
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -539,8 +862,8 @@ var _ = Describe("Client", func() {
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.FindCredentialsByPath("some-path")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.FindCredentialsByPath(context.Background(), "some-path")
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -561,8 +884,8 @@ var _ = Describe("Client", func() {
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.FindCredentialsByPath("some-path")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.FindCredentialsByPath(context.Background(), "some-path")
 				Expect(err).To(MatchError(ContainSubstring(http.StatusText(http.StatusInternalServerError))))
 			})
 		})
@@ -583,8 +906,8 @@ var _ = Describe("Client", func() {
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.FindCredentialsByPath("some-path")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.FindCredentialsByPath(context.Background(), "some-path")
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -597,14 +920,14 @@ var _ = Describe("Client", func() {
 
 				credhubServer.AppendHandlers(
 					ghttp.CombineHandlers(
-						ghttp.VerifyRequest("GET", "/api/v1/data", "path="+path),
+						ghttp.VerifyRequest("GET", "/api/v1/data", "path="+url.QueryEscape(path)),
 						ghttp.RespondWith(http.StatusNotFound, "some-error"),
 					),
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.FindCredentialsByPath(path)
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.FindCredentialsByPath(context.Background(), path)
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -617,16 +940,466 @@ var _ = Describe("Client", func() {
 
 				credhubServer.AppendHandlers(
 					ghttp.CombineHandlers(
-						ghttp.VerifyRequest("GET", "/api/v1/data", "path="+path),
+						ghttp.VerifyRequest("GET", "/api/v1/data", "path="+url.QueryEscape(path)),
+						ghttp.RespondWith(http.StatusOK, "some-non-json-response"),
+					),
+				)
+
+				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.FindCredentialsByPath(context.Background(), path)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("SetCredential", func() {
+		It("sets the credential and returns the created credential", func() {
+			credentialID := uuid.New()
+			credentialName := "some-name"
+			credentialType := "value"
+			credentialValue := "some-value"
+			credentialVersionCreatedAt := time.Now().UTC()
+
+			token := configureTokenHandlers()
+
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/api/v1/data"),
+					ghttp.VerifyHeaderKV("Authorization", "Bearer "+token),
+					ghttp.VerifyJSON(fmt.Sprintf(
+						`{"name": "%s", "type": "%s", "value": "%s"}`,
+						credentialName, credentialType, credentialValue,
+					)),
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(
+						`{
+							"id": "%s",
+							"name": "%s",
+							"type": "%s",
+							"value": "%s",
+							"version_created_at": "%s"
+						}`, credentialID, credentialName, credentialType, credentialValue, credentialVersionCreatedAt.Format(time.RFC3339),
+					)),
+				),
+			)
+
+			credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+			client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+			credential, err := client.SetCredential(context.Background(), credhub.Credential{
+				Name:  credentialName,
+				Value: credhub.Value(credentialValue),
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credential.Name).To(Equal(credentialName))
+			Expect(credential.ID).To(Equal(credentialID))
+			Expect(credential.Type).To(Equal(credentialType))
+			Expect(credential.Value).To(Equal(credhub.Value(credentialValue)))
+			Expect(credential.VersionCreatedAt).To(BeTemporally("~", credentialVersionCreatedAt, time.Second))
+		})
+
+		It("sends structured values as JSON objects", func() {
+			configureTokenHandlers()
+
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/api/v1/data"),
+					ghttp.VerifyJSON(`{
+						"name": "some-name",
+						"type": "ssh",
+						"value": {"public_key": "some-public-key", "private_key": "some-private-key"}
+					}`),
+					ghttp.RespondWith(http.StatusOK, `{"name": "some-name", "type": "ssh", "value": {}}`),
+				),
+			)
+
+			credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+			client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+			_, err := client.SetCredential(context.Background(), credhub.Credential{
+				Name:  "some-name",
+				Value: credhub.SSH{PublicKey: "some-public-key", PrivateKey: "some-private-key"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("sends and returns metadata", func() {
+			configureTokenHandlers()
+
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/api/v1/data"),
+					ghttp.VerifyJSON(`{
+						"name": "some-name",
+						"type": "value",
+						"value": "some-value",
+						"metadata": {"owner": "some-team"}
+					}`),
+					ghttp.RespondWith(http.StatusOK, `{
+						"name": "some-name",
+						"type": "value",
+						"value": "some-value",
+						"metadata": {"owner": "some-team"}
+					}`),
+				),
+			)
+
+			credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+			client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+			credential, err := client.SetCredential(context.Background(), credhub.Credential{
+				Name:     "some-name",
+				Value:    credhub.Value("some-value"),
+				Metadata: map[string]interface{}{"owner": "some-team"},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credential.Metadata).To(Equal(map[string]interface{}{"owner": "some-team"}))
+		})
+
+		Context("when the credential has no value", func() {
+			It("returns an error", func() {
+				client := credhub.NewClient("some-bad-url", credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.SetCredential(context.Background(), credhub.Credential{Name: "some-name"})
+				Expect(err).To(MatchError("credential value must be set"))
+			})
+		})
+
+		Context("when getting the token fails", func() {
+			It("returns an error", func() {
+				client := credhub.NewClient("some-bad-url", credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.SetCredential(context.Background(), credhub.Credential{Name: "some-name", Value: credhub.Value("some-value")})
+				Expect(err).To(MatchError(ContainSubstring("failed to get token")))
+			})
+		})
+
+		Context("when the data response is not 200", func() {
+			It("returns an error", func() {
+				configureTokenHandlers()
+
+				credhubServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("PUT", "/api/v1/data"),
+						ghttp.RespondWith(http.StatusBadRequest, "some-error"),
+					),
+				)
+
+				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.SetCredential(context.Background(), credhub.Credential{Name: "some-name", Value: credhub.Value("some-value")})
+				Expect(err).To(MatchError(ContainSubstring(http.StatusText(http.StatusBadRequest))))
+			})
+		})
+
+		Context("when the data response is not valid JSON", func() {
+			It("returns an error", func() {
+				configureTokenHandlers()
+
+				credhubServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("PUT", "/api/v1/data"),
 						ghttp.RespondWith(http.StatusOK, "some-non-json-response"),
 					),
 				)
 
 				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
-				client := credhub.NewClient(credhubURL, clientID, clientSecret, skipTLSVerifyHttpClient)
-				_, err := client.FindCredentialsByPath(path)
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.SetCredential(context.Background(), credhub.Credential{Name: "some-name", Value: credhub.Value("some-value")})
 				Expect(err).To(HaveOccurred())
 			})
 		})
 	})
+
+	Describe("GenerateCredential", func() {
+		It("generates the credential with the given parameters and returns it", func() {
+			credentialID := uuid.New()
+
+			token := configureTokenHandlers()
+
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/v1/data"),
+					ghttp.VerifyHeaderKV("Authorization", "Bearer "+token),
+					ghttp.VerifyJSON(`{
+						"name": "some-name",
+						"type": "password",
+						"parameters": {"length": 20, "exclude_upper": true, "include_special": true},
+						"mode": "overwrite"
+					}`),
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{
+						"id": "%s",
+						"name": "some-name",
+						"type": "password",
+						"value": "some-generated-password"
+					}`, credentialID)),
+				),
+			)
+
+			credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+			client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+			credential, err := client.GenerateCredential(context.Background(), "some-name", credhub.PasswordParameters{
+				Length:         20,
+				ExcludeUpper:   true,
+				IncludeSpecial: true,
+			}, credhub.GenerateModeOverwrite)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credential.ID).To(Equal(credentialID))
+			Expect(credential.Name).To(Equal("some-name"))
+			Expect(credential.Value).To(Equal(credhub.Password("some-generated-password")))
+		})
+
+		It("sends certificate parameters", func() {
+			configureTokenHandlers()
+
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/v1/data"),
+					ghttp.VerifyJSON(`{
+						"name": "some-name",
+						"type": "certificate",
+						"parameters": {
+							"common_name": "example.com",
+							"alternative_names": ["www.example.com", "10.0.0.1"],
+							"ca": "/some-ca",
+							"key_length": 4096,
+							"duration": 30
+						},
+						"mode": "no-overwrite"
+					}`),
+					ghttp.RespondWith(http.StatusOK, `{"name": "some-name", "type": "certificate", "value": {"certificate": "some-certificate"}}`),
+				),
+			)
+
+			credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+			client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+			credential, err := client.GenerateCredential(context.Background(), "some-name", credhub.CertificateParameters{
+				CommonName:       "example.com",
+				AlternativeNames: []string{"www.example.com", "10.0.0.1"},
+				CA:               "/some-ca",
+				KeyLength:        4096,
+				Duration:         30,
+			}, credhub.GenerateModeNoOverwrite)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credential.Value).To(Equal(credhub.Certificate{Certificate: "some-certificate"}))
+		})
+
+		Context("when no parameters are provided", func() {
+			It("returns an error", func() {
+				client := credhub.NewClient("some-bad-url", credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GenerateCredential(context.Background(), "some-name", nil, credhub.GenerateModeOverwrite)
+				Expect(err).To(MatchError("generate parameters must be set"))
+			})
+		})
+
+		Context("when getting the token fails", func() {
+			It("returns an error", func() {
+				client := credhub.NewClient("some-bad-url", credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GenerateCredential(context.Background(), "some-name", credhub.RSAParameters{}, credhub.GenerateModeOverwrite)
+				Expect(err).To(MatchError(ContainSubstring("failed to get token")))
+			})
+		})
+
+		Context("when the data response is not 200", func() {
+			It("returns an error including the CredHub error message", func() {
+				configureTokenHandlers()
+
+				credhubServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/api/v1/data"),
+						ghttp.RespondWith(http.StatusBadRequest, `{"error": "some-error"}`),
+					),
+				)
+
+				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GenerateCredential(context.Background(), "some-name", credhub.SSHParameters{}, credhub.GenerateModeOverwrite)
+				Expect(err).To(MatchError("got 400 Bad Request: some-error"))
+			})
+		})
+
+		Context("when the data response is not valid JSON", func() {
+			It("returns an error", func() {
+				configureTokenHandlers()
+
+				credhubServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/api/v1/data"),
+						ghttp.RespondWith(http.StatusOK, "some-non-json-response"),
+					),
+				)
+
+				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.GenerateCredential(context.Background(), "some-name", credhub.SSHParameters{}, credhub.GenerateModeOverwrite)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("RegenerateCredential", func() {
+		It("regenerates the credential and returns the new version", func() {
+			credentialID := uuid.New()
+
+			token := configureTokenHandlers()
+
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/v1/regenerate"),
+					ghttp.VerifyHeaderKV("Authorization", "Bearer "+token),
+					ghttp.VerifyJSON(`{"name": "some-name"}`),
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{
+						"id": "%s",
+						"name": "some-name",
+						"type": "password",
+						"value": "some-regenerated-password"
+					}`, credentialID)),
+				),
+			)
+
+			credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+			client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+			credential, err := client.RegenerateCredential(context.Background(), "some-name")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credential.ID).To(Equal(credentialID))
+			Expect(credential.Value).To(Equal(credhub.Password("some-regenerated-password")))
+		})
+
+		Context("when the credential does not exist", func() {
+			It("returns a credential not found error", func() {
+				configureTokenHandlers()
+
+				credhubServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/api/v1/regenerate"),
+						ghttp.RespondWith(http.StatusNotFound, `{"error": "some-error"}`),
+					),
+				)
+
+				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.RegenerateCredential(context.Background(), "some-name")
+				Expect(err).To(BeAssignableToTypeOf(&credhub.ErrCredentialNotFound{}))
+			})
+		})
+
+		Context("when getting the token fails", func() {
+			It("returns an error", func() {
+				client := credhub.NewClient("some-bad-url", credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.RegenerateCredential(context.Background(), "some-name")
+				Expect(err).To(MatchError(ContainSubstring("failed to get token")))
+			})
+		})
+
+		Context("when the response is not 200", func() {
+			It("returns an error including the CredHub error message", func() {
+				configureTokenHandlers()
+
+				credhubServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/api/v1/regenerate"),
+						ghttp.RespondWith(http.StatusBadRequest, `{"error": "some-error"}`),
+					),
+				)
+
+				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.RegenerateCredential(context.Background(), "some-name")
+				Expect(err).To(MatchError("got 400 Bad Request: some-error"))
+			})
+		})
+	})
+
+	Describe("BulkRegenerate", func() {
+		It("regenerates the certificates signed by the CA and returns their names", func() {
+			token := configureTokenHandlers()
+
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/v1/bulk-regenerate"),
+					ghttp.VerifyHeaderKV("Authorization", "Bearer "+token),
+					ghttp.VerifyJSON(`{"signed_by": "/some-ca"}`),
+					ghttp.RespondWith(http.StatusOK, `{"regenerated_credentials": ["/some-cert", "/some-other-cert"]}`),
+				),
+			)
+
+			credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+			client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+			names, err := client.BulkRegenerate(context.Background(), "/some-ca")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names).To(Equal([]string{"/some-cert", "/some-other-cert"}))
+		})
+
+		Context("when the CA does not exist", func() {
+			It("returns a credential not found error", func() {
+				configureTokenHandlers()
+
+				credhubServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/api/v1/bulk-regenerate"),
+						ghttp.RespondWith(http.StatusNotFound, `{"error": "some-error"}`),
+					),
+				)
+
+				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.BulkRegenerate(context.Background(), "/some-ca")
+				Expect(err).To(BeAssignableToTypeOf(&credhub.ErrCredentialNotFound{}))
+			})
+		})
+
+		Context("when the response is not valid JSON", func() {
+			It("returns an error", func() {
+				configureTokenHandlers()
+
+				credhubServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/api/v1/bulk-regenerate"),
+						ghttp.RespondWith(http.StatusOK, "some-non-json-response"),
+					),
+				)
+
+				credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+				client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+				_, err := client.BulkRegenerate(context.Background(), "/some-ca")
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("cancellation", func() {
+		It("does not make requests with a cancelled context", func() {
+			credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+			client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err := client.GetCredentialByName(ctx, "some-name")
+			Expect(err).To(MatchError(ContainSubstring("context canceled")))
+
+			Expect(credhubServer.ReceivedRequests()).To(BeEmpty())
+			Expect(uaaServer.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("abandons requests which are in flight when the context is cancelled", func() {
+			configureTokenHandlers()
+
+			unblock := make(chan struct{})
+			defer close(unblock)
+			credhubServer.AppendHandlers(func(w http.ResponseWriter, r *http.Request) {
+				<-unblock
+			})
+
+			credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+			client := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			start := time.Now()
+			err := client.DeleteCredentialByName(ctx, "some-name")
+			Expect(err).To(MatchError(ContainSubstring("context deadline exceeded")))
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		})
+	})
 })
@@ -0,0 +1,65 @@
+package credhub
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// FormatValue renders a credential value as text: strings as-is, JSON
+// pretty-printed, users as key-value pairs and keys and certificates as their
+// PEM blocks.
+func FormatValue(value CredentialValue) (string, error) {
+	switch v := value.(type) {
+	case Value:
+		return string(v), nil
+	case Password:
+		return string(v), nil
+	case JSON:
+		output, err := json.MarshalIndent(v, "", "  ")
+		return string(output), err
+	case User:
+		output := fmt.Sprintf("username: %s\npassword: %s", v.Username, v.Password)
+		if v.PasswordHash != "" {
+			output += fmt.Sprintf("\npassword_hash: %s", v.PasswordHash)
+		}
+		return output, nil
+	case Certificate:
+		return joinBlocks(v.CA, v.Certificate, v.PrivateKey), nil
+	case RSA:
+		return joinBlocks(v.PublicKey, v.PrivateKey), nil
+	case SSH:
+		return joinBlocks(v.PublicKey, v.PrivateKey), nil
+	default:
+		return "", fmt.Errorf("unsupported credential type %T", value)
+	}
+}
+
+func joinBlocks(blocks ...string) string {
+	var nonEmptyBlocks []string
+	for _, block := range blocks {
+		if block = strings.TrimSpace(block); block != "" {
+			nonEmptyBlocks = append(nonEmptyBlocks, block)
+		}
+	}
+	return strings.Join(nonEmptyBlocks, "\n")
+}
+
+// ParseValue is the inverse of FormatValue for the credential types whose
+// text form is unambiguous: value, password and json.
+func ParseValue(credentialType, text string) (CredentialValue, error) {
+	switch credentialType {
+	case TypeValue:
+		return Value(text), nil
+	case TypePassword:
+		return Password(text), nil
+	case TypeJSON:
+		var value JSON
+		if err := json.Unmarshal([]byte(text), &value); err != nil {
+			return nil, fmt.Errorf("invalid json value: %s", err)
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("cannot parse credential type '%s'", credentialType)
+	}
+}
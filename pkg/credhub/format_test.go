@@ -0,0 +1,50 @@
+package credhub_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+var _ = Describe("Format", func() {
+	Describe("FormatValue", func() {
+		It("formats users as key-value pairs", func() {
+			output, err := credhub.FormatValue(credhub.User{Username: "some-user", Password: "some-password"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(Equal("username: some-user\npassword: some-password"))
+		})
+
+		It("formats keys as their PEM blocks", func() {
+			output, err := credhub.FormatValue(credhub.RSA{PublicKey: "some-public-key\n", PrivateKey: "some-private-key\n"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(Equal("some-public-key\nsome-private-key"))
+		})
+	})
+
+	Describe("ParseValue", func() {
+		It("parses values, passwords and json", func() {
+			value, err := credhub.ParseValue("value", "some-value")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal(credhub.Value("some-value")))
+
+			value, err = credhub.ParseValue("password", "some-password")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal(credhub.Password("some-password")))
+
+			value, err = credhub.ParseValue("json", `{"some-key": "some-value"}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal(credhub.JSON{"some-key": "some-value"}))
+		})
+
+		It("returns an error for invalid json", func() {
+			_, err := credhub.ParseValue("json", "not-json")
+			Expect(err).To(MatchError(HavePrefix("invalid json value: ")))
+		})
+
+		It("returns an error for types without an unambiguous text form", func() {
+			_, err := credhub.ParseValue("certificate", "some-certificate")
+			Expect(err).To(MatchError("cannot parse credential type 'certificate'"))
+		})
+	})
+})
@@ -0,0 +1,51 @@
+package credhub
+
+// GenerateMode controls what CredHub does when asked to generate a credential
+// which already exists.
+type GenerateMode string
+
+const (
+	// GenerateModeOverwrite generates a new version of an existing credential.
+	GenerateModeOverwrite GenerateMode = "overwrite"
+	// GenerateModeNoOverwrite leaves an existing credential unchanged, which
+	// CredHub decides atomically with creating it.
+	GenerateModeNoOverwrite GenerateMode = "no-overwrite"
+)
+
+// GenerateParameters is implemented by the parameters for each of the
+// credential types that CredHub can generate.
+type GenerateParameters interface {
+	Type() string
+}
+
+type PasswordParameters struct {
+	Length         int  `json:"length,omitempty"`
+	ExcludeUpper   bool `json:"exclude_upper,omitempty"`
+	ExcludeLower   bool `json:"exclude_lower,omitempty"`
+	ExcludeNumber  bool `json:"exclude_number,omitempty"`
+	IncludeSpecial bool `json:"include_special,omitempty"`
+}
+
+type CertificateParameters struct {
+	CommonName       string   `json:"common_name,omitempty"`
+	AlternativeNames []string `json:"alternative_names,omitempty"`
+	CA               string   `json:"ca,omitempty"`
+	IsCA             bool     `json:"is_ca,omitempty"`
+	SelfSign         bool     `json:"self_sign,omitempty"`
+	KeyLength        int      `json:"key_length,omitempty"`
+	Duration         int      `json:"duration,omitempty"`
+}
+
+type RSAParameters struct {
+	KeyLength int `json:"key_length,omitempty"`
+}
+
+type SSHParameters struct {
+	KeyLength  int    `json:"key_length,omitempty"`
+	SSHComment string `json:"ssh_comment,omitempty"`
+}
+
+func (PasswordParameters) Type() string    { return TypePassword }
+func (CertificateParameters) Type() string { return TypeCertificate }
+func (RSAParameters) Type() string         { return TypeRSA }
+func (SSHParameters) Type() string         { return TypeSSH }
@@ -0,0 +1,216 @@
+package credhub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+const (
+	OperationRead     = "read"
+	OperationWrite    = "write"
+	OperationDelete   = "delete"
+	OperationReadACL  = "read_acl"
+	OperationWriteACL = "write_acl"
+)
+
+// Operations lists every operation that a permission can grant.
+var Operations = []string{OperationRead, OperationWrite, OperationDelete, OperationReadACL, OperationWriteACL}
+
+// Permission grants an actor operations on the credentials at a path. A path
+// ending in '*' applies to every credential whose name starts with the rest of
+// the path.
+type Permission struct {
+	UUID       uuid.UUID `json:"uuid"`
+	Actor      string    `json:"actor"`
+	Path       string    `json:"path"`
+	Operations []string  `json:"operations"`
+}
+
+type ErrPermissionNotFound struct {
+	path  string
+	actor string
+}
+
+func (e *ErrPermissionNotFound) Error() string {
+	return fmt.Sprintf("could not find permission for actor %s on path %s", e.actor, e.path)
+}
+
+// GetPermissions returns every permission set on exactly the given path. It
+// uses the v1 API, which is the only way to list permissions, so like CredHub
+// it only accepts the name of an existing credential and not a wildcard path.
+func (c *client) GetPermissions(ctx context.Context, path string) ([]Permission, error) {
+	requestURL := fmt.Sprintf("https://%s/api/v1/permissions?credential_name=%s", c.credhubAddr, url.QueryEscape(path))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %s", err.Error())
+	}
+
+	if err := c.authorize(req); err != nil {
+		return nil, fmt.Errorf("failed to get token: %s", err.Error())
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %s", err.Error())
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %s", err.Error())
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &ErrCredentialNotFound{path}
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got %s%s", resp.Status, errorDescription(body))
+	}
+
+	var permissionsResponse struct {
+		Permissions []Permission `json:"permissions"`
+	}
+	if err := json.Unmarshal(body, &permissionsResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse response body: %s\n%s", err.Error(), string(body))
+	}
+
+	for i := range permissionsResponse.Permissions {
+		permissionsResponse.Permissions[i].Path = path
+	}
+
+	return permissionsResponse.Permissions, nil
+}
+
+// GetPermission returns the permission granted to the actor on the path.
+func (c *client) GetPermission(ctx context.Context, path, actor string) (Permission, error) {
+	requestURL := fmt.Sprintf(
+		"https://%s/api/v2/permissions?path=%s&actor=%s",
+		c.credhubAddr, url.QueryEscape(path), url.QueryEscape(actor),
+	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return Permission{}, fmt.Errorf("failed to create request: %s", err.Error())
+	}
+
+	if err := c.authorize(req); err != nil {
+		return Permission{}, fmt.Errorf("failed to get token: %s", err.Error())
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return Permission{}, fmt.Errorf("failed to make request: %s", err.Error())
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Permission{}, fmt.Errorf("failed to read body: %s", err.Error())
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return Permission{}, &ErrPermissionNotFound{path: path, actor: actor}
+	} else if resp.StatusCode != http.StatusOK {
+		return Permission{}, fmt.Errorf("got %s%s", resp.Status, errorDescription(body))
+	}
+
+	var permission Permission
+	if err := json.Unmarshal(body, &permission); err != nil {
+		return Permission{}, fmt.Errorf("failed to parse response body: %s\n%s", err.Error(), string(body))
+	}
+
+	return permission, nil
+}
+
+// CreatePermission grants a new permission and returns it with its UUID.
+func (c *client) CreatePermission(ctx context.Context, permission Permission) (Permission, error) {
+	requestURL := fmt.Sprintf("https://%s/api/v2/permissions", c.credhubAddr)
+	return c.writePermission(ctx, http.MethodPost, requestURL, permission, http.StatusCreated)
+}
+
+// UpdatePermission replaces the permission identified by its UUID.
+func (c *client) UpdatePermission(ctx context.Context, permission Permission) (Permission, error) {
+	requestURL := fmt.Sprintf("https://%s/api/v2/permissions/%s", c.credhubAddr, permission.UUID)
+	return c.writePermission(ctx, http.MethodPut, requestURL, permission, http.StatusOK)
+}
+
+func (c *client) DeletePermission(ctx context.Context, id uuid.UUID) error {
+	requestURL := fmt.Sprintf("https://%s/api/v2/permissions/%s", c.credhubAddr, id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %s", err.Error())
+	}
+
+	if err := c.authorize(req); err != nil {
+		return fmt.Errorf("failed to get token: %s", err.Error())
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %s", err.Error())
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read body: %s", err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("got %s%s", resp.Status, errorDescription(body))
+	}
+
+	return nil
+}
+
+func (c *client) writePermission(ctx context.Context, method, requestURL string, permission Permission, expectedStatus int) (Permission, error) {
+	requestBody, err := json.Marshal(struct {
+		Actor      string   `json:"actor"`
+		Path       string   `json:"path"`
+		Operations []string `json:"operations"`
+	}{
+		Actor:      permission.Actor,
+		Path:       permission.Path,
+		Operations: permission.Operations,
+	})
+	if err != nil {
+		return Permission{}, fmt.Errorf("failed to create request body: %s", err.Error())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewReader(requestBody))
+	if err != nil {
+		return Permission{}, fmt.Errorf("failed to create request: %s", err.Error())
+	}
+
+	if err := c.authorize(req); err != nil {
+		return Permission{}, fmt.Errorf("failed to get token: %s", err.Error())
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return Permission{}, fmt.Errorf("failed to make request: %s", err.Error())
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Permission{}, fmt.Errorf("failed to read body: %s", err.Error())
+	}
+
+	if resp.StatusCode != expectedStatus {
+		return Permission{}, fmt.Errorf("got %s%s", resp.Status, errorDescription(body))
+	}
+
+	var writtenPermission Permission
+	if err := json.Unmarshal(body, &writtenPermission); err != nil {
+		return Permission{}, fmt.Errorf("failed to parse response body: %s\n%s", err.Error(), string(body))
+	}
+
+	return writtenPermission, nil
+}
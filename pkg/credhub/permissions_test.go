@@ -0,0 +1,231 @@
+package credhub_test
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/test/helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Permissions", func() {
+	var (
+		credhubServer           *ghttp.Server
+		uaaServer               *ghttp.Server
+		skipTLSVerifyHttpClient = &http.Client{
+			Timeout: 5 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+				Dial:                (&net.Dialer{Timeout: 5 * time.Second}).Dial,
+				TLSHandshakeTimeout: 5 * time.Second,
+			},
+		}
+		client credhub.Client
+		token  string
+	)
+
+	BeforeEach(func() {
+		credhubServer = ghttp.NewTLSServer()
+		uaaServer = ghttp.NewTLSServer()
+		clientID := helpers.RandomString()
+		clientSecret := helpers.RandomString()
+		token = helpers.RandomString()
+
+		credhubServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/info"),
+				ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"auth-server": {"url": "%s"}}`, uaaServer.URL())),
+			),
+		)
+		uaaServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/oauth/token"),
+				ghttp.VerifyFormKV("client_id", clientID),
+				ghttp.VerifyFormKV("client_secret", clientSecret),
+				ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"access_token": "%s"}`, token)),
+			),
+		)
+
+		credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+		client = credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+	})
+
+	AfterEach(func() {
+		credhubServer.Close()
+		uaaServer.Close()
+	})
+
+	Describe("GetPermissions", func() {
+		It("returns the permissions on the path", func() {
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/permissions", "credential_name=%2Fsome%2F%2A"),
+					ghttp.VerifyHeaderKV("Authorization", "Bearer "+token),
+					ghttp.RespondWith(http.StatusOK, `{
+						"credential_name": "/some/*",
+						"permissions": [
+							{"actor": "uaa-client:a", "operations": ["read", "write"]},
+							{"actor": "uaa-client:b", "operations": ["read_acl"]}
+						]
+					}`),
+				),
+			)
+
+			permissions, err := client.GetPermissions(context.Background(), "/some/*")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(permissions).To(Equal([]credhub.Permission{
+				{Actor: "uaa-client:a", Path: "/some/*", Operations: []string{"read", "write"}},
+				{Actor: "uaa-client:b", Path: "/some/*", Operations: []string{"read_acl"}},
+			}))
+		})
+
+		Context("when the response is not 200", func() {
+			It("returns an error", func() {
+				credhubServer.AppendHandlers(
+					ghttp.RespondWith(http.StatusForbidden, `{"error": "some-error"}`),
+				)
+
+				_, err := client.GetPermissions(context.Background(), "/some/path")
+				Expect(err).To(MatchError("got 403 Forbidden: some-error"))
+			})
+		})
+	})
+
+	Describe("GetPermission", func() {
+		It("returns the permission for the path and actor", func() {
+			id := uuid.New()
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v2/permissions", "path=%2Fsome%2Fpath&actor=uaa-client%3Aa"),
+					ghttp.VerifyHeaderKV("Authorization", "Bearer "+token),
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{
+						"uuid": "%s",
+						"actor": "uaa-client:a",
+						"path": "/some/path",
+						"operations": ["read"]
+					}`, id)),
+				),
+			)
+
+			permission, err := client.GetPermission(context.Background(), "/some/path", "uaa-client:a")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(permission).To(Equal(credhub.Permission{
+				UUID:       id,
+				Actor:      "uaa-client:a",
+				Path:       "/some/path",
+				Operations: []string{"read"},
+			}))
+		})
+
+		Context("when the permission does not exist", func() {
+			It("returns a permission not found error", func() {
+				credhubServer.AppendHandlers(
+					ghttp.RespondWith(http.StatusNotFound, `{"error": "some-error"}`),
+				)
+
+				_, err := client.GetPermission(context.Background(), "/some/path", "uaa-client:a")
+				Expect(err).To(BeAssignableToTypeOf(&credhub.ErrPermissionNotFound{}))
+				Expect(err).To(MatchError("could not find permission for actor uaa-client:a on path /some/path"))
+			})
+		})
+	})
+
+	Describe("CreatePermission", func() {
+		It("creates the permission and returns it", func() {
+			id := uuid.New()
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/v2/permissions"),
+					ghttp.VerifyHeaderKV("Authorization", "Bearer "+token),
+					ghttp.VerifyJSON(`{"actor": "uaa-client:a", "path": "/some/*", "operations": ["read"]}`),
+					ghttp.RespondWith(http.StatusCreated, fmt.Sprintf(`{
+						"uuid": "%s",
+						"actor": "uaa-client:a",
+						"path": "/some/*",
+						"operations": ["read"]
+					}`, id)),
+				),
+			)
+
+			permission, err := client.CreatePermission(context.Background(), credhub.Permission{
+				Actor:      "uaa-client:a",
+				Path:       "/some/*",
+				Operations: []string{"read"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(permission.UUID).To(Equal(id))
+		})
+
+		Context("when the response is not 201", func() {
+			It("returns an error", func() {
+				credhubServer.AppendHandlers(
+					ghttp.RespondWith(http.StatusConflict, `{"error": "some-error"}`),
+				)
+
+				_, err := client.CreatePermission(context.Background(), credhub.Permission{Actor: "a", Path: "/p", Operations: []string{"read"}})
+				Expect(err).To(MatchError("got 409 Conflict: some-error"))
+			})
+		})
+	})
+
+	Describe("UpdatePermission", func() {
+		It("replaces the permission", func() {
+			id := uuid.New()
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/api/v2/permissions/"+id.String()),
+					ghttp.VerifyJSON(`{"actor": "uaa-client:a", "path": "/some/path", "operations": ["read", "write"]}`),
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{
+						"uuid": "%s",
+						"actor": "uaa-client:a",
+						"path": "/some/path",
+						"operations": ["read", "write"]
+					}`, id)),
+				),
+			)
+
+			permission, err := client.UpdatePermission(context.Background(), credhub.Permission{
+				UUID:       id,
+				Actor:      "uaa-client:a",
+				Path:       "/some/path",
+				Operations: []string{"read", "write"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(permission.Operations).To(Equal([]string{"read", "write"}))
+		})
+	})
+
+	Describe("DeletePermission", func() {
+		It("deletes the permission", func() {
+			id := uuid.New()
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", "/api/v2/permissions/"+id.String()),
+					ghttp.VerifyHeaderKV("Authorization", "Bearer "+token),
+					ghttp.RespondWith(http.StatusOK, `{}`),
+				),
+			)
+
+			Expect(client.DeletePermission(context.Background(), id)).To(Succeed())
+		})
+
+		Context("when the response is not 200", func() {
+			It("returns an error", func() {
+				credhubServer.AppendHandlers(
+					ghttp.RespondWith(http.StatusNotFound, `{"error": "some-error"}`),
+				)
+
+				Expect(client.DeletePermission(context.Background(), uuid.New())).To(MatchError("got 404 Not Found: some-error"))
+			})
+		})
+	})
+})
@@ -0,0 +1,56 @@
+package credhub
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits how often requests are made, allowing short bursts. It is
+// safe for concurrent use.
+type RateLimiter struct {
+	interval time.Duration
+	burst    int
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewRateLimiter returns a RateLimiter which allows requestsPerSecond requests
+// per second on average, and up to burst requests at once after a lull.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		interval: time.Duration(float64(time.Second) / requestsPerSecond),
+		burst:    burst,
+	}
+}
+
+// Wait blocks until a request may be made or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	earliest := now.Add(-time.Duration(l.burst-1) * l.interval)
+	if l.next.Before(earliest) {
+		l.next = earliest
+	}
+	at := l.next
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	wait := at.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
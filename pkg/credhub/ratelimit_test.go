@@ -0,0 +1,31 @@
+package credhub_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RateLimiter", func() {
+	It("allows a burst of requests without waiting", func() {
+		limiter := credhub.NewRateLimiter(1, 3)
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			Expect(limiter.Wait(context.Background())).To(Succeed())
+		}
+		Expect(time.Since(start)).To(BeNumerically("<", 100*time.Millisecond))
+	})
+
+	It("stops waiting when the context is done", func() {
+		limiter := credhub.NewRateLimiter(0.1, 1)
+		Expect(limiter.Wait(context.Background())).To(Succeed())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		Expect(limiter.Wait(ctx)).To(MatchError(context.DeadlineExceeded))
+	})
+})
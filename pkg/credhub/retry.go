@@ -0,0 +1,182 @@
+package credhub
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how requests which fail with a transient error, such as
+// a 503 from a load balancer in front of CredHub, are retried. Only requests
+// which read from CredHub are retried once they may have reached it. Even a
+// repeated PUT adds a credential version and a repeated DELETE fails with a
+// 404, so requests which change CredHub are only retried when the connection
+// is refused.
+type RetryPolicy struct {
+	// MaxRetries is the number of times a request is retried after its first
+	// attempt. Zero disables retries.
+	MaxRetries int
+
+	// InitialBackoff is the delay before the first retry. It doubles for each
+	// retry after that, up to MaxBackoff, and a random jitter of up to half
+	// the delay is taken off so that clients do not retry in lockstep.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the longest delay between attempts. A request is not
+	// retried if the server asks, with Retry-After, to wait longer than this.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used by cfs unless it is told
+// otherwise.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+	}
+}
+
+// ClientOption configures optional behaviour of a Client.
+type ClientOption func(*client)
+
+// WithRetryPolicy makes a Client retry requests according to policy. Clients
+// do not retry requests by default.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithRateLimiter makes a Client wait for limiter before every request,
+// including retries and requests to UAA. A limiter may be shared by several
+// clients to limit them together.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// send makes a single request to CredHub or UAA, waiting for the rate limiter
+// first and retrying the request according to the retry policy.
+func (c *client) send(req *http.Request) (*http.Response, error) {
+	for retries := 0; ; retries++ {
+		if c.rateLimiter != nil {
+			if err := c.rateLimiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+
+		attempt := req
+		if retries > 0 && req.GetBody != nil {
+			attempt = req.Clone(req.Context())
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attempt.Body = body
+		}
+
+		resp, err := c.httpClient.Do(attempt)
+		if retries >= c.retryPolicy.MaxRetries || !rewindable(req) {
+			return resp, err
+		}
+
+		delay, ok := c.retryPolicy.delay(req, retries, resp, err)
+		if !ok {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// rewindable reports whether the body of a request can be sent again.
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// readOnly reports whether a request only reads from CredHub, so that it is
+// safe to send again even if CredHub may have already handled it.
+func readOnly(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// delay returns how long to wait before retrying a request which got resp or
+// err, or false if the request should not be retried because the failure is
+// not transient, the request may have changed CredHub, or the server asked to
+// wait longer than MaxBackoff.
+func (p RetryPolicy) delay(req *http.Request, retries int, resp *http.Response, err error) (time.Duration, bool) {
+	if err != nil {
+		if errors.Is(err, syscall.ECONNREFUSED) || (readOnly(req) && errors.Is(err, syscall.ECONNRESET)) {
+			return p.backoff(retries), true
+		}
+		return 0, false
+	}
+	if !readOnly(req) {
+		return 0, false
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+	default:
+		return 0, false
+	}
+
+	if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		return retryAfter, retryAfter <= p.MaxBackoff
+	}
+	return p.backoff(retries), true
+}
+
+// backoff returns the jittered exponential backoff before the given retry.
+func (p RetryPolicy) backoff(retries int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 0; i < retries && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return backoff - time.Duration(rand.Int63n(int64(backoff)/2+1))
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
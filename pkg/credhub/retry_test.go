@@ -0,0 +1,234 @@
+package credhub_test
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Retries", func() {
+	var (
+		credhubServer           *ghttp.Server
+		skipTLSVerifyHttpClient = &http.Client{
+			Timeout: 5 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+				Dial:                (&net.Dialer{Timeout: 5 * time.Second}).Dial,
+				TLSHandshakeTimeout: 5 * time.Second,
+			},
+		}
+		policy    credhub.RetryPolicy
+		newClient func(options ...credhub.ClientOption) credhub.Client
+	)
+
+	respondWithCredential := ghttp.RespondWith(http.StatusOK, `{"data": [{"name": "/some-name", "type": "value", "value": "some-value"}]}`)
+
+	BeforeEach(func() {
+		credhubServer = ghttp.NewTLSServer()
+		credhubServer.SetAllowUnhandledRequests(true)
+		credhubServer.SetUnhandledRequestStatusCode(http.StatusInternalServerError)
+
+		policy = credhub.RetryPolicy{
+			MaxRetries:     2,
+			InitialBackoff: 10 * time.Millisecond,
+			MaxBackoff:     2 * time.Second,
+		}
+		newClient = func(options ...credhub.ClientOption) credhub.Client {
+			credhubURL := strings.TrimPrefix(credhubServer.URL(), "https://")
+			return credhub.NewClient(credhubURL, credhub.NewStaticTokenAuthenticator("some-token"), skipTLSVerifyHttpClient, nil, options...)
+		}
+	})
+
+	AfterEach(func() {
+		credhubServer.Close()
+	})
+
+	It("does not retry requests by default", func() {
+		credhubServer.AppendHandlers(ghttp.RespondWith(http.StatusServiceUnavailable, ""), respondWithCredential)
+
+		_, err := newClient().GetCredentialByName(context.Background(), "/some-name")
+		Expect(err).To(MatchError(ContainSubstring("503 Service Unavailable")))
+		Expect(credhubServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	for _, statusCode := range []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	} {
+		statusCode := statusCode
+		It("retries idempotent requests which get a "+http.StatusText(statusCode), func() {
+			credhubServer.AppendHandlers(ghttp.RespondWith(statusCode, ""), ghttp.RespondWith(statusCode, ""), respondWithCredential)
+
+			credential, err := newClient(credhub.WithRetryPolicy(policy)).GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credential.Name).To(Equal("/some-name"))
+			Expect(credhubServer.ReceivedRequests()).To(HaveLen(3))
+		})
+	}
+
+	It("gives up after the maximum number of retries", func() {
+		credhubServer.AppendHandlers(
+			ghttp.RespondWith(http.StatusBadGateway, ""),
+			ghttp.RespondWith(http.StatusBadGateway, ""),
+			ghttp.RespondWith(http.StatusBadGateway, ""),
+			respondWithCredential,
+		)
+
+		_, err := newClient(credhub.WithRetryPolicy(policy)).GetCredentialByName(context.Background(), "/some-name")
+		Expect(err).To(MatchError(ContainSubstring("502 Bad Gateway")))
+		Expect(credhubServer.ReceivedRequests()).To(HaveLen(3))
+	})
+
+	It("does not retry other errors", func() {
+		credhubServer.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, ""), respondWithCredential)
+
+		_, err := newClient(credhub.WithRetryPolicy(policy)).GetCredentialByName(context.Background(), "/some-name")
+		Expect(err).To(MatchError(ContainSubstring("500 Internal Server Error")))
+		Expect(credhubServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("does not retry a PUT once CredHub may have handled it", func() {
+		credhubServer.AppendHandlers(
+			ghttp.RespondWith(http.StatusBadGateway, ""),
+			ghttp.RespondWith(http.StatusOK, `{"name": "/some-name", "type": "value", "value": "some-value"}`),
+		)
+
+		_, err := newClient(credhub.WithRetryPolicy(policy)).SetCredential(context.Background(), credhub.Credential{
+			Name:  "/some-name",
+			Value: credhub.Value("some-value"),
+		})
+		Expect(err).To(MatchError(ContainSubstring("502 Bad Gateway")))
+		Expect(credhubServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("does not retry a DELETE once CredHub may have handled it", func() {
+		credhubServer.AppendHandlers(
+			ghttp.RespondWith(http.StatusGatewayTimeout, ""),
+			ghttp.RespondWith(http.StatusNotFound, `{"error": "The request could not be completed because the credential does not exist or you do not have sufficient authorization."}`),
+		)
+
+		err := newClient(credhub.WithRetryPolicy(policy)).DeleteCredentialByName(context.Background(), "/some-name")
+		Expect(err).To(MatchError(ContainSubstring("504 Gateway Timeout")))
+		Expect(credhubServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("does not retry a POST once CredHub may have handled it", func() {
+		credhubServer.AppendHandlers(ghttp.RespondWith(http.StatusServiceUnavailable, ""))
+
+		_, err := newClient(credhub.WithRetryPolicy(policy)).GenerateCredential(context.Background(), "/some-name", credhub.PasswordParameters{}, credhub.GenerateModeOverwrite)
+		Expect(err).To(MatchError(ContainSubstring("503 Service Unavailable")))
+		Expect(credhubServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("retries requests which change CredHub when the connection is refused", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		addr := listener.Addr().String()
+		Expect(listener.Close()).To(Succeed())
+
+		laterServer := ghttp.NewUnstartedServer()
+		defer laterServer.Close()
+		laterServer.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("PUT", "/api/v1/data"),
+			ghttp.VerifyJSON(`{"name": "/some-name", "type": "value", "value": "some-value"}`),
+			ghttp.RespondWith(http.StatusOK, `{"name": "/some-name", "type": "value", "value": "some-value"}`),
+		))
+		time.AfterFunc(50*time.Millisecond, func() {
+			defer GinkgoRecover()
+			listener, err := net.Listen("tcp", addr)
+			Expect(err).NotTo(HaveOccurred())
+			laterServer.HTTPTestServer.Listener.Close()
+			laterServer.HTTPTestServer.Listener = listener
+			laterServer.HTTPTestServer.StartTLS()
+		})
+
+		policy.MaxRetries = 5
+		policy.InitialBackoff = 100 * time.Millisecond
+		client := credhub.NewClient(addr, credhub.NewStaticTokenAuthenticator("some-token"), skipTLSVerifyHttpClient, nil, credhub.WithRetryPolicy(policy))
+		_, err = client.SetCredential(context.Background(), credhub.Credential{
+			Name:  "/some-name",
+			Value: credhub.Value("some-value"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(laterServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("waits as long as Retry-After asks", func() {
+		credhubServer.AppendHandlers(
+			ghttp.RespondWith(http.StatusServiceUnavailable, "", http.Header{"Retry-After": {"1"}}),
+			respondWithCredential,
+		)
+
+		start := time.Now()
+		_, err := newClient(credhub.WithRetryPolicy(policy)).GetCredentialByName(context.Background(), "/some-name")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically(">=", time.Second))
+	})
+
+	It("does not retry when Retry-After asks to wait longer than the maximum backoff", func() {
+		credhubServer.AppendHandlers(
+			ghttp.RespondWith(http.StatusServiceUnavailable, "", http.Header{"Retry-After": {"60"}}),
+			respondWithCredential,
+		)
+
+		_, err := newClient(credhub.WithRetryPolicy(policy)).GetCredentialByName(context.Background(), "/some-name")
+		Expect(err).To(MatchError(ContainSubstring("503 Service Unavailable")))
+		Expect(credhubServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("stops waiting to retry when the context is cancelled", func() {
+		credhubServer.AppendHandlers(ghttp.RespondWith(http.StatusServiceUnavailable, "", http.Header{"Retry-After": {"2"}}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := newClient(credhub.WithRetryPolicy(policy)).GetCredentialByName(ctx, "/some-name")
+		Expect(err).To(MatchError(ContainSubstring("context deadline exceeded")))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+
+	It("backs off exponentially with jitter", func() {
+		policy.MaxRetries = 3
+		policy.InitialBackoff = 100 * time.Millisecond
+		credhubServer.AppendHandlers(
+			ghttp.RespondWith(http.StatusServiceUnavailable, ""),
+			ghttp.RespondWith(http.StatusServiceUnavailable, ""),
+			ghttp.RespondWith(http.StatusServiceUnavailable, ""),
+			respondWithCredential,
+		)
+
+		start := time.Now()
+		_, err := newClient(credhub.WithRetryPolicy(policy)).GetCredentialByName(context.Background(), "/some-name")
+		Expect(err).NotTo(HaveOccurred())
+
+		elapsed := time.Since(start)
+		Expect(elapsed).To(BeNumerically(">=", (50+100+200)*time.Millisecond))
+		Expect(elapsed).To(BeNumerically("<", (100+200+400+500)*time.Millisecond))
+	})
+
+	Describe("WithRateLimiter", func() {
+		It("spaces out requests", func() {
+			for i := 0; i < 4; i++ {
+				credhubServer.AppendHandlers(respondWithCredential)
+			}
+			client := newClient(credhub.WithRateLimiter(credhub.NewRateLimiter(20, 1)))
+
+			start := time.Now()
+			for i := 0; i < 4; i++ {
+				_, err := client.GetCredentialByName(context.Background(), "/some-name")
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(time.Since(start)).To(BeNumerically(">=", 150*time.Millisecond))
+		})
+	})
+})
@@ -0,0 +1,126 @@
+package credhub
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// NewHTTPClient returns an HTTP client for talking to CredHub and UAA. Unless
+// skipTLSValidation is set, it verifies their certificates against the system
+// CAs and caCerts, each of which is either a path to a PEM file or PEM data.
+func NewHTTPClient(caCerts []string, skipTLSValidation bool) (*http.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: skipTLSValidation}
+
+	if !skipTLSValidation {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+
+		for _, caCert := range caCerts {
+			pemData, source, err := readPEM(caCert, "CA certificate")
+			if err != nil {
+				return nil, err
+			}
+
+			if !rootCAs.AppendCertsFromPEM(pemData) {
+				return nil, fmt.Errorf("failed to load %s: no PEM certificates found", source)
+			}
+		}
+
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	return &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig:     tlsConfig,
+			Dial:                (&net.Dialer{Timeout: 5 * time.Second}).Dial,
+			TLSHandshakeTimeout: 5 * time.Second,
+		},
+	}, nil
+}
+
+// withClientCertificate returns a copy of httpClient which presents the given
+// certificate and key, each either a path to a PEM file or PEM data, to
+// servers that ask for one.
+func withClientCertificate(httpClient *http.Client, clientCert, clientKey string) (*http.Client, error) {
+	certPEM, _, err := readPEM(clientCert, "client certificate")
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, _, err := readPEM(clientKey, "client key")
+	if err != nil {
+		return nil, err
+	}
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %s", err.Error())
+	}
+
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport)
+	}
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.Certificates = []tls.Certificate{certificate}
+
+	withCertificate := *httpClient
+	withCertificate.Transport = transport
+	return &withCertificate, nil
+}
+
+// readPEM returns PEM data which is either given inline or read from the file
+// at the given path, along with a description of where it came from.
+func readPEM(value, description string) ([]byte, string, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), "inline " + description, nil
+	}
+
+	pemData, err := ioutil.ReadFile(value)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s: %s", description, err.Error())
+	}
+	return pemData, value, nil
+}
+
+// describeTLSError rewrites certificate verification failures, which Go
+// reports in terms of x509 internals, into errors that say how to fix them.
+func describeTLSError(err error) error {
+	host := "the server"
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+			host = u.Host
+		}
+	}
+
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	switch {
+	case errors.As(err, &unknownAuthorityErr):
+		return fmt.Errorf(
+			"the certificate presented by %s is signed by an unknown authority: "+
+				"provide its CA certificate with '--ca-cert' or CREDHUB_CA_CERT, or use '--skip-tls-validation'",
+			host,
+		)
+	case errors.As(err, &hostnameErr):
+		return fmt.Errorf(
+			"the certificate presented by %s does not match its address (%s): "+
+				"connect using a name the certificate is valid for, or use '--skip-tls-validation'",
+			host, strings.TrimPrefix(hostnameErr.Error(), "x509: "),
+		)
+	}
+	return err
+}
@@ -0,0 +1,242 @@
+package credhub_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/test/server"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TLS", func() {
+	var (
+		ca      *server.CA
+		tempDir string
+	)
+
+	startServer := func(hosts ...string) *httptest.Server {
+		certificatePEM, privateKeyPEM, err := ca.IssueCertificate(hosts...)
+		Expect(err).NotTo(HaveOccurred())
+		certificate, err := tls.X509KeyPair([]byte(certificatePEM), []byte(privateKeyPEM))
+		Expect(err).NotTo(HaveOccurred())
+
+		s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		s.TLS = &tls.Config{Certificates: []tls.Certificate{certificate}}
+		s.StartTLS()
+		return s
+	}
+
+	BeforeEach(func() {
+		var err error
+		ca, err = server.NewCA()
+		Expect(err).NotTo(HaveOccurred())
+
+		tempDir, err = ioutil.TempDir("", "tls")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	Describe("NewHTTPClient", func() {
+		It("trusts CA certificates given as paths", func() {
+			s := startServer("127.0.0.1")
+			defer s.Close()
+
+			caPath := filepath.Join(tempDir, "ca.pem")
+			Expect(ioutil.WriteFile(caPath, []byte(ca.CertificatePEM), 0600)).To(Succeed())
+
+			httpClient, err := credhub.NewHTTPClient([]string{caPath}, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			resp, err := httpClient.Get(s.URL)
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+		})
+
+		It("trusts CA certificates given as PEM", func() {
+			s := startServer("127.0.0.1")
+			defer s.Close()
+
+			otherCA, err := server.NewCA()
+			Expect(err).NotTo(HaveOccurred())
+
+			httpClient, err := credhub.NewHTTPClient([]string{otherCA.CertificatePEM, ca.CertificatePEM}, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			resp, err := httpClient.Get(s.URL)
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+		})
+
+		It("does not verify certificates when skipping TLS validation", func() {
+			s := startServer("127.0.0.1")
+			defer s.Close()
+
+			httpClient, err := credhub.NewHTTPClient(nil, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			resp, err := httpClient.Get(s.URL)
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+		})
+
+		Context("when a CA certificate file does not exist", func() {
+			It("returns an error", func() {
+				_, err := credhub.NewHTTPClient([]string{filepath.Join(tempDir, "missing.pem")}, false)
+				Expect(err).To(MatchError(ContainSubstring("failed to read CA certificate")))
+			})
+		})
+
+		Context("when a CA certificate file contains no certificates", func() {
+			It("returns an error", func() {
+				caPath := filepath.Join(tempDir, "ca.pem")
+				Expect(ioutil.WriteFile(caPath, []byte("some-garbage"), 0600)).To(Succeed())
+
+				_, err := credhub.NewHTTPClient([]string{caPath}, false)
+				Expect(err).To(MatchError("failed to load " + caPath + ": no PEM certificates found"))
+			})
+		})
+	})
+
+	Describe("NewClientWithCertificate", func() {
+		var (
+			s              *httptest.Server
+			requests       []*http.Request
+			clientCertPath string
+			clientKeyPath  string
+		)
+
+		BeforeEach(func() {
+			certificatePEM, privateKeyPEM, err := ca.IssueCertificate("127.0.0.1")
+			Expect(err).NotTo(HaveOccurred())
+			certificate, err := tls.X509KeyPair([]byte(certificatePEM), []byte(privateKeyPEM))
+			Expect(err).NotTo(HaveOccurred())
+			clientCAs := x509.NewCertPool()
+			clientCAs.AppendCertsFromPEM([]byte(ca.CertificatePEM))
+
+			requests = nil
+			s = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r)
+				fmt.Fprint(w, `{"data": [{"name": "/some-name", "type": "value", "value": "some-value"}]}`)
+			}))
+			s.TLS = &tls.Config{
+				Certificates: []tls.Certificate{certificate},
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				ClientCAs:    clientCAs,
+			}
+			s.StartTLS()
+
+			clientCertPath, clientKeyPath, err = ca.WriteClientCertificate(tempDir, "some-app-guid")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			s.Close()
+		})
+
+		It("authenticates with the client certificate instead of an access token", func() {
+			httpClient, err := credhub.NewHTTPClient([]string{ca.CertificatePEM}, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			client, err := credhub.NewClientWithCertificate(strings.TrimPrefix(s.URL, "https://"), clientCertPath, clientKeyPath, httpClient)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.Authenticate(context.Background())).To(Succeed())
+			credential, err := client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credential.Value).To(Equal(credhub.Value("some-value")))
+
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].URL.Path).To(Equal("/api/v1/data"))
+			Expect(requests[0].Header.Get("Authorization")).To(BeEmpty())
+			Expect(requests[0].TLS.PeerCertificates[0].Subject.CommonName).To(Equal("some-app-guid"))
+		})
+
+		It("accepts the certificate and key as PEM", func() {
+			certificatePEM, privateKeyPEM, err := ca.IssueClientCertificate("some-app-guid")
+			Expect(err).NotTo(HaveOccurred())
+
+			httpClient, err := credhub.NewHTTPClient([]string{ca.CertificatePEM}, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			client, err := credhub.NewClientWithCertificate(strings.TrimPrefix(s.URL, "https://"), certificatePEM, privateKeyPEM, httpClient)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("does not present the certificate through the HTTP client it was given", func() {
+			httpClient, err := credhub.NewHTTPClient([]string{ca.CertificatePEM}, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = credhub.NewClientWithCertificate(strings.TrimPrefix(s.URL, "https://"), clientCertPath, clientKeyPath, httpClient)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = httpClient.Get(s.URL)
+			Expect(err).To(HaveOccurred())
+		})
+
+		Context("when the client certificate does not exist", func() {
+			It("returns an error", func() {
+				_, err := credhub.NewClientWithCertificate("some-addr", filepath.Join(tempDir, "missing.pem"), clientKeyPath, http.DefaultClient)
+				Expect(err).To(MatchError(ContainSubstring("failed to read client certificate")))
+			})
+		})
+
+		Context("when the key does not match the certificate", func() {
+			It("returns an error", func() {
+				_, otherKeyPEM, err := ca.IssueClientCertificate("some-other-app-guid")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = credhub.NewClientWithCertificate("some-addr", clientCertPath, otherKeyPEM, http.DefaultClient)
+				Expect(err).To(MatchError(ContainSubstring("failed to load client certificate")))
+			})
+		})
+	})
+
+	Describe("verification errors", func() {
+		It("explains certificates signed by an unknown authority", func() {
+			s := startServer("127.0.0.1")
+			defer s.Close()
+
+			httpClient, err := credhub.NewHTTPClient(nil, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			addr := strings.TrimPrefix(s.URL, "https://")
+			client := credhub.NewClient(addr, credhub.NewClientCredentialsAuthenticator("some-client-id", "some-client-secret"), httpClient, nil)
+			_, err = client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).To(MatchError(ContainSubstring(
+				"the certificate presented by " + addr + " is signed by an unknown authority: " +
+					"provide its CA certificate with '--ca-cert' or CREDHUB_CA_CERT, or use '--skip-tls-validation'",
+			)))
+		})
+
+		It("explains certificates which do not match the address", func() {
+			s := startServer("some-other-host")
+			defer s.Close()
+
+			httpClient, err := credhub.NewHTTPClient([]string{ca.CertificatePEM}, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			addr := strings.TrimPrefix(s.URL, "https://")
+			client := credhub.NewClient(addr, credhub.NewClientCredentialsAuthenticator("some-client-id", "some-client-secret"), httpClient, nil)
+			_, err = client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).To(MatchError(ContainSubstring(
+				"the certificate presented by " + addr + " does not match its address",
+			)))
+		})
+	})
+})
@@ -0,0 +1,292 @@
+package credhub
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// tokenRefreshWindow is how long before its expiry a cached token is replaced,
+// so that a token does not expire while a request using it is in flight.
+const tokenRefreshWindow = 30 * time.Second
+
+// Token is a UAA access token and the time at which it expires.
+type Token struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// usable reports whether the token can still be sent with a request.
+func (t Token) usable() bool {
+	return t.AccessToken != "" && time.Now().Add(tokenRefreshWindow).Before(t.ExpiresAt)
+}
+
+// TokenStore persists access tokens beyond the lifetime of a client, for
+// example across separate cfs invocations. Tokens are keyed by CredHub address
+// and the Identity of the Authenticator that obtained them.
+type TokenStore interface {
+	GetToken(credhubAddr, identity string) (Token, error)
+	SetToken(credhubAddr, identity string, token Token) error
+}
+
+// Authenticate obtains an access token unless a usable one is already cached,
+// so that bad credentials are reported before any other request is made. A
+// client certificate is only checked when a request is made, so clients using
+// one have nothing to do.
+func (c *client) Authenticate(ctx context.Context) error {
+	if c.mutualTLS {
+		return nil
+	}
+	_, err := c.getToken(ctx)
+	return err
+}
+
+// authorize adds an access token to a request, unless the client authenticates
+// with a client certificate instead.
+func (c *client) authorize(req *http.Request) error {
+	if c.mutualTLS {
+		return nil
+	}
+
+	authToken, err := c.getToken(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Authorization", "Bearer "+authToken)
+	return nil
+}
+
+// getToken returns the cached access token, obtaining a new one from the
+// authenticator when there is none or it is about to expire. Concurrent callers
+// wait for a single fetch and share its token. Failing to read or write the
+// token store only costs a token request, so its errors are ignored.
+func (c *client) getToken(ctx context.Context) (string, error) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+
+	if c.token.usable() {
+		return c.token.AccessToken, nil
+	}
+
+	identity := c.authenticator.Identity()
+	storeTokens := c.tokenStore != nil && identity != ""
+
+	if storeTokens {
+		stored, err := c.tokenStore.GetToken(c.credhubAddr, identity)
+		if err == nil && stored.usable() && stored.AccessToken != c.rejectedToken {
+			c.token = stored
+			return c.token.AccessToken, nil
+		}
+	}
+
+	token, err := c.authenticator.Token(func(form url.Values) (Token, string, error) {
+		return c.requestToken(ctx, form)
+	})
+	if err != nil {
+		return "", err
+	}
+	if token.AccessToken == c.rejectedToken {
+		return "", errors.New("access token was rejected by CredHub")
+	}
+	c.token = token
+
+	if storeTokens {
+		c.tokenStore.SetToken(c.credhubAddr, identity, c.token)
+	}
+
+	return c.token.AccessToken, nil
+}
+
+// requestToken is the TokenRequester given to the authenticator.
+func (c *client) requestToken(ctx context.Context, form url.Values) (Token, string, error) {
+	uaaURL, err := c.cachedUAAURL(ctx)
+	if err != nil {
+		return Token{}, "", fmt.Errorf("failed to get UAA URL: %s", err.Error())
+	}
+
+	tokenURL := fmt.Sprintf("%s/oauth/token", uaaURL)
+	requestedAt := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, "", fmt.Errorf("failed to create request: %s", err.Error())
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.send(req)
+	if err != nil {
+		return Token{}, "", fmt.Errorf("failed to make request: %s", describeTLSError(err).Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Token{}, "", fmt.Errorf("got %s", resp.Status)
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Token{}, "", fmt.Errorf("failed to read body: %s", err.Error())
+	}
+
+	var tokenResponse struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return Token{}, "", fmt.Errorf("failed to parse response body: %s\n%s", err.Error(), string(body))
+	}
+
+	token := Token{
+		AccessToken: tokenResponse.AccessToken,
+		ExpiresAt:   tokenExpiry(tokenResponse.AccessToken),
+	}
+	if token.ExpiresAt.IsZero() && tokenResponse.ExpiresIn > 0 {
+		token.ExpiresAt = requestedAt.Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
+	}
+
+	return token, tokenResponse.RefreshToken, nil
+}
+
+// invalidateToken discards the cached token if it is still the given token,
+// so that a token rejected by CredHub is only refreshed once no matter how
+// many requests were using it. The token is also remembered so that it is not
+// picked up from the token store again.
+func (c *client) invalidateToken(token string) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+
+	if c.token.AccessToken == token {
+		c.token = Token{}
+	}
+	c.rejectedToken = token
+}
+
+// tokenExpiry returns the time in the 'exp' claim of a JWT access token, or the
+// zero time if the token is not a JWT or has no expiry.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(claims.Exp, 0)
+}
+
+// do sends a request carrying a token from getToken. If CredHub rejects the
+// token as invalid, for example because it was revoked before it expired, the
+// token is refreshed and the request is retried once.
+func (c *client) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, describeTLSError(err)
+	} else if c.mutualTLS || resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %s", err.Error())
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	var errorResponse struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errorResponse); err != nil || errorResponse.Error != "invalid_token" {
+		return resp, nil
+	}
+
+	c.invalidateToken(strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "))
+	authToken, err := c.getToken(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %s", err.Error())
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %s", err.Error())
+		}
+	}
+	retry.Header.Set("Authorization", "Bearer "+authToken)
+
+	resp, err = c.send(retry)
+	if err != nil {
+		return nil, describeTLSError(err)
+	}
+	return resp, nil
+}
+
+// cachedUAAURL looks up the UAA URL the first time a token is requested, so
+// authenticators which do not need UAA never cause it to be contacted. It has
+// its own lock so that it is safe for concurrent requests, such as those made
+// by `cfs grep`, whether or not the caller holds tokenMutex.
+func (c *client) cachedUAAURL(ctx context.Context) (string, error) {
+	c.uaaURLMutex.Lock()
+	defer c.uaaURLMutex.Unlock()
+
+	if c.uaaURL == "" {
+		uaaURL, err := c.getUAAURL(ctx)
+		if err != nil {
+			return "", err
+		}
+		c.uaaURL = uaaURL
+	}
+	return c.uaaURL, nil
+}
+
+func (c *client) getUAAURL(ctx context.Context) (string, error) {
+	infoURL := fmt.Sprintf("https://%s/info", c.credhubAddr)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, infoURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %s", err.Error())
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %s", describeTLSError(err).Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("got %s", resp.Status)
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read body: %s", err.Error())
+	}
+
+	var infoResponse struct {
+		AuthServer struct {
+			URL string `json:"url"`
+		} `json:"auth-server"`
+	}
+	if err := json.Unmarshal(body, &infoResponse); err != nil {
+		return "", fmt.Errorf("failed to parse response body: %s\n%s", err.Error(), string(body))
+	}
+
+	return infoResponse.AuthServer.URL, nil
+}
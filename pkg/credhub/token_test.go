@@ -0,0 +1,322 @@
+package credhub_test
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/test/helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+type memoryTokenStore struct {
+	tokens map[string]credhub.Token
+}
+
+func (s *memoryTokenStore) GetToken(credhubAddr, identity string) (credhub.Token, error) {
+	return s.tokens[credhubAddr+" "+identity], nil
+}
+
+func (s *memoryTokenStore) SetToken(credhubAddr, identity string, token credhub.Token) error {
+	s.tokens[credhubAddr+" "+identity] = token
+	return nil
+}
+
+var _ = Describe("Token caching", func() {
+	var (
+		credhubServer           *ghttp.Server
+		uaaServer               *ghttp.Server
+		skipTLSVerifyHttpClient = &http.Client{
+			Timeout: 5 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+				Dial:                (&net.Dialer{Timeout: 5 * time.Second}).Dial,
+				TLSHandshakeTimeout: 5 * time.Second,
+			},
+		}
+		client       credhub.Client
+		credhubURL   string
+		clientID     string
+		clientSecret string
+	)
+
+	jwtExpiringAt := func(exp time.Time) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"jti": helpers.RandomString(),
+			"exp": exp.Unix(),
+		}).SignedString([]byte("some-key"))
+		Expect(err).NotTo(HaveOccurred())
+		return token
+	}
+
+	respondWithToken := func(token string) http.HandlerFunc {
+		return ghttp.CombineHandlers(
+			ghttp.VerifyRequest("POST", "/oauth/token"),
+			ghttp.VerifyFormKV("client_id", clientID),
+			ghttp.VerifyFormKV("client_secret", clientSecret),
+			ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"access_token": "%s"}`, token)),
+		)
+	}
+
+	respondWithCredential := func(token string) http.HandlerFunc {
+		return ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", "/api/v1/data", "name=%2Fsome-name&current=true"),
+			ghttp.VerifyHeaderKV("Authorization", "Bearer "+token),
+			ghttp.RespondWith(http.StatusOK, `{"data": [{"name": "/some-name", "type": "value", "value": "some-value"}]}`),
+		)
+	}
+
+	BeforeEach(func() {
+		credhubServer = ghttp.NewTLSServer()
+		uaaServer = ghttp.NewTLSServer()
+		clientID = helpers.RandomString()
+		clientSecret = helpers.RandomString()
+
+		credhubServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/info"),
+				ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"auth-server": {"url": "%s"}}`, uaaServer.URL())),
+			),
+		)
+
+		credhubURL = strings.TrimPrefix(credhubServer.URL(), "https://")
+		client = credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, nil)
+	})
+
+	AfterEach(func() {
+		credhubServer.Close()
+		uaaServer.Close()
+	})
+
+	It("reuses the token until it is about to expire", func() {
+		token := jwtExpiringAt(time.Now().Add(time.Hour))
+		uaaServer.AppendHandlers(respondWithToken(token))
+		credhubServer.AppendHandlers(respondWithCredential(token), respondWithCredential(token))
+
+		_, err := client.GetCredentialByName(context.Background(), "/some-name")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.GetCredentialByName(context.Background(), "/some-name")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(uaaServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	Describe("Authenticate", func() {
+		It("fetches a token which later requests use", func() {
+			token := jwtExpiringAt(time.Now().Add(time.Hour))
+			uaaServer.AppendHandlers(respondWithToken(token))
+			credhubServer.AppendHandlers(respondWithCredential(token))
+
+			Expect(client.Authenticate(context.Background())).To(Succeed())
+			Expect(uaaServer.ReceivedRequests()).To(HaveLen(1))
+
+			_, err := client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(uaaServer.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("returns an error when the credentials are rejected", func() {
+			uaaServer.AppendHandlers(ghttp.RespondWith(http.StatusUnauthorized, `{"error": "unauthorized"}`))
+
+			Expect(client.Authenticate(context.Background())).To(MatchError("got 401 Unauthorized"))
+		})
+	})
+
+	It("refreshes the token shortly before it expires", func() {
+		expiringToken := jwtExpiringAt(time.Now().Add(10 * time.Second))
+		newToken := jwtExpiringAt(time.Now().Add(time.Hour))
+		uaaServer.AppendHandlers(respondWithToken(expiringToken), respondWithToken(newToken))
+		credhubServer.AppendHandlers(respondWithCredential(expiringToken), respondWithCredential(newToken))
+
+		_, err := client.GetCredentialByName(context.Background(), "/some-name")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.GetCredentialByName(context.Background(), "/some-name")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(uaaServer.ReceivedRequests()).To(HaveLen(2))
+		Expect(credhubServer.ReceivedRequests()).To(HaveLen(3))
+	})
+
+	Context("when the token is not a JWT", func() {
+		It("uses 'expires_in' from the token response", func() {
+			token := helpers.RandomString()
+			uaaServer.AppendHandlers(
+				ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"access_token": "%s", "expires_in": 3600}`, token)),
+			)
+			credhubServer.AppendHandlers(respondWithCredential(token), respondWithCredential(token))
+
+			_, err := client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(uaaServer.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	Context("when CredHub rejects the token as invalid", func() {
+		It("refreshes the token and retries the request once", func() {
+			rejectedToken := jwtExpiringAt(time.Now().Add(time.Hour))
+			newToken := jwtExpiringAt(time.Now().Add(time.Hour))
+			uaaServer.AppendHandlers(respondWithToken(rejectedToken), respondWithToken(newToken))
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyHeaderKV("Authorization", "Bearer "+rejectedToken),
+					ghttp.RespondWith(http.StatusUnauthorized, `{"error": "invalid_token"}`),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/api/v1/data"),
+					ghttp.VerifyHeaderKV("Authorization", "Bearer "+newToken),
+					ghttp.VerifyJSON(`{"name": "/some-name", "type": "value", "value": "some-value"}`),
+					ghttp.RespondWith(http.StatusOK, `{"name": "/some-name", "type": "value", "value": "some-value"}`),
+				),
+			)
+
+			_, err := client.SetCredential(context.Background(), credhub.Credential{
+				Name:  "/some-name",
+				Type:  "value",
+				Value: credhub.Value("some-value"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(uaaServer.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("returns an error if the refreshed token is rejected too", func() {
+			uaaServer.AppendHandlers(
+				respondWithToken(jwtExpiringAt(time.Now().Add(time.Hour))),
+				respondWithToken(jwtExpiringAt(time.Now().Add(time.Hour))),
+			)
+			credhubServer.AppendHandlers(
+				ghttp.RespondWith(http.StatusUnauthorized, `{"error": "invalid_token"}`),
+				ghttp.RespondWith(http.StatusUnauthorized, `{"error": "invalid_token"}`),
+			)
+
+			_, err := client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).To(MatchError("got 401 Unauthorized"))
+			Expect(credhubServer.ReceivedRequests()).To(HaveLen(3))
+		})
+	})
+
+	Context("when CredHub returns a 401 for another reason", func() {
+		It("does not retry the request", func() {
+			uaaServer.AppendHandlers(respondWithToken(jwtExpiringAt(time.Now().Add(time.Hour))))
+			credhubServer.AppendHandlers(
+				ghttp.RespondWith(http.StatusUnauthorized, `{"error": "some-error"}`),
+			)
+
+			_, err := client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).To(MatchError("got 401 Unauthorized"))
+			Expect(uaaServer.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	It("shares one token between concurrent requests", func() {
+		token := jwtExpiringAt(time.Now().Add(time.Hour))
+		uaaServer.RouteToHandler("POST", "/oauth/token", respondWithToken(token))
+		credhubServer.RouteToHandler("GET", "/api/v1/data", respondWithCredential(token))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, err := client.GetCredentialByName(context.Background(), "/some-name")
+				Expect(err).NotTo(HaveOccurred())
+			}()
+		}
+		wg.Wait()
+
+		Expect(uaaServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	Context("when a token store is provided", func() {
+		var tokenStore *memoryTokenStore
+
+		BeforeEach(func() {
+			tokenStore = &memoryTokenStore{tokens: map[string]credhub.Token{}}
+			client = credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, tokenStore)
+		})
+
+		It("stores new tokens for other clients to use", func() {
+			expiresAt := time.Now().Add(time.Hour).Round(time.Second)
+			token := jwtExpiringAt(expiresAt)
+			uaaServer.AppendHandlers(respondWithToken(token))
+			credhubServer.AppendHandlers(respondWithCredential(token), respondWithCredential(token))
+
+			_, err := client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tokenStore.GetToken(credhubURL, clientID)).To(Equal(credhub.Token{
+				AccessToken: token,
+				ExpiresAt:   expiresAt,
+			}))
+
+			otherClient := credhub.NewClient(credhubURL, credhub.NewClientCredentialsAuthenticator(clientID, clientSecret), skipTLSVerifyHttpClient, tokenStore)
+			_, err = otherClient.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(uaaServer.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("uses a stored token without contacting UAA", func() {
+			token := jwtExpiringAt(time.Now().Add(time.Hour))
+			tokenStore.SetToken(credhubURL, clientID, credhub.Token{AccessToken: token, ExpiresAt: time.Now().Add(time.Hour)})
+			credhubServer.SetHandler(0, respondWithCredential(token))
+
+			_, err := client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(uaaServer.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("ignores stored tokens which are about to expire", func() {
+			tokenStore.SetToken(credhubURL, clientID, credhub.Token{
+				AccessToken: jwtExpiringAt(time.Now().Add(10 * time.Second)),
+				ExpiresAt:   time.Now().Add(10 * time.Second),
+			})
+			token := jwtExpiringAt(time.Now().Add(time.Hour))
+			uaaServer.AppendHandlers(respondWithToken(token))
+			credhubServer.AppendHandlers(respondWithCredential(token))
+
+			_, err := client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(uaaServer.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("does not reuse a stored token which CredHub rejected", func() {
+			rejectedToken := jwtExpiringAt(time.Now().Add(time.Hour))
+			tokenStore.SetToken(credhubURL, clientID, credhub.Token{AccessToken: rejectedToken, ExpiresAt: time.Now().Add(time.Hour)})
+			newToken := jwtExpiringAt(time.Now().Add(time.Hour))
+			credhubServer.SetHandler(0, ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("Authorization", "Bearer "+rejectedToken),
+				ghttp.RespondWith(http.StatusUnauthorized, `{"error": "invalid_token"}`),
+			))
+			credhubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/info"),
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"auth-server": {"url": "%s"}}`, uaaServer.URL())),
+				),
+				respondWithCredential(newToken),
+			)
+			uaaServer.AppendHandlers(respondWithToken(newToken))
+
+			_, err := client.GetCredentialByName(context.Background(), "/some-name")
+			Expect(err).NotTo(HaveOccurred())
+
+			stored, err := tokenStore.GetToken(credhubURL, clientID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.AccessToken).To(Equal(newToken))
+		})
+	})
+})
@@ -1,17 +1,113 @@
 package credhub
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	TypeValue       = "value"
+	TypeJSON        = "json"
+	TypePassword    = "password"
+	TypeUser        = "user"
+	TypeCertificate = "certificate"
+	TypeRSA         = "rsa"
+	TypeSSH         = "ssh"
+)
+
 type Credential struct {
-	ID               uuid.UUID `json:"id"`
-	Name             string    `json:"name"`
-	Type             string    `json:"type"`
-	Value            string    `json:"value"`
-	VersionCreatedAt time.Time `json:"version_created_at"`
+	ID               uuid.UUID              `json:"id"`
+	Name             string                 `json:"name"`
+	Type             string                 `json:"type"`
+	Value            CredentialValue        `json:"value"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+	VersionCreatedAt time.Time              `json:"version_created_at"`
+}
+
+// CredentialValue is implemented by each of the CredHub credential types.
+type CredentialValue interface {
+	Type() string
+}
+
+type Value string
+
+type JSON map[string]interface{}
+
+type Password string
+
+type User struct {
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	PasswordHash string `json:"password_hash,omitempty"`
+}
+
+type Certificate struct {
+	CA          string `json:"ca,omitempty"`
+	CAName      string `json:"ca_name,omitempty"`
+	Certificate string `json:"certificate"`
+	PrivateKey  string `json:"private_key,omitempty"`
+}
+
+type RSA struct {
+	PublicKey  string `json:"public_key"`
+	PrivateKey string `json:"private_key"`
+}
+
+type SSH struct {
+	PublicKey            string `json:"public_key"`
+	PrivateKey           string `json:"private_key"`
+	PublicKeyFingerprint string `json:"public_key_fingerprint,omitempty"`
+}
+
+var credentialValueTypes = map[string]reflect.Type{
+	TypeValue:       reflect.TypeOf(Value("")),
+	TypeJSON:        reflect.TypeOf(JSON{}),
+	TypePassword:    reflect.TypeOf(Password("")),
+	TypeUser:        reflect.TypeOf(User{}),
+	TypeCertificate: reflect.TypeOf(Certificate{}),
+	TypeRSA:         reflect.TypeOf(RSA{}),
+	TypeSSH:         reflect.TypeOf(SSH{}),
+}
+
+func (Value) Type() string       { return TypeValue }
+func (JSON) Type() string        { return TypeJSON }
+func (Password) Type() string    { return TypePassword }
+func (User) Type() string        { return TypeUser }
+func (Certificate) Type() string { return TypeCertificate }
+func (RSA) Type() string         { return TypeRSA }
+func (SSH) Type() string         { return TypeSSH }
+
+func (c *Credential) UnmarshalJSON(data []byte) error {
+	type credential Credential
+	var raw struct {
+		credential
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*c = Credential(raw.credential)
+	if len(raw.Value) == 0 || string(raw.Value) == "null" {
+		return nil
+	}
+
+	valueType, ok := credentialValueTypes[c.Type]
+	if !ok {
+		return fmt.Errorf("unsupported credential type '%s'", c.Type)
+	}
+
+	value := reflect.New(valueType)
+	if err := json.Unmarshal(raw.Value, value.Interface()); err != nil {
+		return fmt.Errorf("failed to parse %s value: %s", c.Type, err.Error())
+	}
+	c.Value = value.Elem().Interface().(CredentialValue)
+
+	return nil
 }
 
 type ErrCredentialNotFound struct {
@@ -0,0 +1,13 @@
+package credhubdav_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCredhubdav(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Credhubdav Suite")
+}
@@ -0,0 +1,309 @@
+package credhubdav
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/pkg/credhubfs"
+	"github.com/mdelillo/credhub-fs/pkg/transfer"
+	"golang.org/x/net/webdav"
+)
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+// FileSystem is a webdav.FileSystem backed by CredHub. Reads go through
+// credhubfs, so the tree looks the same as it does to `cfs ls` and `cfs cat`.
+// Unless writable, every operation that would change CredHub fails with
+// os.ErrPermission.
+type FileSystem struct {
+	credhubClient credhubClient
+	credhubFS     *credhubfs.FS
+	writable      bool
+}
+
+var _ webdav.FileSystem = &FileSystem{}
+
+func NewFileSystem(credhubClient credhub.Client, writable bool) *FileSystem {
+	return &FileSystem{
+		credhubClient: credhubClient,
+		credhubFS:     credhubfs.New(credhubClient),
+		writable:      writable,
+	}
+}
+
+// NewHandler serves a FileSystem over WebDAV to clients which authenticate
+// with HTTP basic auth as username and password. A read-only handler rejects
+// methods that modify resources before they reach the FileSystem.
+func NewHandler(credhubClient credhub.Client, writable bool, username, password string) http.Handler {
+	handler := &webdav.Handler{
+		FileSystem: NewFileSystem(credhubClient, writable),
+		LockSystem: webdav.NewMemLS(),
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r, username, password) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="cfs"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, "PROPFIND":
+		default:
+			if !writable {
+				http.Error(w, "read-only WebDAV server", http.StatusForbidden)
+				return
+			}
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// authorized compares credentials in constant time so that they cannot be
+// guessed a byte at a time. An empty password never matches.
+func authorized(r *http.Request, username, password string) bool {
+	requestUsername, requestPassword, ok := r.BasicAuth()
+	if !ok || password == "" {
+		return false
+	}
+	usernameMatches := subtle.ConstantTimeCompare([]byte(requestUsername), []byte(username)) == 1
+	passwordMatches := subtle.ConstantTimeCompare([]byte(requestPassword), []byte(password)) == 1
+	return usernameMatches && passwordMatches
+}
+
+func (f *FileSystem) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	if !f.writable {
+		return os.ErrPermission
+	}
+	if _, err := f.Stat(ctx, name); err == nil {
+		return os.ErrExist
+	}
+	return nil
+}
+
+func (f *FileSystem) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) == 0 {
+		file, err := f.credhubFS.WithContext(ctx).Open(fsName(name))
+		if err != nil {
+			return nil, err
+		}
+		return &readFile{File: file}, nil
+	}
+
+	if !f.writable {
+		return nil, os.ErrPermission
+	}
+
+	file := &writeFile{ctx: ctx, fs: f, name: credhubName(name), credentialType: credhub.TypeValue}
+	credential, err := f.credhubClient.GetCredentialByName(ctx, file.name)
+	if err == nil {
+		if flag&os.O_EXCL != 0 {
+			return nil, os.ErrExist
+		}
+		file.credentialType = credential.Type
+		if flag&os.O_TRUNC == 0 {
+			contents, err := credhub.FormatValue(credential.Value)
+			if err != nil {
+				return nil, err
+			}
+			file.data.WriteString(contents + "\n")
+		}
+	} else if _, isNotFoundError := err.(*credhub.ErrCredentialNotFound); !isNotFoundError {
+		return nil, err
+	} else if flag&os.O_CREATE == 0 {
+		return nil, os.ErrNotExist
+	}
+	return file, nil
+}
+
+func (f *FileSystem) RemoveAll(ctx context.Context, name string) error {
+	if !f.writable {
+		return os.ErrPermission
+	}
+
+	names, err := f.credentialNames(ctx, name)
+	if err != nil {
+		return err
+	}
+	for _, credentialName := range names {
+		if err := f.credhubClient.DeleteCredentialByName(ctx, credentialName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rename moves credentials the same way as `cfs mv`, writing and verifying
+// every destination before removing any source, so that a MOVE which fails
+// partway does not leave credentials at both names.
+func (f *FileSystem) Rename(ctx context.Context, oldName, newName string) error {
+	if !f.writable {
+		return os.ErrPermission
+	}
+
+	names, err := f.credentialNames(ctx, oldName)
+	if err != nil {
+		return err
+	}
+	var transfers []transfer.Transfer
+	for _, credentialName := range names {
+		transfers = append(transfers, transfer.Transfer{
+			Source:      credhub.Credential{Name: credentialName},
+			Destination: credhubName(newName) + strings.TrimPrefix(credentialName, credhubName(oldName)),
+		})
+	}
+	return transfer.Move(ctx, f.credhubClient, transfers)
+}
+
+func (f *FileSystem) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	return f.credhubFS.WithContext(ctx).Stat(fsName(name))
+}
+
+// credentialNames returns the credential called name, or every credential
+// under name if it is a path.
+func (f *FileSystem) credentialNames(ctx context.Context, name string) ([]string, error) {
+	if credhubName(name) == "/" {
+		return nil, os.ErrPermission
+	}
+
+	credentials, err := f.credhubClient.FindCredentialsByPath(ctx, credhubName(name))
+	if err != nil {
+		return nil, err
+	}
+	if len(credentials) == 0 {
+		if _, err := f.credhubClient.GetCredentialByName(ctx, credhubName(name)); err != nil {
+			if _, isNotFoundError := err.(*credhub.ErrCredentialNotFound); isNotFoundError {
+				return nil, os.ErrNotExist
+			}
+			return nil, err
+		}
+		return []string{credhubName(name)}, nil
+	}
+
+	var names []string
+	for _, credential := range credentials {
+		names = append(names, credential.Name)
+	}
+	return names, nil
+}
+
+func credhubName(name string) string {
+	return path.Clean("/" + name)
+}
+
+func fsName(name string) string {
+	if name = strings.TrimPrefix(credhubName(name), "/"); name == "" {
+		return "."
+	}
+	return name
+}
+
+type readFile struct {
+	fs.File
+}
+
+func (f *readFile) Seek(offset int64, whence int) (int64, error) {
+	seeker, ok := f.File.(io.Seeker)
+	if !ok {
+		return 0, errors.New("is a directory")
+	}
+	return seeker.Seek(offset, whence)
+}
+
+func (f *readFile) Readdir(count int) ([]os.FileInfo, error) {
+	dir, ok := f.File.(fs.ReadDirFile)
+	if !ok {
+		return nil, errors.New("not a directory")
+	}
+
+	entries, err := dir.ReadDir(count)
+	var infos []os.FileInfo
+	for _, entry := range entries {
+		info, infoErr := entry.Info()
+		if infoErr != nil {
+			return infos, infoErr
+		}
+		infos = append(infos, info)
+	}
+	return infos, err
+}
+
+func (f *readFile) Write([]byte) (int, error) {
+	return 0, os.ErrPermission
+}
+
+// writeFile buffers a credential value and sets it in CredHub on Close,
+// keeping the type of the credential it replaces.
+type writeFile struct {
+	ctx            context.Context
+	fs             *FileSystem
+	name           string
+	credentialType string
+	data           bytes.Buffer
+	written        bool
+	modTime        time.Time
+}
+
+func (f *writeFile) Write(p []byte) (int, error) {
+	f.written = true
+	f.modTime = time.Now()
+	return f.data.Write(p)
+}
+
+func (f *writeFile) Close() error {
+	if !f.written {
+		return nil
+	}
+
+	text := strings.TrimSuffix(strings.TrimSuffix(f.data.String(), "\n"), "\r")
+	if text == "" {
+		return errors.New("must provide a non-empty value")
+	}
+
+	value, err := credhub.ParseValue(f.credentialType, text)
+	if err != nil {
+		return err
+	}
+	_, err = f.fs.credhubClient.SetCredential(f.ctx, credhub.Credential{Name: f.name, Value: value})
+	return err
+}
+
+func (f *writeFile) Read([]byte) (int, error) {
+	return 0, os.ErrPermission
+}
+
+func (f *writeFile) Seek(offset int64, whence int) (int64, error) {
+	return 0, os.ErrPermission
+}
+
+func (f *writeFile) Readdir(count int) ([]os.FileInfo, error) {
+	return nil, errors.New("not a directory")
+}
+
+func (f *writeFile) Stat() (os.FileInfo, error) {
+	return &fileInfo{name: path.Base(f.name), size: int64(f.data.Len()), modTime: f.modTime}, nil
+}
+
+type fileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+func (i *fileInfo) Name() string       { return i.name }
+func (i *fileInfo) Size() int64        { return i.size }
+func (i *fileInfo) Mode() os.FileMode  { return 0600 }
+func (i *fileInfo) ModTime() time.Time { return i.modTime }
+func (i *fileInfo) IsDir() bool        { return false }
+func (i *fileInfo) Sys() interface{}   { return nil }
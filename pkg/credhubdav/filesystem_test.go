@@ -0,0 +1,320 @@
+package credhubdav_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/pkg/credhubdav"
+	"github.com/mdelillo/credhub-fs/pkg/credhubdav/credhubdavfakes"
+	"github.com/mdelillo/credhub-fs/test/helpers"
+	"github.com/mdelillo/credhub-fs/test/server"
+)
+
+var _ = Describe("Handler", func() {
+	var (
+		fakeCredhubClient *credhubdavfakes.FakeCredhubClient
+		listenAddr        string
+		davServer         server.Server
+		startServer       func(writable bool)
+		request           func(method, path, body string, headers ...string) (int, string)
+	)
+
+	BeforeEach(func() {
+		fakeCredhubClient = &credhubdavfakes.FakeCredhubClient{}
+		listenAddr = helpers.GetFreeAddr()
+
+		startServer = func(writable bool) {
+			davServer = server.NewServer(
+				listenAddr,
+				filepath.Join("..", "..", "test", "fixtures", "127.0.0.1-cert.pem"),
+				filepath.Join("..", "..", "test", "fixtures", "127.0.0.1-key.pem"),
+				credhubdav.NewHandler(fakeCredhubClient, writable, "some-username", "some-password"),
+			)
+			go davServer.Start()
+			Expect(helpers.WaitForServerToBeAvailable(listenAddr, 5*time.Second)).To(Succeed())
+		}
+
+		request = func(method, path, body string, headers ...string) (int, string) {
+			req, err := http.NewRequest(method, "https://"+listenAddr+path, strings.NewReader(body))
+			ExpectWithOffset(1, err).NotTo(HaveOccurred())
+			req.SetBasicAuth("some-username", "some-password")
+			for i := 0; i+1 < len(headers); i += 2 {
+				req.Header.Set(headers[i], headers[i+1])
+			}
+
+			resp, err := helpers.HTTPClient.Do(req)
+			ExpectWithOffset(1, err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+
+			respBody, err := ioutil.ReadAll(resp.Body)
+			ExpectWithOffset(1, err).NotTo(HaveOccurred())
+			return resp.StatusCode, string(respBody)
+		}
+	})
+
+	AfterEach(func() {
+		Expect(davServer.Shutdown()).To(Succeed())
+	})
+
+	Context("when the client does not authenticate", func() {
+		BeforeEach(func() {
+			startServer(true)
+		})
+
+		It("rejects requests without credentials", func() {
+			for _, method := range []string{"GET", "PROPFIND", "PUT", "DELETE", "MOVE"} {
+				req, err := http.NewRequest(method, "https://"+listenAddr+"/some-cred", strings.NewReader("some-value"))
+				Expect(err).NotTo(HaveOccurred())
+
+				resp, err := helpers.HTTPClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized), method)
+				Expect(resp.Header.Get("WWW-Authenticate")).To(Equal(`Basic realm="cfs"`))
+			}
+
+			Expect(fakeCredhubClient.GetCredentialByNameCallCount()).To(Equal(0))
+			Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(0))
+			Expect(fakeCredhubClient.DeleteCredentialByNameCallCount()).To(Equal(0))
+		})
+
+		It("rejects requests with the wrong credentials", func() {
+			for _, credentials := range [][]string{
+				{"some-username", "wrong-password"},
+				{"wrong-username", "some-password"},
+				{"some-username", ""},
+			} {
+				req, err := http.NewRequest("GET", "https://"+listenAddr+"/some-cred", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.SetBasicAuth(credentials[0], credentials[1])
+
+				resp, err := helpers.HTTPClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized), credentials[0]+":"+credentials[1])
+			}
+
+			Expect(fakeCredhubClient.GetCredentialByNameCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when read-only", func() {
+		BeforeEach(func() {
+			startServer(false)
+		})
+
+		It("serves credential values", func() {
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{
+				Name:  "/some-dir/some-cred",
+				Type:  "value",
+				Value: credhub.Value("some-value"),
+			}, nil)
+
+			status, body := request("GET", "/some-dir/some-cred", "")
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).To(Equal("some-value\n"))
+
+			_, requestedName := fakeCredhubClient.GetCredentialByNameArgsForCall(0)
+			Expect(requestedName).To(Equal("/some-dir/some-cred"))
+		})
+
+		It("lists paths", func() {
+			fakeCredhubClient.FindCredentialsByPathStub = func(_ context.Context, path string) ([]credhub.Credential, error) {
+				switch path {
+				case "/some-dir":
+					return []credhub.Credential{
+						{Name: "/some-dir/some-cred"},
+						{Name: "/some-dir/nested/other-cred"},
+					}, nil
+				case "/some-dir/nested":
+					return []credhub.Credential{{Name: "/some-dir/nested/other-cred"}}, nil
+				default:
+					return nil, nil
+				}
+			}
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{Type: "value", Value: credhub.Value("some-value")}, nil)
+
+			status, body := request("PROPFIND", "/some-dir/", "", "Depth", "1")
+			Expect(status).To(Equal(http.StatusMultiStatus))
+			Expect(body).To(ContainSubstring("<D:href>/some-dir/some-cred</D:href>"))
+			Expect(body).To(ContainSubstring("<D:href>/some-dir/nested/</D:href>"))
+		})
+
+		It("responds with a 404 for missing credentials", func() {
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{}, &credhub.ErrCredentialNotFound{})
+
+			status, _ := request("GET", "/missing", "")
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+
+		It("rejects modifications", func() {
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{Name: "/some-cred", Type: "value", Value: credhub.Value("some-value")}, nil)
+
+			for _, method := range []string{"PUT", "DELETE", "MKCOL", "MOVE", "COPY", "PROPPATCH", "LOCK"} {
+				status, _ := request(method, "/some-cred", "some-value", "Destination", "https://"+listenAddr+"/other-cred")
+				Expect(status).To(Equal(http.StatusForbidden), method)
+			}
+
+			Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(0))
+			Expect(fakeCredhubClient.DeleteCredentialByNameCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when writable", func() {
+		BeforeEach(func() {
+			startServer(true)
+		})
+
+		It("sets new credentials as values", func() {
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{}, &credhub.ErrCredentialNotFound{})
+
+			status, _ := request("PUT", "/some-dir/some-cred", "some-value\n")
+			Expect(status).To(Equal(http.StatusCreated))
+
+			Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(1))
+			_, written := fakeCredhubClient.SetCredentialArgsForCall(0)
+			Expect(written).To(Equal(credhub.Credential{
+				Name:  "/some-dir/some-cred",
+				Value: credhub.Value("some-value"),
+			}))
+		})
+
+		It("keeps the type of existing credentials", func() {
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{
+				Name:  "/some-cred",
+				Type:  "json",
+				Value: credhub.JSON{"old": "value"},
+			}, nil)
+
+			status, _ := request("PUT", "/some-cred", `{"new": "value"}`)
+			Expect(status).To(Equal(http.StatusCreated))
+
+			_, written := fakeCredhubClient.SetCredentialArgsForCall(0)
+			Expect(written).To(Equal(credhub.Credential{
+				Name:  "/some-cred",
+				Value: credhub.JSON{"new": "value"},
+			}))
+		})
+
+		It("deletes credentials", func() {
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{Name: "/some-cred", Type: "value", Value: credhub.Value("some-value")}, nil)
+
+			status, _ := request("DELETE", "/some-cred", "")
+			Expect(status).To(Equal(http.StatusNoContent))
+
+			Expect(fakeCredhubClient.DeleteCredentialByNameCallCount()).To(Equal(1))
+			_, removedName := fakeCredhubClient.DeleteCredentialByNameArgsForCall(0)
+			Expect(removedName).To(Equal("/some-cred"))
+		})
+
+		It("deletes every credential under a path", func() {
+			fakeCredhubClient.FindCredentialsByPathReturns([]credhub.Credential{
+				{Name: "/some-dir/cred1"},
+				{Name: "/some-dir/nested/cred2"},
+			}, nil)
+
+			status, _ := request("DELETE", "/some-dir/", "")
+			Expect(status).To(Equal(http.StatusNoContent))
+
+			Expect(fakeCredhubClient.DeleteCredentialByNameCallCount()).To(Equal(2))
+			_, removedName := fakeCredhubClient.DeleteCredentialByNameArgsForCall(0)
+			Expect(removedName).To(Equal("/some-dir/cred1"))
+			_, removedName = fakeCredhubClient.DeleteCredentialByNameArgsForCall(1)
+			Expect(removedName).To(Equal("/some-dir/nested/cred2"))
+		})
+
+		Context("when moving", func() {
+			var storedValues map[string]credhub.CredentialValue
+
+			BeforeEach(func() {
+				storedValues = map[string]credhub.CredentialValue{
+					"/old-dir/cred1":        credhub.Password("some-password"),
+					"/old-dir/nested/cred2": credhub.Value("some-value"),
+				}
+				fakeCredhubClient.GetCredentialByNameStub = func(_ context.Context, name string) (credhub.Credential, error) {
+					value, found := storedValues[name]
+					if !found {
+						return credhub.Credential{}, &credhub.ErrCredentialNotFound{}
+					}
+					return credhub.Credential{Name: name, Value: value}, nil
+				}
+				fakeCredhubClient.FindCredentialsByPathStub = func(_ context.Context, path string) ([]credhub.Credential, error) {
+					var credentials []credhub.Credential
+					for _, name := range []string{"/old-dir/cred1", "/old-dir/nested/cred2"} {
+						if _, found := storedValues[name]; found && strings.HasPrefix(name, strings.TrimSuffix(path, "/")+"/") {
+							credentials = append(credentials, credhub.Credential{Name: name})
+						}
+					}
+					return credentials, nil
+				}
+				fakeCredhubClient.SetCredentialStub = func(_ context.Context, credential credhub.Credential) (credhub.Credential, error) {
+					storedValues[credential.Name] = credential.Value
+					return credential, nil
+				}
+				fakeCredhubClient.DeleteCredentialByNameStub = func(_ context.Context, name string) error {
+					delete(storedValues, name)
+					return nil
+				}
+			})
+
+			It("moves credentials", func() {
+				status, _ := request("MOVE", "/old-dir/cred1", "", "Destination", "https://"+listenAddr+"/new-name")
+				Expect(status).To(Equal(http.StatusCreated))
+
+				Expect(storedValues).To(Equal(map[string]credhub.CredentialValue{
+					"/new-name":             credhub.Password("some-password"),
+					"/old-dir/nested/cred2": credhub.Value("some-value"),
+				}))
+			})
+
+			It("moves every credential under a path", func() {
+				status, _ := request("MOVE", "/old-dir/", "", "Destination", "https://"+listenAddr+"/new-dir/")
+				Expect(status).To(Equal(http.StatusCreated))
+
+				Expect(storedValues).To(Equal(map[string]credhub.CredentialValue{
+					"/new-dir/cred1":        credhub.Password("some-password"),
+					"/new-dir/nested/cred2": credhub.Value("some-value"),
+				}))
+			})
+
+			It("rolls back the move when writing a destination fails", func() {
+				setCredential := fakeCredhubClient.SetCredentialStub
+				fakeCredhubClient.SetCredentialStub = func(ctx context.Context, credential credhub.Credential) (credhub.Credential, error) {
+					if credential.Name == "/new-dir/nested/cred2" {
+						return credhub.Credential{}, errors.New("some-error")
+					}
+					return setCredential(ctx, credential)
+				}
+
+				status, _ := request("MOVE", "/old-dir/", "", "Destination", "https://"+listenAddr+"/new-dir/")
+				Expect(status).NotTo(Equal(http.StatusCreated))
+
+				Expect(storedValues).To(Equal(map[string]credhub.CredentialValue{
+					"/old-dir/cred1":        credhub.Password("some-password"),
+					"/old-dir/nested/cred2": credhub.Value("some-value"),
+				}))
+				for i := 0; i < fakeCredhubClient.DeleteCredentialByNameCallCount(); i++ {
+					_, removedName := fakeCredhubClient.DeleteCredentialByNameArgsForCall(i)
+					Expect(removedName).To(HavePrefix("/new-dir/"))
+				}
+			})
+		})
+
+		It("responds with an error when setting the credential fails", func() {
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{}, &credhub.ErrCredentialNotFound{})
+			fakeCredhubClient.SetCredentialReturns(credhub.Credential{}, errors.New("some-error"))
+
+			status, _ := request("PUT", "/some-cred", "some-value")
+			Expect(status).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+})
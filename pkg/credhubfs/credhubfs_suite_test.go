@@ -0,0 +1,13 @@
+package credhubfs_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCredhubfs(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Credhubfs Suite")
+}
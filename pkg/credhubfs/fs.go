@@ -0,0 +1,267 @@
+package credhubfs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+// FS is a read-only io/fs view of CredHub. CredHub paths are directories and
+// credentials are files whose contents are the formatted credential value.
+// Names are unrooted, so "some/path/cred" is the credential /some/path/cred.
+type FS struct {
+	ctx           context.Context
+	credhubClient credhubClient
+}
+
+var (
+	_ fs.FS         = &FS{}
+	_ fs.ReadDirFS  = &FS{}
+	_ fs.ReadFileFS = &FS{}
+	_ fs.StatFS     = &FS{}
+)
+
+func New(credhubClient credhub.Client) *FS {
+	return &FS{
+		ctx:           context.Background(),
+		credhubClient: credhubClient,
+	}
+}
+
+// WithContext returns a copy of the FS which makes its requests to CredHub
+// with ctx, since the io/fs interfaces have no way of passing one.
+func (f *FS) WithContext(ctx context.Context) *FS {
+	return &FS{
+		ctx:           ctx,
+		credhubClient: f.credhubClient,
+	}
+}
+
+func (f *FS) Open(name string) (fs.File, error) {
+	info, contents, err := f.stat("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return &dir{fs: f, name: name, info: info}, nil
+	}
+	return &file{Reader: bytes.NewReader(contents), info: info}, nil
+}
+
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	info, _, err := f.stat("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (f *FS) ReadFile(name string) ([]byte, error) {
+	info, contents, err := f.stat("read", name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: errors.New("is a directory")}
+	}
+	return contents, nil
+}
+
+// ReadDir lists the credentials and paths directly under name, deriving
+// directories from flattened credential names the same way `cfs ls` does.
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	credentials, err := f.credhubClient.FindCredentialsByPath(f.ctx, credhubName(name))
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+
+	if len(credentials) == 0 && name != "." {
+		if _, _, err := f.stat("readdir", name); err != nil {
+			return nil, err
+		}
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+
+	var entries []fs.DirEntry
+	seen := map[string]bool{}
+	for _, credential := range credentials {
+		relativeName := strings.TrimPrefix(strings.TrimPrefix(credential.Name, credhubName(name)), "/")
+		parts := strings.SplitN(relativeName, "/", 2)
+		if parts[0] == "" || seen[parts[0]] {
+			continue
+		}
+		seen[parts[0]] = true
+
+		entries = append(entries, &dirEntry{
+			fs:    f,
+			name:  path.Join(name, parts[0]),
+			isDir: len(parts) > 1,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
+
+func (f *FS) stat(op, name string) (*fileInfo, []byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	credentials, err := f.credhubClient.FindCredentialsByPath(f.ctx, credhubName(name))
+	if err != nil {
+		return nil, nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	if len(credentials) > 0 || name == "." {
+		info := &fileInfo{name: path.Base(name), mode: fs.ModeDir | 0500}
+		for _, credential := range credentials {
+			if credential.VersionCreatedAt.After(info.modTime) {
+				info.modTime = credential.VersionCreatedAt
+			}
+		}
+		return info, nil, nil
+	}
+
+	credential, err := f.credhubClient.GetCredentialByName(f.ctx, credhubName(name))
+	if err != nil {
+		if _, isNotFoundError := err.(*credhub.ErrCredentialNotFound); isNotFoundError {
+			err = fs.ErrNotExist
+		}
+		return nil, nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+
+	value, err := credhub.FormatValue(credential.Value)
+	if err != nil {
+		return nil, nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	contents := []byte(value + "\n")
+
+	return &fileInfo{
+		name:    path.Base(name),
+		size:    int64(len(contents)),
+		mode:    0400,
+		modTime: credential.VersionCreatedAt,
+	}, contents, nil
+}
+
+func credhubName(name string) string {
+	if name == "." {
+		return "/"
+	}
+	return "/" + name
+}
+
+type file struct {
+	*bytes.Reader
+	info *fileInfo
+}
+
+func (f *file) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *file) Close() error {
+	return nil
+}
+
+type dir struct {
+	fs      *FS
+	name    string
+	info    *fileInfo
+	entries []fs.DirEntry
+	offset  int
+	listed  bool
+}
+
+func (d *dir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *dir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+func (d *dir) Close() error {
+	return nil
+}
+
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.listed {
+		entries, err := d.fs.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries = entries
+		d.listed = true
+	}
+
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(remaining) {
+		n = len(remaining)
+	}
+	d.offset += n
+	return remaining[:n], nil
+}
+
+type dirEntry struct {
+	fs    *FS
+	name  string
+	isDir bool
+}
+
+func (e *dirEntry) Name() string {
+	return path.Base(e.name)
+}
+
+func (e *dirEntry) IsDir() bool {
+	return e.isDir
+}
+
+func (e *dirEntry) Type() fs.FileMode {
+	if e.isDir {
+		return fs.ModeDir
+	}
+	return 0
+}
+
+func (e *dirEntry) Info() (fs.FileInfo, error) {
+	return e.fs.Stat(e.name)
+}
+
+type fileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i *fileInfo) Name() string       { return i.name }
+func (i *fileInfo) Size() int64        { return i.size }
+func (i *fileInfo) Mode() fs.FileMode  { return i.mode }
+func (i *fileInfo) ModTime() time.Time { return i.modTime }
+func (i *fileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *fileInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,144 @@
+package credhubfs_test
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing/fstest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/pkg/credhubfs"
+	"github.com/mdelillo/credhub-fs/pkg/credhubfs/credhubfsfakes"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/credentials"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/handler"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/handler/handlerfakes"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/permissions"
+)
+
+var _ = Describe("FS", func() {
+	Context("backed by the fake CredHub", func() {
+		var (
+			credhubServer *httptest.Server
+			uaaServer     *httptest.Server
+			credhubClient credhub.Client
+			credhubFS     *credhubfs.FS
+		)
+
+		BeforeEach(func() {
+			gin.DefaultWriter = GinkgoWriter
+
+			uaaServer = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"access_token": "some-token"}`)
+			}))
+
+			credhubHandler, err := handler.NewCredhubHandler(uaaServer.URL, credentials.NewStore(), permissions.NewStore(false, nil), &handlerfakes.FakeTokenValidator{})
+			Expect(err).NotTo(HaveOccurred())
+			credhubServer = httptest.NewTLSServer(credhubHandler)
+
+			httpClient := &http.Client{
+				Timeout: 5 * time.Second,
+				Transport: &http.Transport{
+					TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+					Dial:                (&net.Dialer{Timeout: 5 * time.Second}).Dial,
+					TLSHandshakeTimeout: 5 * time.Second,
+				},
+			}
+			credhubClient = credhub.NewClient(strings.TrimPrefix(credhubServer.URL, "https://"), credhub.NewClientCredentialsAuthenticator("some-client-id", "some-client-secret"), httpClient, nil)
+			credhubFS = credhubfs.New(credhubClient)
+
+			for name, value := range map[string]credhub.CredentialValue{
+				"/top-level":             credhub.Value("some-value"),
+				"/some-dir/password":     credhub.Password("some-password"),
+				"/some-dir/nested/json":  credhub.JSON{"some-key": "some-value"},
+				"/some-dir/nested/user":  credhub.User{Username: "some-user", Password: "some-password"},
+				"/other-dir/certificate": credhub.Certificate{Certificate: "some-certificate"},
+			} {
+				_, err := credhubClient.SetCredential(context.Background(), credhub.Credential{Name: name, Value: value})
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		AfterEach(func() {
+			credhubServer.Close()
+			uaaServer.Close()
+		})
+
+		It("passes fstest.TestFS", func() {
+			Expect(fstest.TestFS(
+				credhubFS,
+				"top-level",
+				"some-dir/password",
+				"some-dir/nested/json",
+				"some-dir/nested/user",
+				"other-dir/certificate",
+			)).To(Succeed())
+		})
+
+		It("derives directories from credential names", func() {
+			entries, err := credhubFS.ReadDir("some-dir")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[0].Name()).To(Equal("nested"))
+			Expect(entries[0].IsDir()).To(BeTrue())
+			Expect(entries[1].Name()).To(Equal("password"))
+			Expect(entries[1].IsDir()).To(BeFalse())
+		})
+
+		It("reads formatted credential values", func() {
+			contents, err := credhubFS.ReadFile("some-dir/nested/user")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("username: some-user\npassword: some-password\n"))
+		})
+
+		It("uses the version creation time as the modification time", func() {
+			credential, err := credhubClient.GetCredentialByName(context.Background(), "/top-level")
+			Expect(err).NotTo(HaveOccurred())
+
+			info, err := credhubFS.Stat("top-level")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.ModTime()).To(Equal(credential.VersionCreatedAt))
+		})
+
+		It("works with fs.Glob", func() {
+			matches, err := fs.Glob(credhubFS, "some-dir/nested/*")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matches).To(Equal([]string{"some-dir/nested/json", "some-dir/nested/user"}))
+		})
+
+		It("returns fs.ErrNotExist for missing credentials", func() {
+			_, err := credhubFS.Open("some-dir/missing")
+			Expect(errors.Is(err, fs.ErrNotExist)).To(BeTrue())
+		})
+
+		It("refuses to read directories as files", func() {
+			_, err := credhubFS.ReadFile("some-dir")
+			Expect(err).To(MatchError("read some-dir: is a directory"))
+		})
+	})
+
+	Context("when CredHub returns an error", func() {
+		It("returns the error wrapped in a PathError", func() {
+			fakeCredhubClient := &credhubfsfakes.FakeCredhubClient{}
+			fakeCredhubClient.FindCredentialsByPathReturns(nil, errors.New("some-error"))
+
+			_, err := credhubfs.New(fakeCredhubClient).Stat("some-dir")
+			Expect(err).To(MatchError("stat some-dir: some-error"))
+		})
+
+		It("returns fs.ErrInvalid for invalid names", func() {
+			_, err := credhubfs.New(&credhubfsfakes.FakeCredhubClient{}).Open("/rooted")
+			Expect(errors.Is(err, fs.ErrInvalid)).To(BeTrue())
+		})
+	})
+})
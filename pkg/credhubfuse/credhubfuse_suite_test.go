@@ -0,0 +1,13 @@
+package credhubfuse_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCredhubfuse(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Credhubfuse Suite")
+}
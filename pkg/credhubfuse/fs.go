@@ -0,0 +1,279 @@
+package credhubfuse
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+// FS exposes CredHub as a FUSE filesystem. CredHub paths are directories and
+// credentials are files whose contents are the formatted credential value.
+// Only value, password and json credentials can be written; other types are
+// read-only. CredHub cannot store an empty credential, so empty files are not
+// supported: a created file is not persisted until it has contents.
+type FS struct {
+	credhubClient credhubClient
+}
+
+func NewFS(credhubClient credhub.Client) *FS {
+	return &FS{credhubClient: credhubClient}
+}
+
+func (f *FS) Root() (fs.Node, error) {
+	return &Dir{fs: f, path: "/"}, nil
+}
+
+// Dir is a CredHub path. Directories only exist while they contain
+// credentials, since CredHub has no notion of an empty path.
+type Dir struct {
+	fs   *FS
+	path string
+}
+
+func (d *Dir) Attr(ctx context.Context, attr *fuse.Attr) error {
+	attr.Mode = os.ModeDir | 0700
+	return nil
+}
+
+func (d *Dir) Lookup(ctx context.Context, name string) (fs.Node, error) {
+	childPath := path.Join(d.path, name)
+
+	credentials, err := d.fs.credhubClient.FindCredentialsByPath(ctx, childPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(credentials) > 0 {
+		return &Dir{fs: d.fs, path: childPath}, nil
+	}
+
+	credential, err := d.fs.credhubClient.GetCredentialByName(ctx, childPath)
+	if err != nil {
+		return nil, toErrno(err)
+	}
+	return &File{fs: d.fs, name: childPath, credential: credential}, nil
+}
+
+func (d *Dir) ReadDirAll(ctx context.Context) ([]fuse.Dirent, error) {
+	credentials, err := d.fs.credhubClient.FindCredentialsByPath(ctx, d.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirents []fuse.Dirent
+	seen := map[string]bool{}
+	for _, credential := range credentials {
+		relativeName := strings.TrimPrefix(strings.TrimPrefix(credential.Name, d.path), "/")
+		parts := strings.SplitN(relativeName, "/", 2)
+		if parts[0] == "" || seen[parts[0]] {
+			continue
+		}
+		seen[parts[0]] = true
+
+		dirent := fuse.Dirent{Name: parts[0], Type: fuse.DT_File}
+		if len(parts) > 1 {
+			dirent.Type = fuse.DT_Dir
+		}
+		dirents = append(dirents, dirent)
+	}
+	return dirents, nil
+}
+
+func (d *Dir) Mkdir(ctx context.Context, req *fuse.MkdirRequest) (fs.Node, error) {
+	return &Dir{fs: d.fs, path: path.Join(d.path, req.Name)}, nil
+}
+
+func (d *Dir) Create(ctx context.Context, req *fuse.CreateRequest, resp *fuse.CreateResponse) (fs.Node, fs.Handle, error) {
+	file := &File{
+		fs:         d.fs,
+		name:       path.Join(d.path, req.Name),
+		credential: credhub.Credential{Type: credhub.TypeValue},
+		loaded:     true,
+	}
+	resp.Flags |= fuse.OpenDirectIO
+	return file, file, nil
+}
+
+func (d *Dir) Remove(ctx context.Context, req *fuse.RemoveRequest) error {
+	name := path.Join(d.path, req.Name)
+
+	if req.Dir {
+		credentials, err := d.fs.credhubClient.FindCredentialsByPath(ctx, name)
+		if err != nil {
+			return err
+		}
+		if len(credentials) > 0 {
+			return fuse.Errno(syscall.ENOTEMPTY)
+		}
+		return nil
+	}
+
+	return toErrno(d.fs.credhubClient.DeleteCredentialByName(ctx, name))
+}
+
+// File is a single credential. Its contents are buffered while open and
+// written back to CredHub when the file is flushed.
+type File struct {
+	fs   *FS
+	name string
+
+	mu         sync.Mutex
+	credential credhub.Credential
+	data       []byte
+	loaded     bool
+	dirty      bool
+}
+
+func (f *File) Attr(ctx context.Context, attr *fuse.Attr) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	attr.Mode = 0600
+	if !writable(f.credential.Type) {
+		attr.Mode = 0400
+	}
+	attr.Mtime = f.credential.VersionCreatedAt
+	attr.Ctime = f.credential.VersionCreatedAt
+	if f.loaded {
+		attr.Size = uint64(len(f.data))
+	} else if contents, err := formatContents(f.credential.Value); err == nil {
+		attr.Size = uint64(len(contents))
+	}
+	return nil
+}
+
+func (f *File) Open(ctx context.Context, req *fuse.OpenRequest, resp *fuse.OpenResponse) (fs.Handle, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.dirty {
+		credential, err := f.fs.credhubClient.GetCredentialByName(ctx, f.name)
+		if err != nil {
+			return nil, toErrno(err)
+		}
+		contents, err := formatContents(credential.Value)
+		if err != nil {
+			return nil, err
+		}
+		f.credential = credential
+		f.data = contents
+		f.loaded = true
+	}
+
+	if !req.Flags.IsReadOnly() && !writable(f.credential.Type) {
+		return nil, fuse.Errno(syscall.EACCES)
+	}
+
+	resp.Flags |= fuse.OpenDirectIO
+	return f, nil
+}
+
+func (f *File) ReadAll(ctx context.Context) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]byte(nil), f.data...), nil
+}
+
+func (f *File) Write(ctx context.Context, req *fuse.WriteRequest, resp *fuse.WriteResponse) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	end := int(req.Offset) + len(req.Data)
+	if end > len(f.data) {
+		f.data = append(f.data, make([]byte, end-len(f.data))...)
+	}
+	copy(f.data[req.Offset:], req.Data)
+	f.dirty = true
+
+	resp.Size = len(req.Data)
+	return nil
+}
+
+func (f *File) Setattr(ctx context.Context, req *fuse.SetattrRequest, resp *fuse.SetattrResponse) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if req.Valid.Size() {
+		if !writable(f.credential.Type) {
+			return fuse.Errno(syscall.EACCES)
+		}
+		if int(req.Size) <= len(f.data) {
+			f.data = f.data[:req.Size]
+		} else {
+			f.data = append(f.data, make([]byte, int(req.Size)-len(f.data))...)
+		}
+		f.loaded = true
+		f.dirty = true
+	}
+	return nil
+}
+
+func (f *File) Flush(ctx context.Context, req *fuse.FlushRequest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.dirty {
+		return nil
+	}
+
+	text := strings.TrimSuffix(strings.TrimSuffix(string(f.data), "\n"), "\r")
+	if text == "" {
+		return fuse.Errno(syscall.EINVAL)
+	}
+
+	credentialType := f.credential.Type
+	if credentialType == "" {
+		credentialType = credhub.TypeValue
+	}
+	value, err := credhub.ParseValue(credentialType, text)
+	if err != nil {
+		return fuse.Errno(syscall.EINVAL)
+	}
+
+	credential, err := f.fs.credhubClient.SetCredential(ctx, credhub.Credential{Name: f.name, Value: value})
+	if err != nil {
+		return err
+	}
+	f.credential = credential
+	f.dirty = false
+	return nil
+}
+
+// writable reports whether credentials of the given type can be parsed from
+// file contents by credhub.ParseValue. A file created through the
+// filesystem has no type until it is written, and is saved as a value.
+func writable(credentialType string) bool {
+	switch credentialType {
+	case "", credhub.TypeValue, credhub.TypePassword, credhub.TypeJSON:
+		return true
+	}
+	return false
+}
+
+func formatContents(value credhub.CredentialValue) ([]byte, error) {
+	if value == nil {
+		return nil, nil
+	}
+	contents, err := credhub.FormatValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(contents + "\n"), nil
+}
+
+func toErrno(err error) error {
+	if _, isNotFoundError := err.(*credhub.ErrCredentialNotFound); isNotFoundError {
+		return fuse.ENOENT
+	}
+	return err
+}
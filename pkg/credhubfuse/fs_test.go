@@ -0,0 +1,274 @@
+package credhubfuse_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"time"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/pkg/credhubfuse"
+	"github.com/mdelillo/credhub-fs/pkg/credhubfuse/credhubfusefakes"
+)
+
+var _ = Describe("FS", func() {
+	var (
+		ctx               context.Context
+		fakeCredhubClient *credhubfusefakes.FakeCredhubClient
+		root              *credhubfuse.Dir
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fakeCredhubClient = &credhubfusefakes.FakeCredhubClient{}
+
+		node, err := credhubfuse.NewFS(fakeCredhubClient).Root()
+		Expect(err).NotTo(HaveOccurred())
+		root = node.(*credhubfuse.Dir)
+	})
+
+	lookupFile := func(name string, credential credhub.Credential) *credhubfuse.File {
+		fakeCredhubClient.FindCredentialsByPathReturnsOnCall(0, nil, nil)
+		fakeCredhubClient.GetCredentialByNameReturns(credential, nil)
+
+		node, err := root.Lookup(ctx, name)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		ExpectWithOffset(1, node).To(BeAssignableToTypeOf(&credhubfuse.File{}))
+		return node.(*credhubfuse.File)
+	}
+
+	Describe("Dir", func() {
+		It("is a directory", func() {
+			var attr fuse.Attr
+			Expect(root.Attr(ctx, &attr)).To(Succeed())
+			Expect(attr.Mode.IsDir()).To(BeTrue())
+		})
+
+		It("lists credentials as files and deeper paths as directories", func() {
+			fakeCredhubClient.FindCredentialsByPathReturns([]credhub.Credential{
+				{Name: "/cred1"},
+				{Name: "/some-dir/cred2"},
+				{Name: "/some-dir/cred3"},
+				{Name: "/some-dir/nested/cred4"},
+			}, nil)
+
+			dirents, err := root.ReadDirAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dirents).To(Equal([]fuse.Dirent{
+				{Name: "cred1", Type: fuse.DT_File},
+				{Name: "some-dir", Type: fuse.DT_Dir},
+			}))
+			_, searchedPath := fakeCredhubClient.FindCredentialsByPathArgsForCall(0)
+			Expect(searchedPath).To(Equal("/"))
+		})
+
+		It("looks up a path as a directory when it contains credentials", func() {
+			fakeCredhubClient.FindCredentialsByPathReturns([]credhub.Credential{{Name: "/some-dir/cred"}}, nil)
+
+			node, err := root.Lookup(ctx, "some-dir")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(node).To(BeAssignableToTypeOf(&credhubfuse.Dir{}))
+
+			_, searchedPath := fakeCredhubClient.FindCredentialsByPathArgsForCall(0)
+			Expect(searchedPath).To(Equal("/some-dir"))
+			Expect(fakeCredhubClient.GetCredentialByNameCallCount()).To(Equal(0))
+		})
+
+		It("looks up a credential as a file", func() {
+			file := lookupFile("some-cred", credhub.Credential{Name: "/some-cred"})
+			Expect(file).NotTo(BeNil())
+			_, requestedName := fakeCredhubClient.GetCredentialByNameArgsForCall(0)
+			Expect(requestedName).To(Equal("/some-cred"))
+		})
+
+		It("returns ENOENT when the name does not exist", func() {
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{}, &credhub.ErrCredentialNotFound{})
+
+			_, err := root.Lookup(ctx, "missing")
+			Expect(err).To(Equal(fuse.ENOENT))
+		})
+
+		It("deletes a credential when a file is removed", func() {
+			Expect(root.Remove(ctx, &fuse.RemoveRequest{Name: "some-cred"})).To(Succeed())
+
+			Expect(fakeCredhubClient.DeleteCredentialByNameCallCount()).To(Equal(1))
+			_, removedName := fakeCredhubClient.DeleteCredentialByNameArgsForCall(0)
+			Expect(removedName).To(Equal("/some-cred"))
+		})
+
+		It("returns ENOENT when removing a credential that does not exist", func() {
+			fakeCredhubClient.DeleteCredentialByNameReturns(&credhub.ErrCredentialNotFound{})
+
+			err := root.Remove(ctx, &fuse.RemoveRequest{Name: "missing"})
+			Expect(err).To(Equal(fuse.ENOENT))
+		})
+
+		It("refuses to remove a directory that contains credentials", func() {
+			fakeCredhubClient.FindCredentialsByPathReturns([]credhub.Credential{{Name: "/some-dir/cred"}}, nil)
+
+			err := root.Remove(ctx, &fuse.RemoveRequest{Name: "some-dir", Dir: true})
+			Expect(err).To(Equal(fuse.Errno(syscall.ENOTEMPTY)))
+			Expect(fakeCredhubClient.DeleteCredentialByNameCallCount()).To(Equal(0))
+		})
+
+		It("sets a new value credential when a created file is written and flushed", func() {
+			fakeCredhubClient.SetCredentialReturns(credhub.Credential{Name: "/new-cred", Type: "value", Value: credhub.Value("some-value")}, nil)
+
+			_, handle, err := root.Create(ctx, &fuse.CreateRequest{Name: "new-cred"}, &fuse.CreateResponse{})
+			Expect(err).NotTo(HaveOccurred())
+
+			write(ctx, handle, 0, "some-value\n")
+			Expect(handle.(fs.HandleFlusher).Flush(ctx, &fuse.FlushRequest{})).To(Succeed())
+
+			Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(1))
+			_, written := fakeCredhubClient.SetCredentialArgsForCall(0)
+			Expect(written).To(Equal(credhub.Credential{
+				Name:  "/new-cred",
+				Value: credhub.Value("some-value"),
+			}))
+		})
+
+		It("does not set a created file that is never written", func() {
+			_, handle, err := root.Create(ctx, &fuse.CreateRequest{Name: "new-cred"}, &fuse.CreateResponse{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(handle.(fs.HandleFlusher).Flush(ctx, &fuse.FlushRequest{})).To(Succeed())
+			Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("File", func() {
+		var versionCreatedAt time.Time
+
+		BeforeEach(func() {
+			versionCreatedAt = time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+		})
+
+		It("uses the version creation time as the mtime", func() {
+			file := lookupFile("some-cred", credhub.Credential{
+				Name:             "/some-cred",
+				Type:             "value",
+				Value:            credhub.Value("some-value"),
+				VersionCreatedAt: versionCreatedAt,
+			})
+
+			var attr fuse.Attr
+			Expect(file.Attr(ctx, &attr)).To(Succeed())
+			Expect(attr.Mtime).To(Equal(versionCreatedAt))
+			Expect(attr.Mode).To(Equal(os.FileMode(0600)))
+			Expect(attr.Size).To(Equal(uint64(len("some-value\n"))))
+		})
+
+		It("reads the current value of the credential", func() {
+			file := lookupFile("some-cred", credhub.Credential{Name: "/some-cred", Type: "value", Value: credhub.Value("old-value")})
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{Name: "/some-cred", Type: "value", Value: credhub.Value("new-value")}, nil)
+
+			handle, err := file.Open(ctx, &fuse.OpenRequest{}, &fuse.OpenResponse{})
+			Expect(err).NotTo(HaveOccurred())
+
+			contents, err := handle.(fs.HandleReadAller).ReadAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("new-value\n"))
+		})
+
+		It("formats structured credentials", func() {
+			file := lookupFile("some-cred", credhub.Credential{Name: "/some-cred", Type: "user"})
+			fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{
+				Name:  "/some-cred",
+				Type:  "user",
+				Value: credhub.User{Username: "some-user", Password: "some-password"},
+			}, nil)
+
+			handle, err := file.Open(ctx, &fuse.OpenRequest{}, &fuse.OpenResponse{})
+			Expect(err).NotTo(HaveOccurred())
+
+			contents, err := handle.(fs.HandleReadAller).ReadAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("username: some-user\npassword: some-password\n"))
+		})
+
+		It("overwrites the credential, keeping its type, when truncated and written", func() {
+			file := lookupFile("some-cred", credhub.Credential{Name: "/some-cred", Type: "password", Value: credhub.Password("old-password")})
+			fakeCredhubClient.SetCredentialReturns(credhub.Credential{Name: "/some-cred", Type: "password", Value: credhub.Password("new")}, nil)
+
+			handle, err := file.Open(ctx, &fuse.OpenRequest{}, &fuse.OpenResponse{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(file.Setattr(ctx, &fuse.SetattrRequest{Valid: fuse.SetattrSize, Size: 0}, &fuse.SetattrResponse{})).To(Succeed())
+			write(ctx, handle, 0, "new\n")
+			Expect(handle.(fs.HandleFlusher).Flush(ctx, &fuse.FlushRequest{})).To(Succeed())
+
+			_, written := fakeCredhubClient.SetCredentialArgsForCall(0)
+			Expect(written).To(Equal(credhub.Credential{
+				Name:  "/some-cred",
+				Value: credhub.Password("new"),
+			}))
+		})
+
+		Context("when the credential type cannot be written", func() {
+			var file *credhubfuse.File
+
+			BeforeEach(func() {
+				userCredential := credhub.Credential{
+					Name:  "/some-cred",
+					Type:  "user",
+					Value: credhub.User{Username: "some-user", Password: "some-password"},
+				}
+				file = lookupFile("some-cred", userCredential)
+				fakeCredhubClient.GetCredentialByNameReturns(userCredential, nil)
+			})
+
+			It("is read-only", func() {
+				var attr fuse.Attr
+				Expect(file.Attr(ctx, &attr)).To(Succeed())
+				Expect(attr.Mode).To(Equal(os.FileMode(0400)))
+
+				handle, err := file.Open(ctx, &fuse.OpenRequest{Flags: fuse.OpenReadOnly}, &fuse.OpenResponse{})
+				Expect(err).NotTo(HaveOccurred())
+
+				contents, err := handle.(fs.HandleReadAller).ReadAll(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(ContainSubstring("some-user"))
+			})
+
+			It("returns EACCES when opened for writing", func() {
+				_, err := file.Open(ctx, &fuse.OpenRequest{Flags: fuse.OpenWriteOnly}, &fuse.OpenResponse{})
+				Expect(err).To(Equal(fuse.Errno(syscall.EACCES)))
+
+				_, err = file.Open(ctx, &fuse.OpenRequest{Flags: fuse.OpenReadWrite}, &fuse.OpenResponse{})
+				Expect(err).To(Equal(fuse.Errno(syscall.EACCES)))
+				Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(0))
+			})
+
+			It("returns EACCES when truncated", func() {
+				err := file.Setattr(ctx, &fuse.SetattrRequest{Valid: fuse.SetattrSize, Size: 0}, &fuse.SetattrResponse{})
+				Expect(err).To(Equal(fuse.Errno(syscall.EACCES)))
+				Expect(fakeCredhubClient.SetCredentialCallCount()).To(Equal(0))
+			})
+		})
+
+		It("returns an error when setting the credential fails", func() {
+			fakeCredhubClient.SetCredentialReturns(credhub.Credential{}, errors.New("some-error"))
+
+			_, handle, err := root.Create(ctx, &fuse.CreateRequest{Name: "new-cred"}, &fuse.CreateResponse{})
+			Expect(err).NotTo(HaveOccurred())
+
+			write(ctx, handle, 0, "some-value")
+			err = handle.(fs.HandleFlusher).Flush(ctx, &fuse.FlushRequest{})
+			Expect(err).To(MatchError("some-error"))
+		})
+	})
+})
+
+func write(ctx context.Context, handle fs.Handle, offset int64, data string) {
+	var resp fuse.WriteResponse
+	err := handle.(fs.HandleWriter).Write(ctx, &fuse.WriteRequest{Offset: offset, Data: []byte(data)}, &resp)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	ExpectWithOffset(1, resp.Size).To(Equal(len(data)))
+}
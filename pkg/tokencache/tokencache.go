@@ -0,0 +1,159 @@
+// Package tokencache persists UAA access tokens on disk so that separate cfs
+// invocations can share them instead of each requesting a new one.
+package tokencache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+// Cache is a credhub.TokenStore backed by a JSON file which only its owner can
+// read, since the tokens it holds grant access to CredHub.
+type Cache struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// tokens maps CredHub addresses to the identities tokens were issued to, such
+// as UAA client IDs, to their tokens.
+type tokens map[string]map[string]credhub.Token
+
+func New(path string) *Cache {
+	return &Cache{path: path}
+}
+
+// DefaultPath returns the location of the token cache in the user's home
+// directory.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cfs", "tokens.json"), nil
+}
+
+func (c *Cache) GetToken(credhubAddr, identity string) (credhub.Token, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cached, err := c.read()
+	if err != nil {
+		return credhub.Token{}, err
+	}
+
+	return cached[credhubAddr][identity], nil
+}
+
+// SetToken stores the token, replacing any other token for the same CredHub and
+// identity, and drops tokens which have expired. A cache which cannot be parsed
+// is replaced, since it would otherwise stay unusable until it is cleared.
+func (c *Cache) SetToken(credhubAddr, identity string, token credhub.Token) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cached, err := c.read()
+	if _, isParseError := err.(*parseError); isParseError {
+		cached = tokens{}
+	} else if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for addr, clients := range cached {
+		for id, t := range clients {
+			if !t.ExpiresAt.After(now) {
+				delete(clients, id)
+			}
+		}
+		if len(clients) == 0 {
+			delete(cached, addr)
+		}
+	}
+
+	if cached[credhubAddr] == nil {
+		cached[credhubAddr] = make(map[string]credhub.Token)
+	}
+	cached[credhubAddr][identity] = token
+
+	return c.write(cached)
+}
+
+// Clear removes every cached token.
+func (c *Cache) Clear() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove token cache: %s", err.Error())
+	}
+	return nil
+}
+
+func (c *Cache) read() (tokens, error) {
+	contents, err := ioutil.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return tokens{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read token cache: %s", err.Error())
+	}
+
+	var cached tokens
+	if err := json.Unmarshal(contents, &cached); err != nil {
+		return nil, &parseError{path: c.path, err: err}
+	}
+	if cached == nil {
+		cached = tokens{}
+	}
+	return cached, nil
+}
+
+// parseError is returned by read when the cache file is not valid JSON.
+type parseError struct {
+	path string
+	err  error
+}
+
+func (e *parseError) Error() string {
+	return fmt.Sprintf("failed to parse token cache %s: %s", e.path, e.err.Error())
+}
+
+// write replaces the cache file atomically so that concurrent cfs invocations
+// never read a partially written file.
+func (c *Cache) write(cached tokens) error {
+	contents, err := json.MarshalIndent(cached, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal token cache: %s", err.Error())
+	}
+
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create token cache directory: %s", err.Error())
+	}
+
+	// TempFile creates the file with mode 0600.
+	tempFile, err := ioutil.TempFile(dir, ".tokens-")
+	if err != nil {
+		return fmt.Errorf("failed to create token cache: %s", err.Error())
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.Write(contents); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to write token cache: %s", err.Error())
+	}
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("failed to write token cache: %s", err.Error())
+	}
+
+	if err := os.Rename(tempFile.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to write token cache: %s", err.Error())
+	}
+	return nil
+}
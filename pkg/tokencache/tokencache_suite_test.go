@@ -0,0 +1,13 @@
+package tokencache_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTokencache(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Tokencache Suite")
+}
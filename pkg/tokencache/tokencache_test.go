@@ -0,0 +1,127 @@
+package tokencache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/pkg/tokencache"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cache", func() {
+	var (
+		tempDir string
+		path    string
+		cache   *tokencache.Cache
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "tokencache")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(tempDir, ".cfs", "tokens.json")
+		cache = tokencache.New(path)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	It("stores tokens keyed by CredHub address and client ID", func() {
+		token := credhub.Token{AccessToken: "some-token", ExpiresAt: time.Now().Add(time.Hour).UTC().Round(time.Second)}
+		otherToken := credhub.Token{AccessToken: "some-other-token", ExpiresAt: time.Now().Add(time.Hour).UTC().Round(time.Second)}
+
+		Expect(cache.SetToken("some-addr", "some-client", token)).To(Succeed())
+		Expect(cache.SetToken("some-addr", "some-other-client", otherToken)).To(Succeed())
+
+		Expect(tokencache.New(path).GetToken("some-addr", "some-client")).To(Equal(token))
+		Expect(tokencache.New(path).GetToken("some-addr", "some-other-client")).To(Equal(otherToken))
+		Expect(tokencache.New(path).GetToken("some-other-addr", "some-client")).To(Equal(credhub.Token{}))
+	})
+
+	It("creates the cache readable only by its owner", func() {
+		Expect(cache.SetToken("some-addr", "some-client", credhub.Token{
+			AccessToken: "some-token",
+			ExpiresAt:   time.Now().Add(time.Hour),
+		})).To(Succeed())
+
+		info, err := os.Stat(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+		info, err = os.Stat(filepath.Dir(path))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0700)))
+	})
+
+	It("drops expired tokens when storing a token", func() {
+		Expect(cache.SetToken("some-addr", "expired-client", credhub.Token{
+			AccessToken: "expired-token",
+			ExpiresAt:   time.Now().Add(-time.Minute),
+		})).To(Succeed())
+		Expect(cache.SetToken("some-addr", "some-client", credhub.Token{
+			AccessToken: "some-token",
+			ExpiresAt:   time.Now().Add(time.Hour),
+		})).To(Succeed())
+
+		contents, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).NotTo(ContainSubstring("expired-token"))
+		Expect(string(contents)).To(ContainSubstring("some-token"))
+	})
+
+	It("returns no token when the cache does not exist", func() {
+		Expect(cache.GetToken("some-addr", "some-client")).To(Equal(credhub.Token{}))
+	})
+
+	Context("when the cache is not valid JSON", func() {
+		It("returns an error", func() {
+			Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(path, []byte("some-invalid-json"), 0600)).To(Succeed())
+
+			_, err := cache.GetToken("some-addr", "some-client")
+			Expect(err).To(MatchError(ContainSubstring("failed to parse token cache")))
+		})
+
+		It("replaces the cache when storing a token", func() {
+			Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(path, []byte("some-invalid-json"), 0600)).To(Succeed())
+			token := credhub.Token{AccessToken: "some-token", ExpiresAt: time.Now().Add(time.Hour).UTC().Round(time.Second)}
+
+			Expect(cache.SetToken("some-addr", "some-client", token)).To(Succeed())
+
+			Expect(tokencache.New(path).GetToken("some-addr", "some-client")).To(Equal(token))
+		})
+	})
+
+	Context("when the cache is JSON null", func() {
+		It("stores a token", func() {
+			Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(path, []byte("null"), 0600)).To(Succeed())
+
+			Expect(cache.SetToken("some-addr", "some-client", credhub.Token{AccessToken: "some-token", ExpiresAt: time.Now().Add(time.Hour)})).To(Succeed())
+		})
+	})
+
+	Describe("Clear", func() {
+		It("removes the cache", func() {
+			Expect(cache.SetToken("some-addr", "some-client", credhub.Token{
+				AccessToken: "some-token",
+				ExpiresAt:   time.Now().Add(time.Hour),
+			})).To(Succeed())
+
+			Expect(cache.Clear()).To(Succeed())
+
+			Expect(path).NotTo(BeAnExistingFile())
+			Expect(cache.GetToken("some-addr", "some-client")).To(Equal(credhub.Token{}))
+		})
+
+		It("succeeds when the cache does not exist", func() {
+			Expect(cache.Clear()).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,132 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+// writtenDestination records a destination credential that has been written,
+// along with the value it replaced so that it can be restored on rollback.
+type writtenDestination struct {
+	name          string
+	previousValue credhub.CredentialValue
+}
+
+// Move moves the source of each transfer to its destination. Every
+// destination is written and verified before any source is removed, and if
+// one cannot be, the destinations already written are rolled back so that
+// CredHub is left as it was. If a source cannot be removed afterwards, the
+// error lists every source which was copied but not removed.
+func Move(ctx context.Context, credhubClient credhub.Client, transfers []Transfer) error {
+	sources := make(map[string]struct{}, len(transfers))
+	for _, transfer := range transfers {
+		sources[transfer.Source.Name] = struct{}{}
+	}
+	for _, transfer := range transfers {
+		if _, isSource := sources[transfer.Destination]; isSource {
+			return fmt.Errorf("cannot move %s onto %s", transfer.Source.Name, transfer.Destination)
+		}
+	}
+
+	var written []writtenDestination
+	for _, transfer := range transfers {
+		destination, err := writeDestination(ctx, credhubClient, transfer)
+		if destination != nil {
+			written = append(written, *destination)
+		}
+		if err != nil {
+			moveErr := fmt.Errorf("failed to move %s to %s: %s", transfer.Source.Name, transfer.Destination, err.Error())
+			if ctx.Err() != nil {
+				moveErr = errors.New("interrupted before moving any credentials")
+			}
+			if rollbackErr := rollback(credhubClient, written); rollbackErr != nil {
+				return fmt.Errorf("%s; rollback failed: %s", moveErr.Error(), rollbackErr.Error())
+			}
+			return moveErr
+		}
+	}
+
+	for i, transfer := range transfers {
+		if err := credhubClient.DeleteCredentialByName(ctx, transfer.Source.Name); err != nil {
+			var remaining []string
+			for _, notRemoved := range transfers[i:] {
+				remaining = append(remaining, notRemoved.Source.Name)
+			}
+			progress := fmt.Sprintf("moving %d of %d credentials; copied but did not remove %s", i, len(transfers), strings.Join(remaining, ", "))
+			if ctx.Err() != nil {
+				return fmt.Errorf("interrupted after %s", progress)
+			}
+			return fmt.Errorf("failed to remove %s: %s; stopped after %s", transfer.Source.Name, err.Error(), progress)
+		}
+	}
+
+	return nil
+}
+
+// writeDestination writes and verifies a single destination credential. The
+// returned writtenDestination is non-nil whenever CredHub may have been
+// modified, even if an error is also returned.
+func writeDestination(ctx context.Context, credhubClient credhub.Client, transfer Transfer) (*writtenDestination, error) {
+	source := transfer.Source
+	if source.Value == nil {
+		var err error
+		source, err = credhubClient.GetCredentialByName(ctx, source.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get credential: %s", err.Error())
+		}
+	}
+
+	destination := &writtenDestination{name: transfer.Destination}
+	existing, err := credhubClient.GetCredentialByName(ctx, transfer.Destination)
+	if err == nil {
+		destination.previousValue = existing.Value
+	} else if _, isNotFoundError := err.(*credhub.ErrCredentialNotFound); !isNotFoundError {
+		return nil, fmt.Errorf("failed to get credential: %s", err.Error())
+	}
+
+	if _, err := credhubClient.SetCredential(ctx, credhub.Credential{Name: transfer.Destination, Value: source.Value}); err != nil {
+		return destination, fmt.Errorf("failed to set credential: %s", err.Error())
+	}
+
+	written, err := credhubClient.GetCredentialByName(ctx, transfer.Destination)
+	if err != nil {
+		return destination, fmt.Errorf("failed to verify credential: %s", err.Error())
+	}
+	if !reflect.DeepEqual(written.Value, source.Value) {
+		return destination, errors.New("failed to verify credential: value does not match source")
+	}
+
+	return destination, nil
+}
+
+// rollback removes the destinations that were written, restoring any that
+// existed before the move. It runs even if the move was interrupted, so its
+// requests are not cancelled with the move's context.
+func rollback(credhubClient credhub.Client, written []writtenDestination) error {
+	ctx := context.Background()
+	var failed []string
+	for _, destination := range written {
+		var err error
+		if destination.previousValue != nil {
+			_, err = credhubClient.SetCredential(ctx, credhub.Credential{Name: destination.name, Value: destination.previousValue})
+		} else {
+			err = credhubClient.DeleteCredentialByName(ctx, destination.name)
+			if _, isNotFoundError := err.(*credhub.ErrCredentialNotFound); isNotFoundError {
+				err = nil
+			}
+		}
+		if err != nil {
+			failed = append(failed, destination.name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("could not restore %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
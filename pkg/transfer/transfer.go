@@ -0,0 +1,88 @@
+// Package transfer copies and moves credentials between names in CredHub,
+// following the same rules as cp(1) and mv(1).
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+)
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credhubClient
+type credhubClient credhub.Client
+
+// Transfer is a credential to be copied or moved to the Destination name.
+type Transfer struct {
+	Source      credhub.Credential
+	Destination string
+}
+
+// Plan maps the credentials at source to their names under
+// destination, following the same rules as cp(1) and mv(1). A source ending in
+// "/*" transfers the contents of the directory rather than the directory
+// itself. Credentials found under a directory are returned without values.
+func Plan(ctx context.Context, credhubClient credhub.Client, source, destination string) (transfers []Transfer, sourceIsDir bool, err error) {
+	copyContents := strings.HasSuffix(source, "/*")
+	source = strings.TrimSuffix(strings.TrimSuffix(source, "*"), "/")
+	if source == "" {
+		source = "/"
+	}
+
+	if !copyContents {
+		credential, err := credhubClient.GetCredentialByName(ctx, source)
+		if err == nil {
+			destinationIsDir, err := isDirectory(ctx, credhubClient, destination)
+			if err != nil {
+				return nil, false, err
+			}
+			if destinationIsDir {
+				destination = path.Join(destination, path.Base(source))
+			}
+			return []Transfer{{Source: credential, Destination: destination}}, false, nil
+		}
+		if _, isNotFoundError := err.(*credhub.ErrCredentialNotFound); !isNotFoundError {
+			return nil, false, fmt.Errorf("failed to get credential: %s", err.Error())
+		}
+	}
+
+	credentials, err := credhubClient.FindCredentialsByPath(ctx, source)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to find credentials: %s", err.Error())
+	}
+	if len(credentials) == 0 {
+		return nil, false, fmt.Errorf("'%s': no such credential or path", source)
+	}
+
+	if !copyContents {
+		destinationIsDir, err := isDirectory(ctx, credhubClient, destination)
+		if err != nil {
+			return nil, false, err
+		}
+		if destinationIsDir {
+			destination = path.Join(destination, path.Base(source))
+		}
+	}
+
+	for _, credential := range credentials {
+		transfers = append(transfers, Transfer{
+			Source:      credential,
+			Destination: path.Join(destination, strings.TrimPrefix(credential.Name, strings.TrimSuffix(source, "/"))),
+		})
+	}
+	return transfers, true, nil
+}
+
+func isDirectory(ctx context.Context, credhubClient credhub.Client, name string) (bool, error) {
+	if strings.HasSuffix(name, "/") {
+		return true, nil
+	}
+
+	credentials, err := credhubClient.FindCredentialsByPath(ctx, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to find credentials: %s", err.Error())
+	}
+	return len(credentials) > 0, nil
+}
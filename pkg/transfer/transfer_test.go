@@ -0,0 +1,262 @@
+package transfer_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mdelillo/credhub-fs/pkg/credhub"
+	"github.com/mdelillo/credhub-fs/pkg/transfer"
+	"github.com/mdelillo/credhub-fs/pkg/transfer/transferfakes"
+)
+
+var _ = Describe("Transfer", func() {
+	var (
+		ctx               context.Context
+		fakeCredhubClient *transferfakes.FakeCredhubClient
+		storedValues      map[string]credhub.CredentialValue
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fakeCredhubClient = &transferfakes.FakeCredhubClient{}
+
+		storedValues = map[string]credhub.CredentialValue{}
+		fakeCredhubClient.GetCredentialByNameStub = func(_ context.Context, name string) (credhub.Credential, error) {
+			value, found := storedValues[name]
+			if !found {
+				return credhub.Credential{}, &credhub.ErrCredentialNotFound{}
+			}
+			return credhub.Credential{Name: name, Value: value}, nil
+		}
+		fakeCredhubClient.FindCredentialsByPathStub = func(_ context.Context, path string) ([]credhub.Credential, error) {
+			var credentials []credhub.Credential
+			for name := range storedValues {
+				if strings.HasPrefix(name, strings.TrimSuffix(path, "/")+"/") {
+					credentials = append(credentials, credhub.Credential{Name: name})
+				}
+			}
+			sort.Slice(credentials, func(i, j int) bool { return credentials[i].Name < credentials[j].Name })
+			return credentials, nil
+		}
+		fakeCredhubClient.SetCredentialStub = func(_ context.Context, credential credhub.Credential) (credhub.Credential, error) {
+			storedValues[credential.Name] = credential.Value
+			return credential, nil
+		}
+		fakeCredhubClient.DeleteCredentialByNameStub = func(_ context.Context, name string) error {
+			if _, found := storedValues[name]; !found {
+				return &credhub.ErrCredentialNotFound{}
+			}
+			delete(storedValues, name)
+			return nil
+		}
+	})
+
+	Describe("Plan", func() {
+		It("transfers a credential to the destination name", func() {
+			storedValues["/some-cred"] = credhub.Value("some-value")
+
+			transfers, sourceIsDir, err := transfer.Plan(ctx, fakeCredhubClient, "/some-cred", "/new-cred")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sourceIsDir).To(BeFalse())
+			Expect(transfers).To(Equal([]transfer.Transfer{{
+				Source:      credhub.Credential{Name: "/some-cred", Value: credhub.Value("some-value")},
+				Destination: "/new-cred",
+			}}))
+		})
+
+		It("transfers a credential into a destination directory", func() {
+			storedValues["/some-cred"] = credhub.Value("some-value")
+			storedValues["/some-dir/other-cred"] = credhub.Value("other-value")
+
+			transfers, _, err := transfer.Plan(ctx, fakeCredhubClient, "/some-cred", "/some-dir")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transfers).To(HaveLen(1))
+			Expect(transfers[0].Destination).To(Equal("/some-dir/some-cred"))
+		})
+
+		It("transfers a directory into a destination directory", func() {
+			storedValues["/old-dir/cred1"] = credhub.Value("value1")
+			storedValues["/old-dir/nested/cred2"] = credhub.Value("value2")
+			storedValues["/new-dir/other-cred"] = credhub.Value("other-value")
+
+			transfers, sourceIsDir, err := transfer.Plan(ctx, fakeCredhubClient, "/old-dir", "/new-dir")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sourceIsDir).To(BeTrue())
+			Expect(transfers).To(Equal([]transfer.Transfer{
+				{Source: credhub.Credential{Name: "/old-dir/cred1"}, Destination: "/new-dir/old-dir/cred1"},
+				{Source: credhub.Credential{Name: "/old-dir/nested/cred2"}, Destination: "/new-dir/old-dir/nested/cred2"},
+			}))
+		})
+
+		It("transfers the contents of a directory when the source ends in '/*'", func() {
+			storedValues["/old-dir/cred1"] = credhub.Value("value1")
+			storedValues["/new-dir/other-cred"] = credhub.Value("other-value")
+
+			transfers, sourceIsDir, err := transfer.Plan(ctx, fakeCredhubClient, "/old-dir/*", "/new-dir")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sourceIsDir).To(BeTrue())
+			Expect(transfers).To(Equal([]transfer.Transfer{
+				{Source: credhub.Credential{Name: "/old-dir/cred1"}, Destination: "/new-dir/cred1"},
+			}))
+		})
+
+		Context("when the source does not exist", func() {
+			It("returns an error", func() {
+				_, _, err := transfer.Plan(ctx, fakeCredhubClient, "/missing", "/new-cred")
+				Expect(err).To(MatchError("'/missing': no such credential or path"))
+			})
+		})
+
+		Context("when getting the source fails", func() {
+			It("returns an error", func() {
+				fakeCredhubClient.GetCredentialByNameReturns(credhub.Credential{}, errors.New("some-error"))
+				fakeCredhubClient.GetCredentialByNameStub = nil
+
+				_, _, err := transfer.Plan(ctx, fakeCredhubClient, "/some-cred", "/new-cred")
+				Expect(err).To(MatchError("failed to get credential: some-error"))
+			})
+		})
+	})
+
+	Describe("Move", func() {
+		It("writes every destination before removing the sources", func() {
+			storedValues["/old-dir/cred1"] = credhub.Value("value1")
+			storedValues["/old-dir/cred2"] = credhub.Value("value2")
+
+			err := transfer.Move(ctx, fakeCredhubClient, []transfer.Transfer{
+				{Source: credhub.Credential{Name: "/old-dir/cred1"}, Destination: "/new-dir/cred1"},
+				{Source: credhub.Credential{Name: "/old-dir/cred2"}, Destination: "/new-dir/cred2"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(storedValues).To(Equal(map[string]credhub.CredentialValue{
+				"/new-dir/cred1": credhub.Value("value1"),
+				"/new-dir/cred2": credhub.Value("value2"),
+			}))
+			Expect(fakeCredhubClient.Invocations()["SetCredential"]).To(HaveLen(2))
+			Expect(fakeCredhubClient.Invocations()["DeleteCredentialByName"]).To(HaveLen(2))
+		})
+
+		Context("when writing a destination fails", func() {
+			It("rolls back the destinations and keeps the sources", func() {
+				storedValues["/old-dir/cred1"] = credhub.Value("value1")
+				storedValues["/old-dir/cred2"] = credhub.Value("value2")
+				storedValues["/new-dir/cred1"] = credhub.Value("previous-value")
+				setCredential := fakeCredhubClient.SetCredentialStub
+				fakeCredhubClient.SetCredentialStub = func(ctx context.Context, credential credhub.Credential) (credhub.Credential, error) {
+					if credential.Name == "/new-dir/cred2" {
+						return credhub.Credential{}, errors.New("some-error")
+					}
+					return setCredential(ctx, credential)
+				}
+
+				err := transfer.Move(ctx, fakeCredhubClient, []transfer.Transfer{
+					{Source: credhub.Credential{Name: "/old-dir/cred1"}, Destination: "/new-dir/cred1"},
+					{Source: credhub.Credential{Name: "/old-dir/cred2"}, Destination: "/new-dir/cred2"},
+				})
+				Expect(err).To(MatchError("failed to move /old-dir/cred2 to /new-dir/cred2: failed to set credential: some-error"))
+				Expect(storedValues).To(Equal(map[string]credhub.CredentialValue{
+					"/old-dir/cred1": credhub.Value("value1"),
+					"/old-dir/cred2": credhub.Value("value2"),
+					"/new-dir/cred1": credhub.Value("previous-value"),
+				}))
+			})
+		})
+
+		Context("when a written destination does not match its source", func() {
+			It("rolls back the move", func() {
+				storedValues["/some-cred"] = credhub.Value("some-value")
+				fakeCredhubClient.SetCredentialStub = func(_ context.Context, credential credhub.Credential) (credhub.Credential, error) {
+					storedValues[credential.Name] = credhub.Value("other-value")
+					return credential, nil
+				}
+
+				err := transfer.Move(ctx, fakeCredhubClient, []transfer.Transfer{
+					{Source: credhub.Credential{Name: "/some-cred"}, Destination: "/new-cred"},
+				})
+				Expect(err).To(MatchError("failed to move /some-cred to /new-cred: failed to verify credential: value does not match source"))
+				Expect(storedValues).To(Equal(map[string]credhub.CredentialValue{
+					"/some-cred": credhub.Value("some-value"),
+				}))
+			})
+		})
+
+		Context("when rolling back fails", func() {
+			It("returns an error listing the destinations that were not restored", func() {
+				storedValues["/some-cred"] = credhub.Value("some-value")
+				fakeCredhubClient.SetCredentialStub = func(_ context.Context, credential credhub.Credential) (credhub.Credential, error) {
+					storedValues[credential.Name] = credhub.Value("other-value")
+					return credential, nil
+				}
+				fakeCredhubClient.DeleteCredentialByNameStub = func(context.Context, string) error {
+					return errors.New("some-delete-error")
+				}
+
+				err := transfer.Move(ctx, fakeCredhubClient, []transfer.Transfer{
+					{Source: credhub.Credential{Name: "/some-cred"}, Destination: "/new-cred"},
+				})
+				Expect(err).To(MatchError(HaveSuffix("; rollback failed: could not restore /new-cred")))
+			})
+		})
+
+		Context("when the destination is one of the sources", func() {
+			It("returns an error without changing anything", func() {
+				err := transfer.Move(ctx, fakeCredhubClient, []transfer.Transfer{
+					{Source: credhub.Credential{Name: "/cred1"}, Destination: "/cred2"},
+					{Source: credhub.Credential{Name: "/cred2"}, Destination: "/cred3"},
+				})
+				Expect(err).To(MatchError("cannot move /cred1 onto /cred2"))
+				Expect(fakeCredhubClient.Invocations()).To(BeEmpty())
+			})
+		})
+
+		Context("when removing a source fails", func() {
+			It("returns an error listing the sources which were not removed", func() {
+				storedValues["/cred1"] = credhub.Value("value1")
+				storedValues["/cred2"] = credhub.Value("value2")
+				storedValues["/cred3"] = credhub.Value("value3")
+				deleteCredential := fakeCredhubClient.DeleteCredentialByNameStub
+				fakeCredhubClient.DeleteCredentialByNameStub = func(ctx context.Context, name string) error {
+					if name == "/cred2" {
+						return errors.New("some-error")
+					}
+					return deleteCredential(ctx, name)
+				}
+
+				err := transfer.Move(ctx, fakeCredhubClient, []transfer.Transfer{
+					{Source: credhub.Credential{Name: "/cred1"}, Destination: "/new/cred1"},
+					{Source: credhub.Credential{Name: "/cred2"}, Destination: "/new/cred2"},
+					{Source: credhub.Credential{Name: "/cred3"}, Destination: "/new/cred3"},
+				})
+				Expect(err).To(MatchError("failed to remove /cred2: some-error; stopped after moving 1 of 3 credentials; copied but did not remove /cred2, /cred3"))
+			})
+		})
+
+		Context("when interrupted while removing sources", func() {
+			It("reports how many credentials were moved", func() {
+				storedValues["/cred1"] = credhub.Value("value1")
+				storedValues["/cred2"] = credhub.Value("value2")
+				ctx, cancel := context.WithCancel(ctx)
+				defer cancel()
+				deleteCredential := fakeCredhubClient.DeleteCredentialByNameStub
+				fakeCredhubClient.DeleteCredentialByNameStub = func(ctx context.Context, name string) error {
+					if name == "/cred2" {
+						cancel()
+						return ctx.Err()
+					}
+					return deleteCredential(ctx, name)
+				}
+
+				err := transfer.Move(ctx, fakeCredhubClient, []transfer.Transfer{
+					{Source: credhub.Credential{Name: "/cred1"}, Destination: "/new/cred1"},
+					{Source: credhub.Credential{Name: "/cred2"}, Destination: "/new/cred2"},
+				})
+				Expect(err).To(MatchError("interrupted after moving 1 of 2 credentials; copied but did not remove /cred2"))
+			})
+		})
+	})
+})
@@ -1,17 +1,24 @@
 package credentials
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Credential struct {
-	ID               uuid.UUID `json:"id"`
-	Name             string    `json:"name"`
-	Type             string    `json:"type"`
-	Value            string    `json:"value"`
-	VersionCreatedAt time.Time `json:"version_created_at"`
+	ID               uuid.UUID              `json:"id"`
+	Name             string                 `json:"name"`
+	Type             string                 `json:"type"`
+	Value            interface{}            `json:"value"`
+	Metadata         map[string]interface{} `json:"metadata"`
+	VersionCreatedAt time.Time              `json:"version_created_at"`
+
+	// GenerationParameters holds the parameters a generated credential was
+	// created with so that it can be regenerated. It is nil for credentials
+	// that were set directly.
+	GenerationParameters json.RawMessage `json:"-"`
 }
 
 type CredentialNameAndDate struct {
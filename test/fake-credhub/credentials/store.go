@@ -1,27 +1,59 @@
 package credentials
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
 
 type store struct {
-	credentials map[string]Credential
+	credentials map[string][]Credential
 }
 
 type Store interface {
 	GetByName(name string) (cred Credential, found bool)
+	GetByID(id uuid.UUID) (cred Credential, found bool)
+	GetVersionsByName(name string) []Credential
 	GetByPath(path string) []Credential
+	GetAll() []Credential
 	Set(credential Credential)
 	Delete(name string) bool
 }
 
 func NewStore() Store {
 	return &store{
-		credentials: map[string]Credential{},
+		credentials: map[string][]Credential{},
 	}
 }
 
 func (s *store) GetByName(name string) (Credential, bool) {
-	cred, exists := s.credentials[name]
-	return cred, exists
+	versions, exists := s.credentials[name]
+	if !exists {
+		return Credential{}, false
+	}
+	return versions[len(versions)-1], true
+}
+
+func (s *store) GetByID(id uuid.UUID) (Credential, bool) {
+	for _, versions := range s.credentials {
+		for _, cred := range versions {
+			if cred.ID == id {
+				return cred, true
+			}
+		}
+	}
+	return Credential{}, false
+}
+
+// GetVersionsByName returns every version of the named credential, newest
+// first.
+func (s *store) GetVersionsByName(name string) []Credential {
+	versions := s.credentials[name]
+	newestFirst := make([]Credential, len(versions))
+	for i, cred := range versions {
+		newestFirst[len(versions)-1-i] = cred
+	}
+	return newestFirst
 }
 
 func (s *store) GetByPath(path string) []Credential {
@@ -31,17 +63,26 @@ func (s *store) GetByPath(path string) []Credential {
 		path = path + "/"
 	}
 
-	for name, cred := range s.credentials {
+	for name, versions := range s.credentials {
 		if strings.HasPrefix(name, path) {
-			matchingCredentials = append(matchingCredentials, cred)
+			matchingCredentials = append(matchingCredentials, versions[len(versions)-1])
 		}
 	}
 
 	return matchingCredentials
 }
 
+// GetAll returns the newest version of every credential.
+func (s *store) GetAll() []Credential {
+	var allCredentials []Credential
+	for _, versions := range s.credentials {
+		allCredentials = append(allCredentials, versions[len(versions)-1])
+	}
+	return allCredentials
+}
+
 func (s *store) Set(credential Credential) {
-	s.credentials[credential.Name] = credential
+	s.credentials[credential.Name] = append(s.credentials[credential.Name], credential)
 }
 
 func (s *store) Delete(name string) bool {
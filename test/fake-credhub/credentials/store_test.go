@@ -1,6 +1,7 @@
 package credentials_test
 
 import (
+	"github.com/google/uuid"
 	"github.com/mdelillo/credhub-fs/test/fake-credhub/credentials"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -46,9 +47,9 @@ var _ = Describe("Store", func() {
 	})
 
 	Context("when a credential with a duplicate name is set", func() {
-		It("overwrites the credential", func() {
-			oldCred := credentials.Credential{Name: "/cred", Value: "old"}
-			newCred := credentials.Credential{Name: "/cred", Value: "new"}
+		It("stores a new version of the credential", func() {
+			oldCred := credentials.Credential{ID: uuid.New(), Name: "/cred", Value: "old"}
+			newCred := credentials.Credential{ID: uuid.New(), Name: "/cred", Value: "new"}
 
 			store := credentials.NewStore()
 
@@ -58,6 +59,53 @@ var _ = Describe("Store", func() {
 			actualCred, found := store.GetByName(oldCred.Name)
 			Expect(found).To(BeTrue())
 			Expect(actualCred).To(Equal(newCred))
+
+			Expect(store.GetVersionsByName(oldCred.Name)).To(Equal([]credentials.Credential{newCred, oldCred}))
+			Expect(store.GetByPath("/")).To(ConsistOf(newCred))
+		})
+	})
+
+	Describe("GetByID", func() {
+		It("gets any version of a credential by ID", func() {
+			oldCred := credentials.Credential{ID: uuid.New(), Name: "/cred", Value: "old"}
+			newCred := credentials.Credential{ID: uuid.New(), Name: "/cred", Value: "new"}
+
+			store := credentials.NewStore()
+			store.Set(oldCred)
+			store.Set(newCred)
+
+			actualCred, found := store.GetByID(oldCred.ID)
+			Expect(found).To(BeTrue())
+			Expect(actualCred).To(Equal(oldCred))
+
+			_, found = store.GetByID(uuid.New())
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Describe("Delete", func() {
+		It("deletes every version of the credential", func() {
+			store := credentials.NewStore()
+			store.Set(credentials.Credential{Name: "/cred", Value: "old"})
+			store.Set(credentials.Credential{Name: "/cred", Value: "new"})
+
+			Expect(store.Delete("/cred")).To(BeTrue())
+			Expect(store.GetVersionsByName("/cred")).To(BeEmpty())
+		})
+	})
+
+	Describe("GetAll", func() {
+		It("gets the newest version of every credential", func() {
+			oldCred := credentials.Credential{Name: "/cred", Value: "old"}
+			newCred := credentials.Credential{Name: "/cred", Value: "new"}
+			otherCred := credentials.Credential{Name: "other-cred"}
+
+			store := credentials.NewStore()
+			store.Set(oldCred)
+			store.Set(newCred)
+			store.Set(otherCred)
+
+			Expect(store.GetAll()).To(ConsistOf(newCred, otherCred))
 		})
 	})
 
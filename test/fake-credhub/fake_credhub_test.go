@@ -3,10 +3,12 @@ package main_test
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
@@ -15,6 +17,7 @@ import (
 	"github.com/dgrijalva/jwt-go"
 	"github.com/google/uuid"
 	"github.com/mdelillo/credhub-fs/test/helpers"
+	"github.com/mdelillo/credhub-fs/test/server"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gexec"
@@ -29,13 +32,23 @@ var _ = Describe("FakeCredhub", func() {
 		makeRequest    func(method, path, body, authToken string) (statusCode int, responseBody string)
 		get            func(path, authToken string) (statusCode int, responseBody string)
 		put            func(path, body, authToken string) (statusCode int, responseBody string)
+		post           func(path, body, authToken string) (statusCode int, responseBody string)
 		delete         func(path, authToken string) (statusCode int, responseBody string)
+		clientCA       *server.CA
+		tempDir        string
 	)
 
 	BeforeSuite(func() {
 		var err error
 		fakeCredhub, err = gexec.Build(filepath.Join("github.com", "mdelillo", "credhub-fs", "test", "fake-credhub"))
 		Expect(err).NotTo(HaveOccurred())
+
+		tempDir, err = ioutil.TempDir("", "fake-credhub")
+		Expect(err).NotTo(HaveOccurred())
+
+		clientCA, err = server.NewCA()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ioutil.WriteFile(filepath.Join(tempDir, "client-ca.pem"), []byte(clientCA.CertificatePEM), 0600)).To(Succeed())
 	})
 
 	BeforeEach(func() {
@@ -49,8 +62,11 @@ var _ = Describe("FakeCredhub", func() {
 			"--listen-addr", listenAddr,
 			"--cert-path", filepath.Join("..", "fixtures", "127.0.0.1-cert.pem"),
 			"--key-path", filepath.Join("..", "fixtures", "127.0.0.1-key.pem"),
+			"--client-ca-path", filepath.Join(tempDir, "client-ca.pem"),
 			"--auth-server-addr", authServerAddr,
 			"--jwt-verification-key", helpers.PublicKeyToPEM(&jwtSigningKey.PublicKey),
+			"--enforce-permissions",
+			"--admin-client", "some-client-id",
 		)
 		_, err = gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
 		Expect(err).NotTo(HaveOccurred())
@@ -82,6 +98,9 @@ var _ = Describe("FakeCredhub", func() {
 		put = func(path, body, authToken string) (int, string) {
 			return makeRequest(http.MethodPut, path, body, authToken)
 		}
+		post = func(path, body, authToken string) (int, string) {
+			return makeRequest(http.MethodPost, path, body, authToken)
+		}
 		delete = func(path, authToken string) (int, string) {
 			return makeRequest(http.MethodDelete, path, "", authToken)
 		}
@@ -93,6 +112,7 @@ var _ = Describe("FakeCredhub", func() {
 
 	AfterSuite(func() {
 		gexec.CleanupBuildArtifacts()
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
 	})
 
 	It("implements GET /info", func() {
@@ -138,6 +158,69 @@ var _ = Describe("FakeCredhub", func() {
 			Expect(credFromGet).To(Equal(credFromSet))
 		})
 
+		It("can set and get structured credentials when authenticated", func() {
+			name := "/" + helpers.RandomString()
+
+			setCredReqBody := fmt.Sprintf(
+				`{"name": "%s", "value": {"public_key": "some-public-key", "private_key": "some-private-key"}, "type": "rsa"}`,
+				name,
+			)
+			token := generateJWTToken(authServerAddr, jwtSigningKey)
+
+			setCredStatusCode, _ := put("api/v1/data", setCredReqBody, token)
+			Expect(setCredStatusCode).To(Equal(http.StatusOK))
+
+			getCredStatusCode, getCredResponseBody := get("api/v1/data?name="+name, token)
+			Expect(getCredStatusCode).To(Equal(http.StatusOK))
+
+			var resp getCredResponse
+			Expect(json.Unmarshal([]byte(getCredResponseBody), &resp)).To(Succeed())
+			Expect(resp.Data).To(HaveLen(1))
+			Expect(resp.Data[0].Type).To(Equal("rsa"))
+			Expect(resp.Data[0].Value).To(Equal(map[string]interface{}{
+				"public_key":  "some-public-key",
+				"private_key": "some-private-key",
+			}))
+		})
+
+		It("retains previous versions of credentials", func() {
+			name := "/" + helpers.RandomString()
+			token := generateJWTToken(authServerAddr, jwtSigningKey)
+
+			var credsFromSet []credential
+			for _, value := range []string{"old-value", "new-value"} {
+				body := fmt.Sprintf(`{"name": "%s", "value": "%s", "type": "value"}`, name, value)
+				statusCode, respBody := put("api/v1/data", body, token)
+				Expect(statusCode).To(Equal(http.StatusOK))
+
+				var credFromSet credential
+				Expect(json.Unmarshal([]byte(respBody), &credFromSet)).To(Succeed())
+				credsFromSet = append(credsFromSet, credFromSet)
+			}
+
+			By("getting every version by name")
+			statusCode, respBody := get("api/v1/data?name="+name, token)
+			Expect(statusCode).To(Equal(http.StatusOK))
+
+			var resp getCredResponse
+			Expect(json.Unmarshal([]byte(respBody), &resp)).To(Succeed())
+			Expect(resp.Data).To(Equal([]credential{credsFromSet[1], credsFromSet[0]}))
+
+			By("getting a limited number of versions by name")
+			statusCode, respBody = get("api/v1/data?name="+name+"&versions=1", token)
+			Expect(statusCode).To(Equal(http.StatusOK))
+			Expect(json.Unmarshal([]byte(respBody), &resp)).To(Succeed())
+			Expect(resp.Data).To(Equal([]credential{credsFromSet[1]}))
+
+			By("getting an old version by ID")
+			statusCode, respBody = get("api/v1/data/"+credsFromSet[0].ID.String(), token)
+			Expect(statusCode).To(Equal(http.StatusOK))
+
+			var credFromGet credential
+			Expect(json.Unmarshal([]byte(respBody), &credFromGet)).To(Succeed())
+			Expect(credFromGet).To(Equal(credsFromSet[0]))
+		})
+
 		It("can list credential names and versionCreatedAt dates at a specific path", func() {
 			topLevelName := "/" + helpers.RandomString()
 			nestedName := "/some-dir/" + helpers.RandomString()
@@ -238,14 +321,100 @@ var _ = Describe("FakeCredhub", func() {
 			Expect(getCredResponseBody).To(MatchJSON(`{"error": "invalid_token", "error_description": "The request token is malformed. Please validate that your request token was issued by the UAA server authorized by CredHub."}`))
 		})
 	})
+	Describe("client certificates", func() {
+		clientWithCertificate := func(ca *server.CA, appGUID string) *http.Client {
+			certificatePEM, privateKeyPEM, err := ca.IssueClientCertificate(appGUID)
+			Expect(err).NotTo(HaveOccurred())
+			certificate, err := tls.X509KeyPair([]byte(certificatePEM), []byte(privateKeyPEM))
+			Expect(err).NotTo(HaveOccurred())
+			return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+				Certificates:       []tls.Certificate{certificate},
+			}}}
+		}
+
+		It("authenticates apps with certificates issued by the client CA", func() {
+			name := "/" + helpers.RandomString()
+			appGUID := uuid.New().String()
+			adminToken := generateJWTToken(authServerAddr, jwtSigningKey)
+
+			statusCode, _ := put("api/v1/data", fmt.Sprintf(`{"name": "%s", "value": "some-value", "type": "value"}`, name), adminToken)
+			Expect(statusCode).To(Equal(http.StatusOK))
+
+			statusCode, respBody := post("api/v2/permissions", fmt.Sprintf(`{
+				"actor": "mtls-app:%s",
+				"path": "%s",
+				"operations": ["read"]
+			}`, appGUID, name), adminToken)
+			Expect(statusCode).To(Equal(http.StatusCreated), respBody)
+
+			resp, err := clientWithCertificate(clientCA, appGUID).Get(fmt.Sprintf("https://%s/api/v1/data?name=%s", listenAddr, name))
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+			By("rejecting certificates issued by another CA")
+			otherCA, err := server.NewCA()
+			Expect(err).NotTo(HaveOccurred())
+			_, err = clientWithCertificate(otherCA, appGUID).Get(fmt.Sprintf("https://%s/api/v1/data?name=%s", listenAddr, name))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("/api/v2/permissions", func() {
+		It("enforces permissions for clients that are not admins", func() {
+			name := "/shared/" + helpers.RandomString()
+			adminToken := generateJWTToken(authServerAddr, jwtSigningKey)
+			otherToken := generateJWTTokenForClient(authServerAddr, "some-other-client-id", jwtSigningKey)
+
+			statusCode, _ := put("api/v1/data", fmt.Sprintf(`{"name": "%s", "value": "some-value", "type": "value"}`, name), adminToken)
+			Expect(statusCode).To(Equal(http.StatusOK))
+
+			statusCode, _ = get("api/v1/data?name="+name, otherToken)
+			Expect(statusCode).To(Equal(http.StatusNotFound))
+
+			By("granting read access to a wildcard path")
+			statusCode, respBody := post("api/v2/permissions", `{
+				"actor": "uaa-client:some-other-client-id",
+				"path": "/shared/*",
+				"operations": ["read"]
+			}`, adminToken)
+			Expect(statusCode).To(Equal(http.StatusCreated), respBody)
+
+			var permission struct {
+				UUID string `json:"uuid"`
+			}
+			Expect(json.Unmarshal([]byte(respBody), &permission)).To(Succeed())
+
+			statusCode, _ = get("api/v1/data?name="+name, otherToken)
+			Expect(statusCode).To(Equal(http.StatusOK))
+
+			statusCode, _ = put("api/v1/data", fmt.Sprintf(`{"name": "%s", "value": "some-value", "type": "value"}`, name), otherToken)
+			Expect(statusCode).To(Equal(http.StatusForbidden))
+
+			statusCode, respBody = get("api/v1/permissions?credential_name=/shared/*", adminToken)
+			Expect(statusCode).To(Equal(http.StatusOK))
+			Expect(respBody).To(MatchJSON(`{
+				"credential_name": "/shared/*",
+				"permissions": [{"actor": "uaa-client:some-other-client-id", "operations": ["read"]}]
+			}`))
+
+			By("revoking access")
+			statusCode, _ = delete("api/v2/permissions/"+permission.UUID, adminToken)
+			Expect(statusCode).To(Equal(http.StatusOK))
+
+			statusCode, _ = get("api/v1/data?name="+name, otherToken)
+			Expect(statusCode).To(Equal(http.StatusNotFound))
+		})
+	})
 })
 
 type credential struct {
-	ID               uuid.UUID `json:"id"`
-	Name             string    `json:"name"`
-	Type             string    `json:"type"`
-	Value            string    `json:"value"`
-	VersionCreatedAt time.Time `json:"version_created_at"`
+	ID               uuid.UUID   `json:"id"`
+	Name             string      `json:"name"`
+	Type             string      `json:"type"`
+	Value            interface{} `json:"value"`
+	VersionCreatedAt time.Time   `json:"version_created_at"`
 }
 
 type setCredResponse credential
@@ -260,8 +429,12 @@ type listCredsResponse struct {
 }
 
 func generateJWTToken(authServerAddr string, signingKey *rsa.PrivateKey) string {
+	return generateJWTTokenForClient(authServerAddr, "some-client-id", signingKey)
+}
+
+func generateJWTTokenForClient(authServerAddr, clientID string, signingKey *rsa.PrivateKey) string {
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
-		"client_id":  "some-client-id",
+		"client_id":  clientID,
 		"grant_type": "client_credentials",
 		"iss":        authServerAddr + "/oauth/token",
 		"scope":      []string{"credhub.read", "credhub.write"},
@@ -1,14 +1,26 @@
 package handler
 
 import (
+	"net/http"
 	"strings"
 
+	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 )
 
+const actorKey = "actor"
+
+// authenticationRequired identifies the actor making a request, either from a
+// UAA access token or, when no token is sent, from a verified client
+// certificate.
 func (h *credhubHandler) authenticationRequired(c *gin.Context) {
 	authorization := c.Request.Header.Get("Authorization")
 	if authorization == "" {
+		if actor := actorFromClientCertificate(c.Request); actor != "" {
+			c.Set(actorKey, actor)
+			return
+		}
+
 		c.JSON(401, gin.H{
 			"error":             ErrInvalidToken,
 			"error_description": ErrDescriptionNoAuthentication,
@@ -28,4 +40,47 @@ func (h *credhubHandler) authenticationRequired(c *gin.Context) {
 		c.Abort()
 		return
 	}
+
+	c.Set(actorKey, actorFromToken(tokenString))
+}
+
+// actorFromToken identifies the UAA user or client that a validated token was
+// issued to, in the form used by CredHub permissions.
+func actorFromToken(tokenString string) string {
+	var tokenClaims jwt.MapClaims
+	if _, _, err := new(jwt.Parser).ParseUnverified(tokenString, &tokenClaims); err != nil {
+		return ""
+	}
+
+	if userID, _ := tokenClaims["user_id"].(string); userID != "" {
+		return "uaa-user:" + userID
+	}
+
+	clientID, _ := tokenClaims["client_id"].(string)
+	if clientID == "" {
+		return ""
+	}
+	return "uaa-client:" + clientID
+}
+
+// actorFromClientCertificate identifies the app that a verified client
+// certificate was issued to. Like CredHub, it expects Diego instance identity
+// certificates, which carry the app GUID in an 'app:GUID' organizational unit.
+func actorFromClientCertificate(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return ""
+	}
+
+	for _, unit := range r.TLS.VerifiedChains[0][0].Subject.OrganizationalUnit {
+		if strings.HasPrefix(unit, "app:") {
+			return "mtls-app:" + strings.TrimPrefix(unit, "app:")
+		}
+	}
+	return ""
+}
+
+// authorized reports whether the actor making the request may perform the
+// operation on the named credential.
+func (h *credhubHandler) authorized(c *gin.Context, name, operation string) bool {
+	return h.permissionStore.Allowed(c.GetString(actorKey), name, operation)
 }
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/permissions"
 )
 
 func (h *credhubHandler) deleteDataHandler(c *gin.Context) {
@@ -11,6 +12,10 @@ func (h *credhubHandler) deleteDataHandler(c *gin.Context) {
 		c.JSON(400, gin.H{
 			"error": ErrMissingNameParameter,
 		})
+	} else if !h.authorized(c, name, permissions.OperationDelete) {
+		c.JSON(403, gin.H{
+			"error": ErrCredentialDoesNotExist,
+		})
 	} else {
 		found := h.credentialStore.Delete(name)
 		if !found {
@@ -15,16 +15,19 @@ var _ = Describe("DeleteDataHandler", func() {
 	var (
 		credhubHandler      http.Handler
 		fakeCredentialStore *handlerfakes.FakeCredentialStore
+		fakePermissionStore *handlerfakes.FakePermissionStore
 		fakeTokenValidator  *handlerfakes.FakeTokenValidator
 		authServerURL       = "some-auth-server-url"
 	)
 
 	BeforeEach(func() {
 		fakeCredentialStore = &handlerfakes.FakeCredentialStore{}
+		fakePermissionStore = &handlerfakes.FakePermissionStore{}
+		fakePermissionStore.AllowedReturns(true)
 		fakeTokenValidator = &handlerfakes.FakeTokenValidator{}
 
 		var err error
-		credhubHandler, err = handler.NewCredhubHandler(authServerURL, fakeCredentialStore, fakeTokenValidator)
+		credhubHandler, err = handler.NewCredhubHandler(authServerURL, fakeCredentialStore, fakePermissionStore, fakeTokenValidator)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
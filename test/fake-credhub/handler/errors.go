@@ -1,11 +1,28 @@
 package handler
 
 const (
-	ErrCredentialDoesNotExist      = "The request could not be completed because the credential does not exist or you do not have sufficient authorization."
-	ErrDescriptionMalformedToken   = "The request token is malformed. Please validate that your request token was issued by the UAA server authorized by CredHub."
-	ErrDescriptionNoAuthentication = "Full authentication is required to access this resource"
-	ErrInvalidPathOrBody           = "The request could not be fulfilled because the request path or body did not meet expectation. Please check the documentation for required formatting and retry your request."
-	ErrInvalidToken                = "invalid_token"
-	ErrInvalidType                 = "Only 'value' types are supported"
-	ErrMissingNameParameter        = "The query parameter name is required for this request."
+	ErrCANotFound                     = "The request could not be completed because the CA does not exist or you do not have sufficient authorization."
+	ErrCannotGenerateType             = "Credentials of this type cannot be generated. Please adjust the credential type and retry your request."
+	ErrCannotRegenerateStatic         = "The credential could not be regenerated because the value was statically set. Only generated credentials can be regenerated."
+	ErrCertificateMissingSigner       = "Certificate generation requires a CA name, the self_sign flag or the is_ca flag."
+	ErrCertificateMissingSubject      = "You must provide a common name or at least one alternative name."
+	ErrCredentialDoesNotExist         = "The request could not be completed because the credential does not exist or you do not have sufficient authorization."
+	ErrDescriptionMalformedToken      = "The request token is malformed. Please validate that your request token was issued by the UAA server authorized by CredHub."
+	ErrDescriptionNoAuthentication    = "Full authentication is required to access this resource"
+	ErrInvalidDuration                = "The provided duration must be between 1 and 3650 days."
+	ErrInvalidKeyLength               = "The provided key length is not supported. Valid values include '2048', '3072' and '4096'."
+	ErrInvalidMode                    = "The request includes an unrecognized mode. Please update or remove the mode and retry your request."
+	ErrInvalidOperation               = "The provided operation is not supported. Valid values include read, write, delete, read_acl, and write_acl."
+	ErrInvalidPasswordLength          = "The password length must be between 4 and 200."
+	ErrInvalidPasswordParameters      = "The combination of parameters in the request is not allowed. Please validate your input and retry your request."
+	ErrInvalidPathOrBody              = "The request could not be fulfilled because the request path or body did not meet expectation. Please check the documentation for required formatting and retry your request."
+	ErrInvalidToken                   = "invalid_token"
+	ErrInvalidType                    = "The request does not include a valid type. Valid values include 'value', 'json', 'password', 'user', 'certificate', 'ssh' and 'rsa'."
+	ErrInvalidVersionsParameter       = "The query parameter versions must be a positive integer."
+	ErrMissingCredentialNameParameter = "The query parameter credential_name is required for this request."
+	ErrMissingNameParameter           = "The query parameter name is required for this request."
+	ErrMissingPathAndActorParameters  = "The query parameters path and actor are required for this request."
+	ErrMissingSignedByParameter       = "The request does not include the required signed_by parameter."
+	ErrPermissionAlreadyExists        = "A permission entry for this actor and path already exists."
+	ErrPermissionDoesNotExist         = "The request could not be completed because the permission does not exist or you do not have sufficient authorization."
 )
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fault makes the next Count requests with the given method and path fail with
+// Status, the way a load balancer in front of CredHub does when it cannot
+// reach it. Faults are added with POST /fake/faults so that tests can exercise
+// transient failures deterministically.
+type fault struct {
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Status     int    `json:"status"`
+	Count      int    `json:"count"`
+	RetryAfter string `json:"retry_after"`
+}
+
+type faultInjector struct {
+	mu     sync.Mutex
+	faults []*fault
+}
+
+func (h *credhubHandler) postFaultHandler(c *gin.Context) {
+	var f fault
+	if err := c.BindJSON(&f); err != nil || f.Method == "" || f.Path == "" || f.Status == 0 || f.Count < 1 {
+		c.JSON(400, gin.H{"error": ErrInvalidPathOrBody})
+		return
+	}
+
+	h.faults.mu.Lock()
+	h.faults.faults = append(h.faults.faults, &f)
+	h.faults.mu.Unlock()
+
+	c.Status(http.StatusNoContent)
+}
+
+// injectFaults fails a request if a fault was added for it, using up one of
+// the fault's failures.
+func (h *credhubHandler) injectFaults(c *gin.Context) {
+	h.faults.mu.Lock()
+	defer h.faults.mu.Unlock()
+
+	for i, f := range h.faults.faults {
+		if f.Method != c.Request.Method || f.Path != c.Request.URL.Path {
+			continue
+		}
+
+		f.Count--
+		if f.Count == 0 {
+			h.faults.faults = append(h.faults.faults[:i], h.faults.faults[i+1:]...)
+		}
+
+		if f.RetryAfter != "" {
+			c.Header("Retry-After", f.RetryAfter)
+		}
+		c.JSON(f.Status, gin.H{"error": http.StatusText(f.Status)})
+		c.Abort()
+		return
+	}
+}
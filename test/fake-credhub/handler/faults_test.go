@@ -0,0 +1,62 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/handler"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Faults", func() {
+	var credhubHandler http.Handler
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		responseRecorder := httptest.NewRecorder()
+		request, err := http.NewRequest(method, path, strings.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		credhubHandler.ServeHTTP(responseRecorder, request)
+		return responseRecorder
+	}
+
+	BeforeEach(func() {
+		var err error
+		credhubHandler, err = handler.NewCredhubHandler("some-auth-server-url", nil, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("fails the given number of matching requests with the given status", func() {
+		response := serve("POST", "/fake/faults", `{"method": "GET", "path": "/info", "status": 503, "count": 2, "retry_after": "1"}`)
+		Expect(response.Code).To(Equal(http.StatusNoContent))
+
+		for i := 0; i < 2; i++ {
+			response = serve("GET", "/info", "")
+			Expect(response.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(response.Header().Get("Retry-After")).To(Equal("1"))
+			Expect(readBody(response)).To(MatchJSON(`{"error": "Service Unavailable"}`))
+		}
+
+		Expect(serve("GET", "/info", "").Code).To(Equal(http.StatusOK))
+	})
+
+	It("does not fail requests with a different method or path", func() {
+		serve("POST", "/fake/faults", `{"method": "GET", "path": "/api/v1/data", "status": 502, "count": 1}`)
+
+		Expect(serve("GET", "/info", "").Code).To(Equal(http.StatusOK))
+		Expect(serve("DELETE", "/api/v1/data", "").Code).NotTo(Equal(http.StatusBadGateway))
+	})
+
+	It("rejects faults without a method, path, status and count", func() {
+		for _, body := range []string{
+			`{"path": "/info", "status": 503, "count": 1}`,
+			`{"method": "GET", "status": 503, "count": 1}`,
+			`{"method": "GET", "path": "/info", "count": 1}`,
+			`{"method": "GET", "path": "/info", "status": 503}`,
+			`some-invalid-json`,
+		} {
+			Expect(serve("POST", "/fake/faults", body).Code).To(Equal(http.StatusBadRequest))
+		}
+	})
+})
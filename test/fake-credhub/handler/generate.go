@@ -0,0 +1,299 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"time"
+)
+
+const (
+	defaultPasswordLength = 30
+	defaultKeyLength      = 2048
+	defaultDuration       = 365
+
+	upperCharacters   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	lowerCharacters   = "abcdefghijklmnopqrstuvwxyz"
+	numberCharacters  = "0123456789"
+	specialCharacters = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+)
+
+// generate creates a new value of the given type from the generation
+// parameters.
+func (h *credhubHandler) generate(credentialType string, parameters json.RawMessage) (interface{}, error) {
+	switch credentialType {
+	case "password":
+		return generatePassword(parameters)
+	case "certificate":
+		return h.generateCertificate(parameters)
+	case "rsa":
+		return generateRSA(parameters)
+	case "ssh":
+		return generateSSH(parameters)
+	default:
+		return nil, errors.New(ErrCannotGenerateType)
+	}
+}
+
+func generatePassword(rawParameters json.RawMessage) (interface{}, error) {
+	parameters := struct {
+		Length         int  `json:"length"`
+		ExcludeUpper   bool `json:"exclude_upper"`
+		ExcludeLower   bool `json:"exclude_lower"`
+		ExcludeNumber  bool `json:"exclude_number"`
+		IncludeSpecial bool `json:"include_special"`
+	}{Length: defaultPasswordLength}
+	if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+		return nil, errors.New(ErrInvalidPathOrBody)
+	}
+
+	if parameters.Length < 4 || parameters.Length > 200 {
+		return nil, errors.New(ErrInvalidPasswordLength)
+	}
+
+	var charset string
+	if !parameters.ExcludeUpper {
+		charset += upperCharacters
+	}
+	if !parameters.ExcludeLower {
+		charset += lowerCharacters
+	}
+	if !parameters.ExcludeNumber {
+		charset += numberCharacters
+	}
+	if parameters.IncludeSpecial {
+		charset += specialCharacters
+	}
+	if charset == "" {
+		return nil, errors.New(ErrInvalidPasswordParameters)
+	}
+
+	password := make([]byte, parameters.Length)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+		if err != nil {
+			return nil, err
+		}
+		password[i] = charset[n.Int64()]
+	}
+
+	return string(password), nil
+}
+
+func (h *credhubHandler) generateCertificate(rawParameters json.RawMessage) (interface{}, error) {
+	parameters := struct {
+		CommonName       string   `json:"common_name"`
+		AlternativeNames []string `json:"alternative_names"`
+		CA               string   `json:"ca"`
+		IsCA             bool     `json:"is_ca"`
+		SelfSign         bool     `json:"self_sign"`
+		KeyLength        int      `json:"key_length"`
+		Duration         int      `json:"duration"`
+	}{KeyLength: defaultKeyLength, Duration: defaultDuration}
+	if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+		return nil, errors.New(ErrInvalidPathOrBody)
+	}
+
+	if parameters.CommonName == "" && len(parameters.AlternativeNames) == 0 {
+		return nil, errors.New(ErrCertificateMissingSubject)
+	}
+	if parameters.CA == "" && !parameters.IsCA && !parameters.SelfSign {
+		return nil, errors.New(ErrCertificateMissingSigner)
+	}
+	if parameters.Duration < 1 || parameters.Duration > 3650 {
+		return nil, errors.New(ErrInvalidDuration)
+	}
+
+	privateKey, err := generateKey(parameters.KeyLength)
+	if err != nil {
+		return nil, err
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: parameters.CommonName},
+		NotBefore:    now,
+		NotAfter:     now.AddDate(0, 0, parameters.Duration),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+	}
+	for _, name := range parameters.AlternativeNames {
+		if ip := net.ParseIP(name); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, name)
+		}
+	}
+	if parameters.IsCA {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	}
+
+	parent, signingKey := template, privateKey
+	var caPEM string
+	if parameters.CA != "" {
+		caPEM, parent, signingKey, err = h.loadCA(parameters.CA)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	certificateDER, err := x509.CreateCertificate(rand.Reader, template, parent, &privateKey.PublicKey, signingKey)
+	if err != nil {
+		return nil, err
+	}
+	certificatePEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificateDER}))
+
+	value := map[string]interface{}{
+		"certificate": certificatePEM,
+		"private_key": encodePrivateKey(privateKey),
+	}
+	if parameters.CA != "" {
+		value["ca"] = caPEM
+		value["ca_name"] = parameters.CA
+	} else {
+		value["ca"] = certificatePEM
+	}
+
+	return value, nil
+}
+
+// loadCA reads the certificate and private key of the named CA from the store.
+func (h *credhubHandler) loadCA(name string) (string, *x509.Certificate, *rsa.PrivateKey, error) {
+	errCANotFound := errors.New(ErrCANotFound)
+
+	cred, found := h.credentialStore.GetByName(name)
+	if !found || cred.Type != "certificate" {
+		return "", nil, nil, errCANotFound
+	}
+
+	value, _ := cred.Value.(map[string]interface{})
+	certificatePEM, _ := value["certificate"].(string)
+	privateKeyPEM, _ := value["private_key"].(string)
+
+	certificateBlock, _ := pem.Decode([]byte(certificatePEM))
+	privateKeyBlock, _ := pem.Decode([]byte(privateKeyPEM))
+	if certificateBlock == nil || privateKeyBlock == nil {
+		return "", nil, nil, errCANotFound
+	}
+
+	certificate, err := x509.ParseCertificate(certificateBlock.Bytes)
+	if err != nil || !certificate.IsCA {
+		return "", nil, nil, errCANotFound
+	}
+
+	privateKey, err := x509.ParsePKCS1PrivateKey(privateKeyBlock.Bytes)
+	if err != nil {
+		return "", nil, nil, errCANotFound
+	}
+
+	return certificatePEM, certificate, privateKey, nil
+}
+
+func generateRSA(rawParameters json.RawMessage) (interface{}, error) {
+	parameters := struct {
+		KeyLength int `json:"key_length"`
+	}{KeyLength: defaultKeyLength}
+	if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+		return nil, errors.New(ErrInvalidPathOrBody)
+	}
+
+	privateKey, err := generateKey(parameters.KeyLength)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"public_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER})),
+		"private_key": encodePrivateKey(privateKey),
+	}, nil
+}
+
+func generateSSH(rawParameters json.RawMessage) (interface{}, error) {
+	parameters := struct {
+		KeyLength  int    `json:"key_length"`
+		SSHComment string `json:"ssh_comment"`
+	}{KeyLength: defaultKeyLength}
+	if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+		return nil, errors.New(ErrInvalidPathOrBody)
+	}
+
+	privateKey, err := generateKey(parameters.KeyLength)
+	if err != nil {
+		return nil, err
+	}
+
+	wireFormat := sshRSAPublicKey(&privateKey.PublicKey)
+	publicKey := "ssh-rsa " + base64.StdEncoding.EncodeToString(wireFormat)
+	if parameters.SSHComment != "" {
+		publicKey += " " + parameters.SSHComment
+	}
+	fingerprint := sha256.Sum256(wireFormat)
+
+	return map[string]interface{}{
+		"public_key":             publicKey,
+		"private_key":            encodePrivateKey(privateKey),
+		"public_key_fingerprint": base64.RawStdEncoding.EncodeToString(fingerprint[:]),
+	}, nil
+}
+
+func generateKey(keyLength int) (*rsa.PrivateKey, error) {
+	switch keyLength {
+	case 2048, 3072, 4096:
+		return rsa.GenerateKey(rand.Reader, keyLength)
+	default:
+		return nil, errors.New(ErrInvalidKeyLength)
+	}
+}
+
+func encodePrivateKey(privateKey *rsa.PrivateKey) string {
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	}))
+}
+
+// sshRSAPublicKey encodes the key in the SSH wire format described in RFC 4253.
+func sshRSAPublicKey(publicKey *rsa.PublicKey) []byte {
+	var b bytes.Buffer
+	writeSSHString := func(data []byte) {
+		length := make([]byte, 4)
+		binary.BigEndian.PutUint32(length, uint32(len(data)))
+		b.Write(length)
+		b.Write(data)
+	}
+	writeSSHMPInt := func(n *big.Int) {
+		data := n.Bytes()
+		if len(data) > 0 && data[0]&0x80 != 0 {
+			data = append([]byte{0}, data...)
+		}
+		writeSSHString(data)
+	}
+
+	writeSSHString([]byte("ssh-rsa"))
+	writeSSHMPInt(big.NewInt(int64(publicKey.E)))
+	writeSSHMPInt(publicKey.N)
+
+	return b.Bytes()
+}
@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"strconv"
+
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/mdelillo/credhub-fs/test/fake-credhub/credentials"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/permissions"
 )
 
 func (h *credhubHandler) getDataHandler(c *gin.Context) {
@@ -21,23 +25,61 @@ func (h *credhubHandler) getDataHandler(c *gin.Context) {
 }
 
 func (h *credhubHandler) getDataByNameHandler(name string, c *gin.Context) {
-	cred, found := h.credentialStore.GetByName(name)
-	if !found {
+	versions := h.credentialStore.GetVersionsByName(name)
+	if len(versions) == 0 || !h.authorized(c, name, permissions.OperationRead) {
 		c.JSON(404, gin.H{
 			"error": ErrCredentialDoesNotExist,
 		})
 		return
 	}
 
+	if c.Query("current") == "true" {
+		versions = versions[:1]
+	} else if versionsParam := c.Query("versions"); versionsParam != "" {
+		n, err := strconv.Atoi(versionsParam)
+		if err != nil || n < 1 {
+			c.JSON(400, gin.H{
+				"error": ErrInvalidVersionsParameter,
+			})
+			return
+		}
+		if n < len(versions) {
+			versions = versions[:n]
+		}
+	}
+
 	c.JSON(200, gin.H{
-		"data": []credentials.Credential{cred},
+		"data": versions,
 	})
 }
 
+func (h *credhubHandler) getDataByIDHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(404, gin.H{
+			"error": ErrCredentialDoesNotExist,
+		})
+		return
+	}
+
+	cred, found := h.credentialStore.GetByID(id)
+	if !found || !h.authorized(c, cred.Name, permissions.OperationRead) {
+		c.JSON(404, gin.H{
+			"error": ErrCredentialDoesNotExist,
+		})
+		return
+	}
+
+	c.JSON(200, cred)
+}
+
 func (h *credhubHandler) getDataByPathHandler(path string, c *gin.Context) {
 	creds := h.credentialStore.GetByPath(path)
 	var credsView []credentials.CredentialNameAndDate
 	for _, cred := range creds {
+		if !h.authorized(c, cred.Name, permissions.OperationRead) {
+			continue
+		}
 		credsView = append(credsView, credentials.CredentialNameAndDate{
 			Name: cred.Name, VersionCreatedAt: cred.VersionCreatedAt,
 		})
@@ -1,6 +1,9 @@
 package handler_test
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -20,30 +23,40 @@ var _ = Describe("GetDataHandler", func() {
 	var (
 		credhubHandler      http.Handler
 		fakeCredentialStore *handlerfakes.FakeCredentialStore
+		fakePermissionStore *handlerfakes.FakePermissionStore
 		fakeTokenValidator  *handlerfakes.FakeTokenValidator
 		authServerURL       = "some-auth-server-url"
 	)
 
 	BeforeEach(func() {
 		fakeCredentialStore = &handlerfakes.FakeCredentialStore{}
+		fakePermissionStore = &handlerfakes.FakePermissionStore{}
+		fakePermissionStore.AllowedReturns(true)
 		fakeTokenValidator = &handlerfakes.FakeTokenValidator{}
 
 		var err error
-		credhubHandler, err = handler.NewCredhubHandler(authServerURL, fakeCredentialStore, fakeTokenValidator)
+		credhubHandler, err = handler.NewCredhubHandler(authServerURL, fakeCredentialStore, fakePermissionStore, fakeTokenValidator)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
 	Context("when 'name' is provided", func() {
-		It("gets a credential from the store by name", func() {
-			name := "some-name"
-			expectedCredential := credentials.Credential{
-				ID:               uuid.New(),
-				Name:             name,
-				Type:             "some-type",
-				Value:            "some-value",
-				VersionCreatedAt: time.Now().UTC(),
+		var expectedCredentials []credentials.Credential
+
+		BeforeEach(func() {
+			for _, value := range []string{"newest-value", "middle-value", "oldest-value"} {
+				expectedCredentials = append(expectedCredentials, credentials.Credential{
+					ID:               uuid.New(),
+					Name:             "some-name",
+					Type:             "some-type",
+					Value:            value,
+					VersionCreatedAt: time.Now().UTC(),
+				})
 			}
-			fakeCredentialStore.GetByNameReturns(expectedCredential, true)
+			fakeCredentialStore.GetVersionsByNameReturns(expectedCredentials)
+		})
+
+		It("gets every version of a credential from the store by name", func() {
+			name := "some-name"
 
 			responseRecorder := httptest.NewRecorder()
 			request := getDataByNameRequest(name, "some-token")
@@ -56,15 +69,62 @@ var _ = Describe("GetDataHandler", func() {
 				Data []credentials.Credential
 			}
 			Expect(json.Unmarshal(readBody(responseRecorder), &response)).To(Succeed())
-			Expect(response.Data).To(HaveLen(1))
-			Expect(response.Data[0]).To(Equal(expectedCredential))
+			Expect(response.Data).To(Equal(expectedCredentials))
 
-			Expect(fakeCredentialStore.GetByNameArgsForCall(0)).To(Equal(name))
+			Expect(fakeCredentialStore.GetVersionsByNameArgsForCall(0)).To(Equal(name))
+		})
+
+		It("limits the number of versions when 'versions' is provided", func() {
+			responseRecorder := httptest.NewRecorder()
+			request, err := http.NewRequest("GET", "/api/v1/data?name=some-name&versions=2", nil)
+			Expect(err).NotTo(HaveOccurred())
+			request.Header.Add("Authorization", "Bearer some-token")
+
+			credhubHandler.ServeHTTP(responseRecorder, request)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+			var response struct {
+				Data []credentials.Credential
+			}
+			Expect(json.Unmarshal(readBody(responseRecorder), &response)).To(Succeed())
+			Expect(response.Data).To(Equal(expectedCredentials[:2]))
+		})
+
+		It("returns only the newest version when 'current' is true", func() {
+			responseRecorder := httptest.NewRecorder()
+			request, err := http.NewRequest("GET", "/api/v1/data?name=some-name&current=true", nil)
+			Expect(err).NotTo(HaveOccurred())
+			request.Header.Add("Authorization", "Bearer some-token")
+
+			credhubHandler.ServeHTTP(responseRecorder, request)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+			var response struct {
+				Data []credentials.Credential
+			}
+			Expect(json.Unmarshal(readBody(responseRecorder), &response)).To(Succeed())
+			Expect(response.Data).To(Equal(expectedCredentials[:1]))
+		})
+
+		Context("when 'versions' is not a positive integer", func() {
+			It("responds with a 400", func() {
+				responseRecorder := httptest.NewRecorder()
+				request, err := http.NewRequest("GET", "/api/v1/data?name=some-name&versions=zero", nil)
+				Expect(err).NotTo(HaveOccurred())
+				request.Header.Add("Authorization", "Bearer some-token")
+
+				credhubHandler.ServeHTTP(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(readBody(responseRecorder)).To(MatchJSON(`{"error": "The query parameter versions must be a positive integer."}`))
+			})
 		})
 
 		Context("when the credential does not exist", func() {
 			It("responds with a 404", func() {
-				fakeCredentialStore.GetByNameReturns(credentials.Credential{}, false)
+				fakeCredentialStore.GetVersionsByNameReturns(nil)
 
 				responseRecorder := httptest.NewRecorder()
 				request := getDataByNameRequest("some-nonexistent-name", "some-token")
@@ -77,6 +137,63 @@ var _ = Describe("GetDataHandler", func() {
 		})
 	})
 
+	Context("when an ID is provided in the path", func() {
+		It("gets the credential version from the store by ID", func() {
+			expectedCredential := credentials.Credential{
+				ID:               uuid.New(),
+				Name:             "some-name",
+				Type:             "some-type",
+				Value:            "some-value",
+				VersionCreatedAt: time.Now().UTC(),
+			}
+			fakeCredentialStore.GetByIDReturns(expectedCredential, true)
+
+			responseRecorder := httptest.NewRecorder()
+			request, err := http.NewRequest("GET", "/api/v1/data/"+expectedCredential.ID.String(), nil)
+			Expect(err).NotTo(HaveOccurred())
+			request.Header.Add("Authorization", "Bearer some-token")
+
+			credhubHandler.ServeHTTP(responseRecorder, request)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+			var response credentials.Credential
+			Expect(json.Unmarshal(readBody(responseRecorder), &response)).To(Succeed())
+			Expect(response).To(Equal(expectedCredential))
+
+			Expect(fakeCredentialStore.GetByIDArgsForCall(0)).To(Equal(expectedCredential.ID))
+		})
+
+		Context("when the credential does not exist", func() {
+			It("responds with a 404", func() {
+				fakeCredentialStore.GetByIDReturns(credentials.Credential{}, false)
+
+				responseRecorder := httptest.NewRecorder()
+				request, err := http.NewRequest("GET", "/api/v1/data/"+uuid.New().String(), nil)
+				Expect(err).NotTo(HaveOccurred())
+				request.Header.Add("Authorization", "Bearer some-token")
+
+				credhubHandler.ServeHTTP(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the ID is not a UUID", func() {
+			It("responds with a 404", func() {
+				responseRecorder := httptest.NewRecorder()
+				request, err := http.NewRequest("GET", "/api/v1/data/some-non-uuid", nil)
+				Expect(err).NotTo(HaveOccurred())
+				request.Header.Add("Authorization", "Bearer some-token")
+
+				credhubHandler.ServeHTTP(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+				Expect(fakeCredentialStore.GetByIDCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 	Context("when 'path' is provided", func() {
 		It("gets credentials from the store by path", func() {
 			path := "some-path"
@@ -135,7 +252,7 @@ var _ = Describe("GetDataHandler", func() {
 
 			Expect(fakeCredentialStore.GetByPathCallCount()).To(Equal(1))
 			Expect(fakeCredentialStore.GetByPathArgsForCall(0)).To(Equal(path))
-			Expect(fakeCredentialStore.GetByNameCallCount()).To(Equal(0))
+			Expect(fakeCredentialStore.GetVersionsByNameCallCount()).To(Equal(0))
 		})
 	})
 
@@ -168,6 +285,37 @@ var _ = Describe("GetDataHandler", func() {
 		})
 	})
 
+	Context("when no Authorization header is set but a verified client certificate is presented", func() {
+		withClientCertificate := func(request *http.Request, organizationalUnits ...string) *http.Request {
+			certificate := &x509.Certificate{Subject: pkix.Name{OrganizationalUnit: organizationalUnits}}
+			request.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}}
+			return request
+		}
+
+		It("authenticates the app the certificate was issued to", func() {
+			fakeCredentialStore.GetVersionsByNameReturns([]credentials.Credential{{Name: "some-name"}})
+
+			responseRecorder := httptest.NewRecorder()
+			request := withClientCertificate(getDataByNameRequest("some-name", ""), "organization:some-org-guid", "app:some-app-guid")
+
+			credhubHandler.ServeHTTP(responseRecorder, request)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			actor, _, _ := fakePermissionStore.AllowedArgsForCall(0)
+			Expect(actor).To(Equal("mtls-app:some-app-guid"))
+			Expect(fakeTokenValidator.ValidateTokenWithClaimsCallCount()).To(Equal(0))
+		})
+
+		It("responds with a 401 when the certificate does not identify an app", func() {
+			responseRecorder := httptest.NewRecorder()
+			request := withClientCertificate(getDataByNameRequest("some-name", ""), "organization:some-org-guid")
+
+			credhubHandler.ServeHTTP(responseRecorder, request)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
 	Context("when token validation fails", func() {
 		It("responds with a 401", func() {
 			fakeTokenValidator.ValidateTokenWithClaimsReturns(errors.New("some-error"))
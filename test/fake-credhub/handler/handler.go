@@ -4,44 +4,73 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/mdelillo/credhub-fs/test/fake-credhub/credentials"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/permissions"
 )
 
 type credhubHandler struct {
 	authServerURL   string
 	credentialStore credentialStore
+	permissionStore permissionStore
 	tokenValidator  tokenValidator
+	faults          faultInjector
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . credentialStore
 type credentialStore interface {
 	GetByName(name string) (cred credentials.Credential, found bool)
+	GetByID(id uuid.UUID) (cred credentials.Credential, found bool)
+	GetVersionsByName(name string) []credentials.Credential
 	GetByPath(path string) []credentials.Credential
+	GetAll() []credentials.Credential
 	Set(credential credentials.Credential)
 	Delete(name string) bool
 }
 
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . permissionStore
+type permissionStore interface {
+	GetByID(id uuid.UUID) (permission permissions.Permission, found bool)
+	GetByPathAndActor(path, actor string) (permission permissions.Permission, found bool)
+	GetByPath(path string) []permissions.Permission
+	Set(permission permissions.Permission)
+	Delete(id uuid.UUID) bool
+	Allowed(actor, name, operation string) bool
+}
+
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . tokenValidator
 type tokenValidator interface {
 	ValidateTokenWithClaims(token string, claims map[string]string) error
 }
 
-func NewCredhubHandler(authServerURL string, credentialStore credentialStore, tokenValidator tokenValidator) (http.Handler, error) {
+func NewCredhubHandler(authServerURL string, credentialStore credentialStore, permissionStore permissionStore, tokenValidator tokenValidator) (http.Handler, error) {
 	h := &credhubHandler{
 		authServerURL:   authServerURL,
 		credentialStore: credentialStore,
+		permissionStore: permissionStore,
 		tokenValidator:  tokenValidator,
 	}
 
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.Default()
+	router.POST("/fake/faults", h.postFaultHandler)
+	router.Use(h.injectFaults)
 	router.GET("/info", h.infoHandler)
 
 	authenticationRequired := router.Group("/", h.authenticationRequired)
 	{
 		authenticationRequired.GET("/api/v1/data", h.getDataHandler)
+		authenticationRequired.GET("/api/v1/data/:id", h.getDataByIDHandler)
 		authenticationRequired.PUT("/api/v1/data", h.putDataHandler)
+		authenticationRequired.POST("/api/v1/data", h.postDataHandler)
 		authenticationRequired.DELETE("/api/v1/data", h.deleteDataHandler)
+		authenticationRequired.POST("/api/v1/regenerate", h.regenerateHandler)
+		authenticationRequired.POST("/api/v1/bulk-regenerate", h.bulkRegenerateHandler)
+		authenticationRequired.GET("/api/v1/permissions", h.getPermissionsV1Handler)
+		authenticationRequired.GET("/api/v2/permissions", h.getPermissionHandler)
+		authenticationRequired.POST("/api/v2/permissions", h.postPermissionHandler)
+		authenticationRequired.PUT("/api/v2/permissions/:id", h.putPermissionHandler)
+		authenticationRequired.DELETE("/api/v2/permissions/:id", h.deletePermissionHandler)
 	}
 
 	return router, nil
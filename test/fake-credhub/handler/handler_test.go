@@ -11,15 +11,25 @@ import (
 var _ = Describe("Handler", func() {
 	It("returns a handler that handles endpoints", func() {
 		authServerURL := "some-auth-server-url"
-		credhubHandler, err := handler.NewCredhubHandler(authServerURL, nil, nil)
+		credhubHandler, err := handler.NewCredhubHandler(authServerURL, nil, nil, nil)
 		Expect(err).NotTo(HaveOccurred())
 
 		routes := credhubHandler.(*gin.Engine).Routes()
 		Expect(routes).To(ConsistOf(
+			MatchFields(IgnoreExtras, Fields{"Path": Equal("/fake/faults"), "Method": Equal("POST")}),
 			MatchFields(IgnoreExtras, Fields{"Path": Equal("/info"), "Method": Equal("GET")}),
 			MatchFields(IgnoreExtras, Fields{"Path": Equal("/api/v1/data"), "Method": Equal("GET")}),
+			MatchFields(IgnoreExtras, Fields{"Path": Equal("/api/v1/data/:id"), "Method": Equal("GET")}),
 			MatchFields(IgnoreExtras, Fields{"Path": Equal("/api/v1/data"), "Method": Equal("PUT")}),
+			MatchFields(IgnoreExtras, Fields{"Path": Equal("/api/v1/data"), "Method": Equal("POST")}),
 			MatchFields(IgnoreExtras, Fields{"Path": Equal("/api/v1/data"), "Method": Equal("DELETE")}),
+			MatchFields(IgnoreExtras, Fields{"Path": Equal("/api/v1/regenerate"), "Method": Equal("POST")}),
+			MatchFields(IgnoreExtras, Fields{"Path": Equal("/api/v1/bulk-regenerate"), "Method": Equal("POST")}),
+			MatchFields(IgnoreExtras, Fields{"Path": Equal("/api/v1/permissions"), "Method": Equal("GET")}),
+			MatchFields(IgnoreExtras, Fields{"Path": Equal("/api/v2/permissions"), "Method": Equal("GET")}),
+			MatchFields(IgnoreExtras, Fields{"Path": Equal("/api/v2/permissions"), "Method": Equal("POST")}),
+			MatchFields(IgnoreExtras, Fields{"Path": Equal("/api/v2/permissions/:id"), "Method": Equal("PUT")}),
+			MatchFields(IgnoreExtras, Fields{"Path": Equal("/api/v2/permissions/:id"), "Method": Equal("DELETE")}),
 		))
 	})
 })
@@ -4,6 +4,7 @@ package handlerfakes
 import (
 	"sync"
 
+	"github.com/google/uuid"
 	"github.com/mdelillo/credhub-fs/test/fake-credhub/credentials"
 )
 
@@ -19,6 +20,29 @@ type FakeCredentialStore struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	GetAllStub        func() []credentials.Credential
+	getAllMutex       sync.RWMutex
+	getAllArgsForCall []struct {
+	}
+	getAllReturns struct {
+		result1 []credentials.Credential
+	}
+	getAllReturnsOnCall map[int]struct {
+		result1 []credentials.Credential
+	}
+	GetByIDStub        func(uuid.UUID) (credentials.Credential, bool)
+	getByIDMutex       sync.RWMutex
+	getByIDArgsForCall []struct {
+		arg1 uuid.UUID
+	}
+	getByIDReturns struct {
+		result1 credentials.Credential
+		result2 bool
+	}
+	getByIDReturnsOnCall map[int]struct {
+		result1 credentials.Credential
+		result2 bool
+	}
 	GetByNameStub        func(string) (credentials.Credential, bool)
 	getByNameMutex       sync.RWMutex
 	getByNameArgsForCall []struct {
@@ -43,6 +67,17 @@ type FakeCredentialStore struct {
 	getByPathReturnsOnCall map[int]struct {
 		result1 []credentials.Credential
 	}
+	GetVersionsByNameStub        func(string) []credentials.Credential
+	getVersionsByNameMutex       sync.RWMutex
+	getVersionsByNameArgsForCall []struct {
+		arg1 string
+	}
+	getVersionsByNameReturns struct {
+		result1 []credentials.Credential
+	}
+	getVersionsByNameReturnsOnCall map[int]struct {
+		result1 []credentials.Credential
+	}
 	SetStub        func(credentials.Credential)
 	setMutex       sync.RWMutex
 	setArgsForCall []struct {
@@ -112,6 +147,121 @@ func (fake *FakeCredentialStore) DeleteReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *FakeCredentialStore) GetAll() []credentials.Credential {
+	fake.getAllMutex.Lock()
+	ret, specificReturn := fake.getAllReturnsOnCall[len(fake.getAllArgsForCall)]
+	fake.getAllArgsForCall = append(fake.getAllArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GetAll", []interface{}{})
+	fake.getAllMutex.Unlock()
+	if fake.GetAllStub != nil {
+		return fake.GetAllStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.getAllReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeCredentialStore) GetAllCallCount() int {
+	fake.getAllMutex.RLock()
+	defer fake.getAllMutex.RUnlock()
+	return len(fake.getAllArgsForCall)
+}
+
+func (fake *FakeCredentialStore) GetAllCalls(stub func() []credentials.Credential) {
+	fake.getAllMutex.Lock()
+	defer fake.getAllMutex.Unlock()
+	fake.GetAllStub = stub
+}
+
+func (fake *FakeCredentialStore) GetAllReturns(result1 []credentials.Credential) {
+	fake.getAllMutex.Lock()
+	defer fake.getAllMutex.Unlock()
+	fake.GetAllStub = nil
+	fake.getAllReturns = struct {
+		result1 []credentials.Credential
+	}{result1}
+}
+
+func (fake *FakeCredentialStore) GetAllReturnsOnCall(i int, result1 []credentials.Credential) {
+	fake.getAllMutex.Lock()
+	defer fake.getAllMutex.Unlock()
+	fake.GetAllStub = nil
+	if fake.getAllReturnsOnCall == nil {
+		fake.getAllReturnsOnCall = make(map[int]struct {
+			result1 []credentials.Credential
+		})
+	}
+	fake.getAllReturnsOnCall[i] = struct {
+		result1 []credentials.Credential
+	}{result1}
+}
+
+func (fake *FakeCredentialStore) GetByID(arg1 uuid.UUID) (credentials.Credential, bool) {
+	fake.getByIDMutex.Lock()
+	ret, specificReturn := fake.getByIDReturnsOnCall[len(fake.getByIDArgsForCall)]
+	fake.getByIDArgsForCall = append(fake.getByIDArgsForCall, struct {
+		arg1 uuid.UUID
+	}{arg1})
+	fake.recordInvocation("GetByID", []interface{}{arg1})
+	fake.getByIDMutex.Unlock()
+	if fake.GetByIDStub != nil {
+		return fake.GetByIDStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getByIDReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCredentialStore) GetByIDCallCount() int {
+	fake.getByIDMutex.RLock()
+	defer fake.getByIDMutex.RUnlock()
+	return len(fake.getByIDArgsForCall)
+}
+
+func (fake *FakeCredentialStore) GetByIDCalls(stub func(uuid.UUID) (credentials.Credential, bool)) {
+	fake.getByIDMutex.Lock()
+	defer fake.getByIDMutex.Unlock()
+	fake.GetByIDStub = stub
+}
+
+func (fake *FakeCredentialStore) GetByIDArgsForCall(i int) uuid.UUID {
+	fake.getByIDMutex.RLock()
+	defer fake.getByIDMutex.RUnlock()
+	argsForCall := fake.getByIDArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeCredentialStore) GetByIDReturns(result1 credentials.Credential, result2 bool) {
+	fake.getByIDMutex.Lock()
+	defer fake.getByIDMutex.Unlock()
+	fake.GetByIDStub = nil
+	fake.getByIDReturns = struct {
+		result1 credentials.Credential
+		result2 bool
+	}{result1, result2}
+}
+
+func (fake *FakeCredentialStore) GetByIDReturnsOnCall(i int, result1 credentials.Credential, result2 bool) {
+	fake.getByIDMutex.Lock()
+	defer fake.getByIDMutex.Unlock()
+	fake.GetByIDStub = nil
+	if fake.getByIDReturnsOnCall == nil {
+		fake.getByIDReturnsOnCall = make(map[int]struct {
+			result1 credentials.Credential
+			result2 bool
+		})
+	}
+	fake.getByIDReturnsOnCall[i] = struct {
+		result1 credentials.Credential
+		result2 bool
+	}{result1, result2}
+}
+
 func (fake *FakeCredentialStore) GetByName(arg1 string) (credentials.Credential, bool) {
 	fake.getByNameMutex.Lock()
 	ret, specificReturn := fake.getByNameReturnsOnCall[len(fake.getByNameArgsForCall)]
@@ -235,6 +385,66 @@ func (fake *FakeCredentialStore) GetByPathReturnsOnCall(i int, result1 []credent
 	}{result1}
 }
 
+func (fake *FakeCredentialStore) GetVersionsByName(arg1 string) []credentials.Credential {
+	fake.getVersionsByNameMutex.Lock()
+	ret, specificReturn := fake.getVersionsByNameReturnsOnCall[len(fake.getVersionsByNameArgsForCall)]
+	fake.getVersionsByNameArgsForCall = append(fake.getVersionsByNameArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetVersionsByName", []interface{}{arg1})
+	fake.getVersionsByNameMutex.Unlock()
+	if fake.GetVersionsByNameStub != nil {
+		return fake.GetVersionsByNameStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.getVersionsByNameReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeCredentialStore) GetVersionsByNameCallCount() int {
+	fake.getVersionsByNameMutex.RLock()
+	defer fake.getVersionsByNameMutex.RUnlock()
+	return len(fake.getVersionsByNameArgsForCall)
+}
+
+func (fake *FakeCredentialStore) GetVersionsByNameCalls(stub func(string) []credentials.Credential) {
+	fake.getVersionsByNameMutex.Lock()
+	defer fake.getVersionsByNameMutex.Unlock()
+	fake.GetVersionsByNameStub = stub
+}
+
+func (fake *FakeCredentialStore) GetVersionsByNameArgsForCall(i int) string {
+	fake.getVersionsByNameMutex.RLock()
+	defer fake.getVersionsByNameMutex.RUnlock()
+	argsForCall := fake.getVersionsByNameArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeCredentialStore) GetVersionsByNameReturns(result1 []credentials.Credential) {
+	fake.getVersionsByNameMutex.Lock()
+	defer fake.getVersionsByNameMutex.Unlock()
+	fake.GetVersionsByNameStub = nil
+	fake.getVersionsByNameReturns = struct {
+		result1 []credentials.Credential
+	}{result1}
+}
+
+func (fake *FakeCredentialStore) GetVersionsByNameReturnsOnCall(i int, result1 []credentials.Credential) {
+	fake.getVersionsByNameMutex.Lock()
+	defer fake.getVersionsByNameMutex.Unlock()
+	fake.GetVersionsByNameStub = nil
+	if fake.getVersionsByNameReturnsOnCall == nil {
+		fake.getVersionsByNameReturnsOnCall = make(map[int]struct {
+			result1 []credentials.Credential
+		})
+	}
+	fake.getVersionsByNameReturnsOnCall[i] = struct {
+		result1 []credentials.Credential
+	}{result1}
+}
+
 func (fake *FakeCredentialStore) Set(arg1 credentials.Credential) {
 	fake.setMutex.Lock()
 	fake.setArgsForCall = append(fake.setArgsForCall, struct {
@@ -271,10 +481,16 @@ func (fake *FakeCredentialStore) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
+	fake.getAllMutex.RLock()
+	defer fake.getAllMutex.RUnlock()
+	fake.getByIDMutex.RLock()
+	defer fake.getByIDMutex.RUnlock()
 	fake.getByNameMutex.RLock()
 	defer fake.getByNameMutex.RUnlock()
 	fake.getByPathMutex.RLock()
 	defer fake.getByPathMutex.RUnlock()
+	fake.getVersionsByNameMutex.RLock()
+	defer fake.getVersionsByNameMutex.RUnlock()
 	fake.setMutex.RLock()
 	defer fake.setMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -0,0 +1,455 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package handlerfakes
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/permissions"
+)
+
+type FakePermissionStore struct {
+	AllowedStub        func(string, string, string) bool
+	allowedMutex       sync.RWMutex
+	allowedArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	allowedReturns struct {
+		result1 bool
+	}
+	allowedReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	DeleteStub        func(uuid.UUID) bool
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		arg1 uuid.UUID
+	}
+	deleteReturns struct {
+		result1 bool
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	GetByIDStub        func(uuid.UUID) (permissions.Permission, bool)
+	getByIDMutex       sync.RWMutex
+	getByIDArgsForCall []struct {
+		arg1 uuid.UUID
+	}
+	getByIDReturns struct {
+		result1 permissions.Permission
+		result2 bool
+	}
+	getByIDReturnsOnCall map[int]struct {
+		result1 permissions.Permission
+		result2 bool
+	}
+	GetByPathStub        func(string) []permissions.Permission
+	getByPathMutex       sync.RWMutex
+	getByPathArgsForCall []struct {
+		arg1 string
+	}
+	getByPathReturns struct {
+		result1 []permissions.Permission
+	}
+	getByPathReturnsOnCall map[int]struct {
+		result1 []permissions.Permission
+	}
+	GetByPathAndActorStub        func(string, string) (permissions.Permission, bool)
+	getByPathAndActorMutex       sync.RWMutex
+	getByPathAndActorArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getByPathAndActorReturns struct {
+		result1 permissions.Permission
+		result2 bool
+	}
+	getByPathAndActorReturnsOnCall map[int]struct {
+		result1 permissions.Permission
+		result2 bool
+	}
+	SetStub        func(permissions.Permission)
+	setMutex       sync.RWMutex
+	setArgsForCall []struct {
+		arg1 permissions.Permission
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakePermissionStore) Allowed(arg1 string, arg2 string, arg3 string) bool {
+	fake.allowedMutex.Lock()
+	ret, specificReturn := fake.allowedReturnsOnCall[len(fake.allowedArgsForCall)]
+	fake.allowedArgsForCall = append(fake.allowedArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("Allowed", []interface{}{arg1, arg2, arg3})
+	fake.allowedMutex.Unlock()
+	if fake.AllowedStub != nil {
+		return fake.AllowedStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.allowedReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePermissionStore) AllowedCallCount() int {
+	fake.allowedMutex.RLock()
+	defer fake.allowedMutex.RUnlock()
+	return len(fake.allowedArgsForCall)
+}
+
+func (fake *FakePermissionStore) AllowedCalls(stub func(string, string, string) bool) {
+	fake.allowedMutex.Lock()
+	defer fake.allowedMutex.Unlock()
+	fake.AllowedStub = stub
+}
+
+func (fake *FakePermissionStore) AllowedArgsForCall(i int) (string, string, string) {
+	fake.allowedMutex.RLock()
+	defer fake.allowedMutex.RUnlock()
+	argsForCall := fake.allowedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePermissionStore) AllowedReturns(result1 bool) {
+	fake.allowedMutex.Lock()
+	defer fake.allowedMutex.Unlock()
+	fake.AllowedStub = nil
+	fake.allowedReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakePermissionStore) AllowedReturnsOnCall(i int, result1 bool) {
+	fake.allowedMutex.Lock()
+	defer fake.allowedMutex.Unlock()
+	fake.AllowedStub = nil
+	if fake.allowedReturnsOnCall == nil {
+		fake.allowedReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.allowedReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakePermissionStore) Delete(arg1 uuid.UUID) bool {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 uuid.UUID
+	}{arg1})
+	fake.recordInvocation("Delete", []interface{}{arg1})
+	fake.deleteMutex.Unlock()
+	if fake.DeleteStub != nil {
+		return fake.DeleteStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePermissionStore) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakePermissionStore) DeleteCalls(stub func(uuid.UUID) bool) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
+func (fake *FakePermissionStore) DeleteArgsForCall(i int) uuid.UUID {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	argsForCall := fake.deleteArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePermissionStore) DeleteReturns(result1 bool) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakePermissionStore) DeleteReturnsOnCall(i int, result1 bool) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakePermissionStore) GetByID(arg1 uuid.UUID) (permissions.Permission, bool) {
+	fake.getByIDMutex.Lock()
+	ret, specificReturn := fake.getByIDReturnsOnCall[len(fake.getByIDArgsForCall)]
+	fake.getByIDArgsForCall = append(fake.getByIDArgsForCall, struct {
+		arg1 uuid.UUID
+	}{arg1})
+	fake.recordInvocation("GetByID", []interface{}{arg1})
+	fake.getByIDMutex.Unlock()
+	if fake.GetByIDStub != nil {
+		return fake.GetByIDStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getByIDReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePermissionStore) GetByIDCallCount() int {
+	fake.getByIDMutex.RLock()
+	defer fake.getByIDMutex.RUnlock()
+	return len(fake.getByIDArgsForCall)
+}
+
+func (fake *FakePermissionStore) GetByIDCalls(stub func(uuid.UUID) (permissions.Permission, bool)) {
+	fake.getByIDMutex.Lock()
+	defer fake.getByIDMutex.Unlock()
+	fake.GetByIDStub = stub
+}
+
+func (fake *FakePermissionStore) GetByIDArgsForCall(i int) uuid.UUID {
+	fake.getByIDMutex.RLock()
+	defer fake.getByIDMutex.RUnlock()
+	argsForCall := fake.getByIDArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePermissionStore) GetByIDReturns(result1 permissions.Permission, result2 bool) {
+	fake.getByIDMutex.Lock()
+	defer fake.getByIDMutex.Unlock()
+	fake.GetByIDStub = nil
+	fake.getByIDReturns = struct {
+		result1 permissions.Permission
+		result2 bool
+	}{result1, result2}
+}
+
+func (fake *FakePermissionStore) GetByIDReturnsOnCall(i int, result1 permissions.Permission, result2 bool) {
+	fake.getByIDMutex.Lock()
+	defer fake.getByIDMutex.Unlock()
+	fake.GetByIDStub = nil
+	if fake.getByIDReturnsOnCall == nil {
+		fake.getByIDReturnsOnCall = make(map[int]struct {
+			result1 permissions.Permission
+			result2 bool
+		})
+	}
+	fake.getByIDReturnsOnCall[i] = struct {
+		result1 permissions.Permission
+		result2 bool
+	}{result1, result2}
+}
+
+func (fake *FakePermissionStore) GetByPath(arg1 string) []permissions.Permission {
+	fake.getByPathMutex.Lock()
+	ret, specificReturn := fake.getByPathReturnsOnCall[len(fake.getByPathArgsForCall)]
+	fake.getByPathArgsForCall = append(fake.getByPathArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetByPath", []interface{}{arg1})
+	fake.getByPathMutex.Unlock()
+	if fake.GetByPathStub != nil {
+		return fake.GetByPathStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.getByPathReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePermissionStore) GetByPathCallCount() int {
+	fake.getByPathMutex.RLock()
+	defer fake.getByPathMutex.RUnlock()
+	return len(fake.getByPathArgsForCall)
+}
+
+func (fake *FakePermissionStore) GetByPathCalls(stub func(string) []permissions.Permission) {
+	fake.getByPathMutex.Lock()
+	defer fake.getByPathMutex.Unlock()
+	fake.GetByPathStub = stub
+}
+
+func (fake *FakePermissionStore) GetByPathArgsForCall(i int) string {
+	fake.getByPathMutex.RLock()
+	defer fake.getByPathMutex.RUnlock()
+	argsForCall := fake.getByPathArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePermissionStore) GetByPathReturns(result1 []permissions.Permission) {
+	fake.getByPathMutex.Lock()
+	defer fake.getByPathMutex.Unlock()
+	fake.GetByPathStub = nil
+	fake.getByPathReturns = struct {
+		result1 []permissions.Permission
+	}{result1}
+}
+
+func (fake *FakePermissionStore) GetByPathReturnsOnCall(i int, result1 []permissions.Permission) {
+	fake.getByPathMutex.Lock()
+	defer fake.getByPathMutex.Unlock()
+	fake.GetByPathStub = nil
+	if fake.getByPathReturnsOnCall == nil {
+		fake.getByPathReturnsOnCall = make(map[int]struct {
+			result1 []permissions.Permission
+		})
+	}
+	fake.getByPathReturnsOnCall[i] = struct {
+		result1 []permissions.Permission
+	}{result1}
+}
+
+func (fake *FakePermissionStore) GetByPathAndActor(arg1 string, arg2 string) (permissions.Permission, bool) {
+	fake.getByPathAndActorMutex.Lock()
+	ret, specificReturn := fake.getByPathAndActorReturnsOnCall[len(fake.getByPathAndActorArgsForCall)]
+	fake.getByPathAndActorArgsForCall = append(fake.getByPathAndActorArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("GetByPathAndActor", []interface{}{arg1, arg2})
+	fake.getByPathAndActorMutex.Unlock()
+	if fake.GetByPathAndActorStub != nil {
+		return fake.GetByPathAndActorStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getByPathAndActorReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePermissionStore) GetByPathAndActorCallCount() int {
+	fake.getByPathAndActorMutex.RLock()
+	defer fake.getByPathAndActorMutex.RUnlock()
+	return len(fake.getByPathAndActorArgsForCall)
+}
+
+func (fake *FakePermissionStore) GetByPathAndActorCalls(stub func(string, string) (permissions.Permission, bool)) {
+	fake.getByPathAndActorMutex.Lock()
+	defer fake.getByPathAndActorMutex.Unlock()
+	fake.GetByPathAndActorStub = stub
+}
+
+func (fake *FakePermissionStore) GetByPathAndActorArgsForCall(i int) (string, string) {
+	fake.getByPathAndActorMutex.RLock()
+	defer fake.getByPathAndActorMutex.RUnlock()
+	argsForCall := fake.getByPathAndActorArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePermissionStore) GetByPathAndActorReturns(result1 permissions.Permission, result2 bool) {
+	fake.getByPathAndActorMutex.Lock()
+	defer fake.getByPathAndActorMutex.Unlock()
+	fake.GetByPathAndActorStub = nil
+	fake.getByPathAndActorReturns = struct {
+		result1 permissions.Permission
+		result2 bool
+	}{result1, result2}
+}
+
+func (fake *FakePermissionStore) GetByPathAndActorReturnsOnCall(i int, result1 permissions.Permission, result2 bool) {
+	fake.getByPathAndActorMutex.Lock()
+	defer fake.getByPathAndActorMutex.Unlock()
+	fake.GetByPathAndActorStub = nil
+	if fake.getByPathAndActorReturnsOnCall == nil {
+		fake.getByPathAndActorReturnsOnCall = make(map[int]struct {
+			result1 permissions.Permission
+			result2 bool
+		})
+	}
+	fake.getByPathAndActorReturnsOnCall[i] = struct {
+		result1 permissions.Permission
+		result2 bool
+	}{result1, result2}
+}
+
+func (fake *FakePermissionStore) Set(arg1 permissions.Permission) {
+	fake.setMutex.Lock()
+	fake.setArgsForCall = append(fake.setArgsForCall, struct {
+		arg1 permissions.Permission
+	}{arg1})
+	fake.recordInvocation("Set", []interface{}{arg1})
+	fake.setMutex.Unlock()
+	if fake.SetStub != nil {
+		fake.SetStub(arg1)
+	}
+}
+
+func (fake *FakePermissionStore) SetCallCount() int {
+	fake.setMutex.RLock()
+	defer fake.setMutex.RUnlock()
+	return len(fake.setArgsForCall)
+}
+
+func (fake *FakePermissionStore) SetCalls(stub func(permissions.Permission)) {
+	fake.setMutex.Lock()
+	defer fake.setMutex.Unlock()
+	fake.SetStub = stub
+}
+
+func (fake *FakePermissionStore) SetArgsForCall(i int) permissions.Permission {
+	fake.setMutex.RLock()
+	defer fake.setMutex.RUnlock()
+	argsForCall := fake.setArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePermissionStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.allowedMutex.RLock()
+	defer fake.allowedMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	fake.getByIDMutex.RLock()
+	defer fake.getByIDMutex.RUnlock()
+	fake.getByPathMutex.RLock()
+	defer fake.getByPathMutex.RUnlock()
+	fake.getByPathAndActorMutex.RLock()
+	defer fake.getByPathAndActorMutex.RUnlock()
+	fake.setMutex.RLock()
+	defer fake.setMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakePermissionStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -13,7 +13,7 @@ import (
 var _ = Describe("InfoHandler", func() {
 	It("responds with 200 and the auth-server URL and app name", func() {
 		authServerURL := "some-auth-server-url"
-		credhubHandler, err := handler.NewCredhubHandler(authServerURL, nil, nil)
+		credhubHandler, err := handler.NewCredhubHandler(authServerURL, nil, nil, nil)
 		Expect(err).NotTo(HaveOccurred())
 
 		responseRecorder := httptest.NewRecorder()
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/permissions"
+)
+
+type permissionRequestBody struct {
+	Actor      string   `json:"actor" binding:"required"`
+	Path       string   `json:"path" binding:"required"`
+	Operations []string `json:"operations" binding:"required"`
+}
+
+func (h *credhubHandler) getPermissionsV1Handler(c *gin.Context) {
+	name := c.Query("credential_name")
+	if name == "" {
+		c.JSON(400, gin.H{
+			"error": ErrMissingCredentialNameParameter,
+		})
+		return
+	}
+
+	// Like CredHub, the v1 API only lists permissions on an existing
+	// credential, so it cannot be used with a wildcard path.
+	if _, found := h.credentialStore.GetByName(name); !found || !h.authorized(c, name, permissions.OperationReadACL) {
+		c.JSON(404, gin.H{
+			"error": ErrCredentialDoesNotExist,
+		})
+		return
+	}
+
+	actorPermissions := []permissions.ActorPermission{}
+	for _, permission := range h.permissionStore.GetByPath(name) {
+		actorPermissions = append(actorPermissions, permissions.ActorPermission{
+			Actor:      permission.Actor,
+			Operations: permission.Operations,
+		})
+	}
+	sort.Slice(actorPermissions, func(i, j int) bool {
+		return actorPermissions[i].Actor < actorPermissions[j].Actor
+	})
+
+	c.JSON(200, gin.H{
+		"credential_name": name,
+		"permissions":     actorPermissions,
+	})
+}
+
+func (h *credhubHandler) getPermissionHandler(c *gin.Context) {
+	path := c.Query("path")
+	actor := c.Query("actor")
+	if path == "" || actor == "" {
+		c.JSON(400, gin.H{
+			"error": ErrMissingPathAndActorParameters,
+		})
+		return
+	}
+
+	permission, found := h.permissionStore.GetByPathAndActor(path, actor)
+	if !found || !h.authorized(c, path, permissions.OperationReadACL) {
+		c.JSON(404, gin.H{
+			"error": ErrPermissionDoesNotExist,
+		})
+		return
+	}
+
+	c.JSON(200, permission)
+}
+
+func (h *credhubHandler) postPermissionHandler(c *gin.Context) {
+	var requestBody permissionRequestBody
+	if !bindPermissionRequest(c, &requestBody) {
+		return
+	}
+
+	if !h.authorized(c, requestBody.Path, permissions.OperationWriteACL) {
+		c.JSON(403, gin.H{
+			"error": ErrPermissionDoesNotExist,
+		})
+		return
+	}
+
+	if _, exists := h.permissionStore.GetByPathAndActor(requestBody.Path, requestBody.Actor); exists {
+		c.JSON(409, gin.H{
+			"error": ErrPermissionAlreadyExists,
+		})
+		return
+	}
+
+	permission := permissions.Permission{
+		UUID:       uuid.New(),
+		Actor:      requestBody.Actor,
+		Path:       requestBody.Path,
+		Operations: requestBody.Operations,
+	}
+	h.permissionStore.Set(permission)
+
+	c.JSON(201, permission)
+}
+
+func (h *credhubHandler) putPermissionHandler(c *gin.Context) {
+	existing, found := h.permissionByIDParam(c)
+	if !found {
+		return
+	}
+
+	var requestBody permissionRequestBody
+	if !bindPermissionRequest(c, &requestBody) {
+		return
+	}
+
+	if !h.authorized(c, existing.Path, permissions.OperationWriteACL) ||
+		!h.authorized(c, requestBody.Path, permissions.OperationWriteACL) {
+		c.JSON(403, gin.H{
+			"error": ErrPermissionDoesNotExist,
+		})
+		return
+	}
+
+	if other, exists := h.permissionStore.GetByPathAndActor(requestBody.Path, requestBody.Actor); exists && other.UUID != existing.UUID {
+		c.JSON(409, gin.H{
+			"error": ErrPermissionAlreadyExists,
+		})
+		return
+	}
+
+	permission := permissions.Permission{
+		UUID:       existing.UUID,
+		Actor:      requestBody.Actor,
+		Path:       requestBody.Path,
+		Operations: requestBody.Operations,
+	}
+	h.permissionStore.Set(permission)
+
+	c.JSON(200, permission)
+}
+
+func (h *credhubHandler) deletePermissionHandler(c *gin.Context) {
+	permission, found := h.permissionByIDParam(c)
+	if !found {
+		return
+	}
+
+	if !h.authorized(c, permission.Path, permissions.OperationWriteACL) {
+		c.JSON(403, gin.H{
+			"error": ErrPermissionDoesNotExist,
+		})
+		return
+	}
+
+	h.permissionStore.Delete(permission.UUID)
+
+	c.JSON(200, permission)
+}
+
+// permissionByIDParam looks up the permission identified in the request path,
+// responding with a 404 if there is no such permission.
+func (h *credhubHandler) permissionByIDParam(c *gin.Context) (permissions.Permission, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err == nil {
+		if permission, found := h.permissionStore.GetByID(id); found {
+			return permission, true
+		}
+	}
+
+	c.JSON(404, gin.H{
+		"error": ErrPermissionDoesNotExist,
+	})
+	return permissions.Permission{}, false
+}
+
+// bindPermissionRequest parses and validates a permission request body,
+// responding with a 400 if it is invalid.
+func bindPermissionRequest(c *gin.Context, requestBody *permissionRequestBody) bool {
+	if err := c.ShouldBindJSON(requestBody); err != nil {
+		c.JSON(400, gin.H{
+			"error": ErrInvalidPathOrBody,
+		})
+		return false
+	}
+
+	if len(requestBody.Operations) == 0 {
+		c.JSON(400, gin.H{
+			"error": ErrInvalidOperation,
+		})
+		return false
+	}
+	for _, operation := range requestBody.Operations {
+		if !validOperation(operation) {
+			c.JSON(400, gin.H{
+				"error": ErrInvalidOperation,
+			})
+			return false
+		}
+	}
+
+	return true
+}
+
+func validOperation(operation string) bool {
+	for _, validOperation := range permissions.Operations {
+		if operation == validOperation {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,319 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/credentials"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/handler"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/handler/handlerfakes"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/permissions"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PermissionsHandler", func() {
+	var (
+		credhubHandler      http.Handler
+		fakeCredentialStore *handlerfakes.FakeCredentialStore
+		fakePermissionStore *handlerfakes.FakePermissionStore
+		fakeTokenValidator  *handlerfakes.FakeTokenValidator
+		authServerURL       = "some-auth-server-url"
+		token               string
+	)
+
+	BeforeEach(func() {
+		fakeCredentialStore = &handlerfakes.FakeCredentialStore{}
+		fakePermissionStore = &handlerfakes.FakePermissionStore{}
+		fakePermissionStore.AllowedReturns(true)
+		fakeTokenValidator = &handlerfakes.FakeTokenValidator{}
+
+		var err error
+		credhubHandler, err = handler.NewCredhubHandler(authServerURL, fakeCredentialStore, fakePermissionStore, fakeTokenValidator)
+		Expect(err).NotTo(HaveOccurred())
+
+		token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"client_id": "some-client"}).SignedString([]byte("some-key"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		request, err := http.NewRequest(method, path, strings.NewReader(body))
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		request.Header.Add("Authorization", "Bearer "+token)
+
+		responseRecorder := httptest.NewRecorder()
+		credhubHandler.ServeHTTP(responseRecorder, request)
+		return responseRecorder
+	}
+
+	Describe("GET /api/v1/permissions", func() {
+		BeforeEach(func() {
+			fakeCredentialStore.GetByNameReturns(credentials.Credential{Name: "/some/path"}, true)
+		})
+
+		It("lists the permissions on the path, sorted by actor", func() {
+			fakePermissionStore.GetByPathReturns([]permissions.Permission{
+				{UUID: uuid.New(), Actor: "uaa-client:b", Path: "/some/path", Operations: []string{"read"}},
+				{UUID: uuid.New(), Actor: "uaa-client:a", Path: "/some/path", Operations: []string{"read", "write"}},
+			})
+
+			responseRecorder := serve("GET", "/api/v1/permissions?credential_name=/some/path", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(readBody(responseRecorder)).To(MatchJSON(`{
+				"credential_name": "/some/path",
+				"permissions": [
+					{"actor": "uaa-client:a", "operations": ["read", "write"]},
+					{"actor": "uaa-client:b", "operations": ["read"]}
+				]
+			}`))
+			Expect(fakePermissionStore.GetByPathArgsForCall(0)).To(Equal("/some/path"))
+
+			actor, name, operation := fakePermissionStore.AllowedArgsForCall(0)
+			Expect(actor).To(Equal("uaa-client:some-client"))
+			Expect(name).To(Equal("/some/path"))
+			Expect(operation).To(Equal("read_acl"))
+		})
+
+		It("identifies users by their user ID", func() {
+			var err error
+			token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+				"client_id": "credhub_cli",
+				"user_id":   "some-user-id",
+			}).SignedString([]byte("some-key"))
+			Expect(err).NotTo(HaveOccurred())
+
+			serve("GET", "/api/v1/permissions?credential_name=/some/path", "")
+
+			actor, _, _ := fakePermissionStore.AllowedArgsForCall(0)
+			Expect(actor).To(Equal("uaa-user:some-user-id"))
+		})
+
+		It("responds with an empty list when there are no permissions", func() {
+			responseRecorder := serve("GET", "/api/v1/permissions?credential_name=/some/path", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(readBody(responseRecorder)).To(MatchJSON(`{"credential_name": "/some/path", "permissions": []}`))
+		})
+
+		Context("when the credential does not exist", func() {
+			It("responds with a 404", func() {
+				fakeCredentialStore.GetByNameReturns(credentials.Credential{}, false)
+
+				responseRecorder := serve("GET", "/api/v1/permissions?credential_name=/some/*", "")
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+				Expect(fakeCredentialStore.GetByNameArgsForCall(0)).To(Equal("/some/*"))
+				Expect(fakePermissionStore.GetByPathCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the actor may not read the ACL", func() {
+			It("responds with a 404", func() {
+				fakePermissionStore.AllowedReturns(false)
+
+				responseRecorder := serve("GET", "/api/v1/permissions?credential_name=/some/path", "")
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+				Expect(fakePermissionStore.GetByPathCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when credential_name is not provided", func() {
+			It("responds with a 400", func() {
+				responseRecorder := serve("GET", "/api/v1/permissions", "")
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(readBody(responseRecorder)).To(MatchJSON(fmt.Sprintf(`{"error": %q}`, handler.ErrMissingCredentialNameParameter)))
+			})
+		})
+	})
+
+	Describe("GET /api/v2/permissions", func() {
+		It("gets the permission for the path and actor", func() {
+			permission := permissions.Permission{UUID: uuid.New(), Actor: "some-actor", Path: "/some/*", Operations: []string{"read"}}
+			fakePermissionStore.GetByPathAndActorReturns(permission, true)
+
+			responseRecorder := serve("GET", "/api/v2/permissions?path=/some/*&actor=some-actor", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			var response permissions.Permission
+			Expect(json.Unmarshal(readBody(responseRecorder), &response)).To(Succeed())
+			Expect(response).To(Equal(permission))
+
+			path, actor := fakePermissionStore.GetByPathAndActorArgsForCall(0)
+			Expect(path).To(Equal("/some/*"))
+			Expect(actor).To(Equal("some-actor"))
+		})
+
+		Context("when the permission does not exist", func() {
+			It("responds with a 404", func() {
+				responseRecorder := serve("GET", "/api/v2/permissions?path=/some/path&actor=some-actor", "")
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+				Expect(readBody(responseRecorder)).To(MatchJSON(fmt.Sprintf(`{"error": %q}`, handler.ErrPermissionDoesNotExist)))
+			})
+		})
+
+		Context("when path or actor is not provided", func() {
+			It("responds with a 400", func() {
+				responseRecorder := serve("GET", "/api/v2/permissions?path=/some/path", "")
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(readBody(responseRecorder)).To(MatchJSON(fmt.Sprintf(`{"error": %q}`, handler.ErrMissingPathAndActorParameters)))
+			})
+		})
+	})
+
+	Describe("POST /api/v2/permissions", func() {
+		It("creates the permission", func() {
+			responseRecorder := serve("POST", "/api/v2/permissions", `{"actor": "some-actor", "path": "/some/*", "operations": ["read", "write_acl"]}`)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusCreated))
+
+			permission := fakePermissionStore.SetArgsForCall(0)
+			Expect(permission.UUID).NotTo(Equal(uuid.Nil))
+			Expect(permission.Actor).To(Equal("some-actor"))
+			Expect(permission.Path).To(Equal("/some/*"))
+			Expect(permission.Operations).To(Equal([]string{"read", "write_acl"}))
+
+			var response permissions.Permission
+			Expect(json.Unmarshal(readBody(responseRecorder), &response)).To(Succeed())
+			Expect(response).To(Equal(permission))
+
+			_, name, operation := fakePermissionStore.AllowedArgsForCall(0)
+			Expect(name).To(Equal("/some/*"))
+			Expect(operation).To(Equal("write_acl"))
+		})
+
+		Context("when the permission already exists", func() {
+			It("responds with a 409", func() {
+				fakePermissionStore.GetByPathAndActorReturns(permissions.Permission{UUID: uuid.New()}, true)
+
+				responseRecorder := serve("POST", "/api/v2/permissions", `{"actor": "some-actor", "path": "/some/path", "operations": ["read"]}`)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusConflict))
+				Expect(readBody(responseRecorder)).To(MatchJSON(fmt.Sprintf(`{"error": %q}`, handler.ErrPermissionAlreadyExists)))
+				Expect(fakePermissionStore.SetCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when an operation is not supported", func() {
+			It("responds with a 400", func() {
+				responseRecorder := serve("POST", "/api/v2/permissions", `{"actor": "some-actor", "path": "/some/path", "operations": ["read", "execute"]}`)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(readBody(responseRecorder)).To(MatchJSON(fmt.Sprintf(`{"error": %q}`, handler.ErrInvalidOperation)))
+			})
+		})
+
+		Context("when the actor may not write the ACL", func() {
+			It("responds with a 403", func() {
+				fakePermissionStore.AllowedReturns(false)
+
+				responseRecorder := serve("POST", "/api/v2/permissions", `{"actor": "some-actor", "path": "/some/path", "operations": ["read"]}`)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusForbidden))
+				Expect(fakePermissionStore.SetCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("PUT /api/v2/permissions/:id", func() {
+		It("replaces the permission", func() {
+			existing := permissions.Permission{UUID: uuid.New(), Actor: "some-actor", Path: "/some/path", Operations: []string{"read"}}
+			fakePermissionStore.GetByIDReturns(existing, true)
+
+			responseRecorder := serve("PUT", "/api/v2/permissions/"+existing.UUID.String(), `{"actor": "some-actor", "path": "/some/path", "operations": ["read", "write"]}`)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(fakePermissionStore.GetByIDArgsForCall(0)).To(Equal(existing.UUID))
+			Expect(fakePermissionStore.SetArgsForCall(0)).To(Equal(permissions.Permission{
+				UUID:       existing.UUID,
+				Actor:      "some-actor",
+				Path:       "/some/path",
+				Operations: []string{"read", "write"},
+			}))
+		})
+
+		Context("when the permission does not exist", func() {
+			It("responds with a 404", func() {
+				responseRecorder := serve("PUT", "/api/v2/permissions/"+uuid.New().String(), `{"actor": "some-actor", "path": "/some/path", "operations": ["read"]}`)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+				Expect(fakePermissionStore.SetCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("DELETE /api/v2/permissions/:id", func() {
+		It("deletes the permission and responds with it", func() {
+			existing := permissions.Permission{UUID: uuid.New(), Actor: "some-actor", Path: "/some/path", Operations: []string{"read"}}
+			fakePermissionStore.GetByIDReturns(existing, true)
+
+			responseRecorder := serve("DELETE", "/api/v2/permissions/"+existing.UUID.String(), "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(fakePermissionStore.DeleteArgsForCall(0)).To(Equal(existing.UUID))
+
+			var response permissions.Permission
+			Expect(json.Unmarshal(readBody(responseRecorder), &response)).To(Succeed())
+			Expect(response).To(Equal(existing))
+		})
+
+		Context("when the actor may not write the ACL", func() {
+			It("responds with a 403", func() {
+				fakePermissionStore.GetByIDReturns(permissions.Permission{UUID: uuid.New(), Path: "/some/path"}, true)
+				fakePermissionStore.AllowedReturns(false)
+
+				responseRecorder := serve("DELETE", "/api/v2/permissions/"+uuid.New().String(), "")
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusForbidden))
+				Expect(fakePermissionStore.DeleteCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the ID is not a UUID", func() {
+			It("responds with a 404", func() {
+				responseRecorder := serve("DELETE", "/api/v2/permissions/some-non-uuid", "")
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+				Expect(fakePermissionStore.GetByIDCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("enforcing permissions on credentials", func() {
+		BeforeEach(func() {
+			fakePermissionStore.AllowedStub = func(actor, name, operation string) bool {
+				return actor == "uaa-client:some-client" && name == "/allowed" && operation == "read"
+			}
+			cred := credentials.Credential{Name: "/allowed", Value: "some-value"}
+			fakeCredentialStore.GetVersionsByNameReturns([]credentials.Credential{cred})
+			fakeCredentialStore.GetByPathReturns([]credentials.Credential{cred, {Name: "/denied"}})
+		})
+
+		It("hides credentials the actor may not read", func() {
+			Expect(serve("GET", "/api/v1/data?name=/allowed", "").Code).To(Equal(http.StatusOK))
+			Expect(serve("GET", "/api/v1/data?name=/denied", "").Code).To(Equal(http.StatusNotFound))
+
+			responseRecorder := serve("GET", "/api/v1/data?path=/", "")
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(string(readBody(responseRecorder))).NotTo(ContainSubstring("/denied"))
+		})
+
+		It("forbids writing and deleting without permission", func() {
+			Expect(serve("PUT", "/api/v1/data", `{"name": "/allowed", "type": "value", "value": "some-value"}`).Code).To(Equal(http.StatusForbidden))
+			Expect(serve("POST", "/api/v1/data", `{"name": "/allowed", "type": "password"}`).Code).To(Equal(http.StatusForbidden))
+			Expect(serve("DELETE", "/api/v1/data?name=/allowed", "").Code).To(Equal(http.StatusForbidden))
+
+			Expect(fakeCredentialStore.SetCallCount()).To(Equal(0))
+			Expect(fakeCredentialStore.DeleteCallCount()).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/credentials"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/permissions"
+)
+
+func (h *credhubHandler) postDataHandler(c *gin.Context) {
+	var requestBody struct {
+		Name       string          `json:"name" binding:"required"`
+		Type       string          `json:"type" binding:"required"`
+		Parameters json.RawMessage `json:"parameters"`
+		Mode       string          `json:"mode"`
+	}
+
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(400, gin.H{
+			"error": ErrInvalidPathOrBody,
+		})
+		return
+	}
+
+	switch requestBody.Mode {
+	case "", "overwrite", "no-overwrite":
+	default:
+		c.JSON(400, gin.H{
+			"error": ErrInvalidMode,
+		})
+		return
+	}
+
+	if _, supported := credentialTypes[requestBody.Type]; !supported {
+		c.JSON(400, gin.H{
+			"error": ErrInvalidType,
+		})
+		return
+	}
+
+	if !h.authorized(c, requestBody.Name, permissions.OperationWrite) {
+		c.JSON(403, gin.H{
+			"error": ErrCredentialDoesNotExist,
+		})
+		return
+	}
+
+	if requestBody.Mode == "no-overwrite" {
+		if existingCred, found := h.credentialStore.GetByName(requestBody.Name); found {
+			c.JSON(200, existingCred)
+			return
+		}
+	}
+
+	parameters := requestBody.Parameters
+	if len(parameters) == 0 || string(parameters) == "null" {
+		parameters = json.RawMessage("{}")
+	}
+
+	value, err := h.generate(requestBody.Type, parameters)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	createdCred := credentials.Credential{
+		ID:                   uuid.New(),
+		VersionCreatedAt:     time.Now().UTC(),
+		Name:                 requestBody.Name,
+		Value:                value,
+		Type:                 requestBody.Type,
+		GenerationParameters: parameters,
+	}
+
+	h.credentialStore.Set(createdCred)
+
+	c.JSON(200, createdCred)
+}
@@ -6,13 +6,15 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/mdelillo/credhub-fs/test/fake-credhub/credentials"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/permissions"
 )
 
 func (h *credhubHandler) putDataHandler(c *gin.Context) {
 	var requestBody struct {
-		Name  string `json:"name" binding:"required"`
-		Type  string `json:"type" binding:"required"`
-		Value string `json:"value" binding:"required"`
+		Name     string                 `json:"name" binding:"required"`
+		Type     string                 `json:"type" binding:"required"`
+		Value    interface{}            `json:"value" binding:"required"`
+		Metadata map[string]interface{} `json:"metadata"`
 	}
 
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
@@ -22,22 +24,60 @@ func (h *credhubHandler) putDataHandler(c *gin.Context) {
 		return
 	}
 
-	if requestBody.Type != "value" {
+	hasStringValue, supported := credentialTypes[requestBody.Type]
+	if !supported {
 		c.JSON(400, gin.H{
 			"error": ErrInvalidType,
 		})
 		return
 	}
 
+	if !validValue(requestBody.Value, hasStringValue) {
+		c.JSON(400, gin.H{
+			"error": ErrInvalidPathOrBody,
+		})
+		return
+	}
+
+	if !h.authorized(c, requestBody.Name, permissions.OperationWrite) {
+		c.JSON(403, gin.H{
+			"error": ErrCredentialDoesNotExist,
+		})
+		return
+	}
+
 	createdCred := credentials.Credential{
 		ID:               uuid.New(),
 		VersionCreatedAt: time.Now().UTC(),
 		Name:             requestBody.Name,
 		Value:            requestBody.Value,
 		Type:             requestBody.Type,
+		Metadata:         requestBody.Metadata,
 	}
 
 	h.credentialStore.Set(createdCred)
 
 	c.JSON(200, createdCred)
 }
+
+// credentialTypes maps each supported credential type to whether its value is
+// a plain string rather than a JSON object.
+var credentialTypes = map[string]bool{
+	"value":       true,
+	"password":    true,
+	"json":        false,
+	"user":        false,
+	"certificate": false,
+	"rsa":         false,
+	"ssh":         false,
+}
+
+func validValue(value interface{}, hasStringValue bool) bool {
+	if hasStringValue {
+		stringValue, ok := value.(string)
+		return ok && stringValue != ""
+	}
+
+	_, ok := value.(map[string]interface{})
+	return ok
+}
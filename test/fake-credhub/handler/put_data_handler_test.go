@@ -19,16 +19,19 @@ var _ = Describe("PutDataHandler", func() {
 	var (
 		credhubHandler      http.Handler
 		fakeCredentialStore *handlerfakes.FakeCredentialStore
+		fakePermissionStore *handlerfakes.FakePermissionStore
 		fakeTokenValidator  *handlerfakes.FakeTokenValidator
 		authServerURL       = "some-auth-server-url"
 	)
 
 	BeforeEach(func() {
 		fakeCredentialStore = &handlerfakes.FakeCredentialStore{}
+		fakePermissionStore = &handlerfakes.FakePermissionStore{}
+		fakePermissionStore.AllowedReturns(true)
 		fakeTokenValidator = &handlerfakes.FakeTokenValidator{}
 
 		var err error
-		credhubHandler, err = handler.NewCredhubHandler(authServerURL, fakeCredentialStore, fakeTokenValidator)
+		credhubHandler, err = handler.NewCredhubHandler(authServerURL, fakeCredentialStore, fakePermissionStore, fakeTokenValidator)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
@@ -69,16 +72,73 @@ var _ = Describe("PutDataHandler", func() {
 		})
 	})
 
-	Context("when the type is not 'value'", func() {
+	It("stores metadata", func() {
+		responseRecorder := httptest.NewRecorder()
+		body := strings.NewReader(`{
+			"name": "some-name",
+			"type": "value",
+			"value": "some-value",
+			"metadata": {"owner": "some-team"}
+		}`)
+		request, err := http.NewRequest("PUT", "/api/v1/data", body)
+		Expect(err).NotTo(HaveOccurred())
+		request.Header.Add("Authorization", "Bearer some-token")
+
+		credhubHandler.ServeHTTP(responseRecorder, request)
+
+		Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+		cred := fakeCredentialStore.SetArgsForCall(0)
+		Expect(cred.Metadata).To(Equal(map[string]interface{}{"owner": "some-team"}))
+	})
+
+	It("sets structured credentials in the store", func() {
+		responseRecorder := httptest.NewRecorder()
+		body := strings.NewReader(`{
+			"name": "some-name",
+			"type": "user",
+			"value": {"username": "some-username", "password": "some-password"}
+		}`)
+		request, err := http.NewRequest("PUT", "/api/v1/data", body)
+		Expect(err).NotTo(HaveOccurred())
+		request.Header.Add("Authorization", "Bearer some-token")
+
+		credhubHandler.ServeHTTP(responseRecorder, request)
+
+		Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+		cred := fakeCredentialStore.SetArgsForCall(0)
+		Expect(cred.Type).To(Equal("user"))
+		Expect(cred.Value).To(Equal(map[string]interface{}{
+			"username": "some-username",
+			"password": "some-password",
+		}))
+	})
+
+	Context("when the type is not supported", func() {
+		It("responds with a 400", func() {
+			responseRecorder := httptest.NewRecorder()
+			request := setRequest("some-name", "some-unsupported-type", "some-value", "some-token")
+
+			credhubHandler.ServeHTTP(responseRecorder, request)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(readBody(responseRecorder)).To(MatchJSON(`{
+				"error": "The request does not include a valid type. Valid values include 'value', 'json', 'password', 'user', 'certificate', 'ssh' and 'rsa'."
+			}`))
+		})
+	})
+
+	Context("when the value does not match the type", func() {
 		It("responds with a 400", func() {
 			responseRecorder := httptest.NewRecorder()
-			request := setRequest("some-name", "some-non-value-type", "some-value", "some-token")
+			request := setRequest("some-name", "certificate", "some-string-value", "some-token")
 
 			credhubHandler.ServeHTTP(responseRecorder, request)
 
 			Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
 			Expect(readBody(responseRecorder)).To(MatchJSON(`{
-				"error": "Only 'value' types are supported"
+				"error": "The request could not be fulfilled because the request path or body did not meet expectation. Please check the documentation for required formatting and retry your request."
 			}`))
 		})
 	})
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/credentials"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/permissions"
+)
+
+func (h *credhubHandler) regenerateHandler(c *gin.Context) {
+	var requestBody struct {
+		Name string `json:"name"`
+	}
+
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(400, gin.H{
+			"error": ErrInvalidPathOrBody,
+		})
+		return
+	}
+
+	if requestBody.Name == "" {
+		c.JSON(400, gin.H{
+			"error": ErrMissingNameParameter,
+		})
+		return
+	}
+
+	cred, found := h.credentialStore.GetByName(requestBody.Name)
+	if !found {
+		c.JSON(404, gin.H{
+			"error": ErrCredentialDoesNotExist,
+		})
+		return
+	}
+
+	if !h.authorized(c, cred.Name, permissions.OperationWrite) {
+		c.JSON(403, gin.H{
+			"error": ErrCredentialDoesNotExist,
+		})
+		return
+	}
+
+	regeneratedCred, err := h.regenerate(cred)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(200, regeneratedCred)
+}
+
+func (h *credhubHandler) bulkRegenerateHandler(c *gin.Context) {
+	var requestBody struct {
+		SignedBy string `json:"signed_by"`
+	}
+
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(400, gin.H{
+			"error": ErrInvalidPathOrBody,
+		})
+		return
+	}
+
+	if requestBody.SignedBy == "" {
+		c.JSON(400, gin.H{
+			"error": ErrMissingSignedByParameter,
+		})
+		return
+	}
+
+	_, found := h.credentialStore.GetByName(requestBody.SignedBy)
+	if !found || !h.authorized(c, requestBody.SignedBy, permissions.OperationRead) {
+		c.JSON(404, gin.H{
+			"error": ErrCredentialDoesNotExist,
+		})
+		return
+	}
+
+	regeneratedNames := []string{}
+	if err := h.regenerateSignedBy(c, requestBody.SignedBy, &regeneratedNames); err == errPermissionDenied {
+		c.JSON(403, gin.H{
+			"error": ErrCredentialDoesNotExist,
+		})
+		return
+	} else if err != nil {
+		c.JSON(400, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	sort.Strings(regeneratedNames)
+
+	c.JSON(200, gin.H{
+		"regenerated_credentials": regeneratedNames,
+	})
+}
+
+// regenerate stores a new version of a generated credential, created from the
+// parameters of its current version.
+func (h *credhubHandler) regenerate(cred credentials.Credential) (credentials.Credential, error) {
+	if cred.GenerationParameters == nil {
+		return credentials.Credential{}, errors.New(ErrCannotRegenerateStatic)
+	}
+
+	value, err := h.generate(cred.Type, cred.GenerationParameters)
+	if err != nil {
+		return credentials.Credential{}, err
+	}
+
+	regeneratedCred := credentials.Credential{
+		ID:                   uuid.New(),
+		VersionCreatedAt:     time.Now().UTC(),
+		Name:                 cred.Name,
+		Value:                value,
+		Type:                 cred.Type,
+		Metadata:             cred.Metadata,
+		GenerationParameters: cred.GenerationParameters,
+	}
+
+	h.credentialStore.Set(regeneratedCred)
+
+	return regeneratedCred, nil
+}
+
+var errPermissionDenied = errors.New("permission denied")
+
+// regenerateSignedBy regenerates every generated certificate signed by the
+// named CA, then recurses so that certificates signed by a regenerated
+// intermediate CA are signed again by its new version. It stops with
+// errPermissionDenied at the first certificate the actor may not write.
+func (h *credhubHandler) regenerateSignedBy(c *gin.Context, caName string, regeneratedNames *[]string) error {
+	for _, cred := range h.credentialStore.GetAll() {
+		if cred.Type != "certificate" || cred.GenerationParameters == nil {
+			continue
+		}
+		if value, _ := cred.Value.(map[string]interface{}); value["ca_name"] != caName {
+			continue
+		}
+
+		if !h.authorized(c, cred.Name, permissions.OperationWrite) {
+			return errPermissionDenied
+		}
+
+		if _, err := h.regenerate(cred); err != nil {
+			return err
+		}
+		*regeneratedNames = append(*regeneratedNames, cred.Name)
+
+		if err := h.regenerateSignedBy(c, cred.Name, regeneratedNames); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package handler_test
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/credentials"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/handler"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/handler/handlerfakes"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/permissions"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RegenerateHandler", func() {
+	var (
+		credhubHandler      http.Handler
+		fakeCredentialStore *handlerfakes.FakeCredentialStore
+		fakePermissionStore *handlerfakes.FakePermissionStore
+		fakeTokenValidator  *handlerfakes.FakeTokenValidator
+		authServerURL       = "some-auth-server-url"
+	)
+
+	BeforeEach(func() {
+		fakeCredentialStore = &handlerfakes.FakeCredentialStore{}
+		fakePermissionStore = &handlerfakes.FakePermissionStore{}
+		fakePermissionStore.AllowedReturns(true)
+		fakeTokenValidator = &handlerfakes.FakeTokenValidator{}
+
+		var err error
+		credhubHandler, err = handler.NewCredhubHandler(authServerURL, fakeCredentialStore, fakePermissionStore, fakeTokenValidator)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("stores a new version generated with the original parameters", func() {
+		originalCred := credentials.Credential{
+			ID:                   uuid.New(),
+			Name:                 "some-name",
+			Type:                 "password",
+			Value:                "some-old-password",
+			Metadata:             map[string]interface{}{"owner": "some-team"},
+			GenerationParameters: json.RawMessage(`{"length": 10, "exclude_number": true}`),
+		}
+		fakeCredentialStore.GetByNameReturns(originalCred, true)
+
+		responseRecorder := httptest.NewRecorder()
+		credhubHandler.ServeHTTP(responseRecorder, regenerateRequest(`{"name": "some-name"}`))
+
+		Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+		Expect(fakeCredentialStore.GetByNameArgsForCall(0)).To(Equal("some-name"))
+
+		cred := fakeCredentialStore.SetArgsForCall(0)
+		Expect(cred.Name).To(Equal("some-name"))
+		Expect(cred.Type).To(Equal("password"))
+		Expect(cred.Value).To(MatchRegexp(`^[A-Za-z]{10}$`))
+		Expect(cred.ID).NotTo(Equal(originalCred.ID))
+		Expect(cred.Metadata).To(Equal(originalCred.Metadata))
+		Expect(cred.GenerationParameters).To(Equal(originalCred.GenerationParameters))
+
+		var response credentials.Credential
+		Expect(json.Unmarshal(readBody(responseRecorder), &response)).To(Succeed())
+		Expect(response.Value).To(Equal(cred.Value))
+	})
+
+	Context("when the credential was not generated", func() {
+		It("responds with a 400", func() {
+			fakeCredentialStore.GetByNameReturns(credentials.Credential{Name: "some-name", Type: "value", Value: "some-value"}, true)
+
+			responseRecorder := httptest.NewRecorder()
+			credhubHandler.ServeHTTP(responseRecorder, regenerateRequest(`{"name": "some-name"}`))
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(readBody(responseRecorder)).To(MatchJSON(fmt.Sprintf(`{"error": %q}`, handler.ErrCannotRegenerateStatic)))
+			Expect(fakeCredentialStore.SetCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the credential does not exist", func() {
+		It("responds with a 404", func() {
+			responseRecorder := httptest.NewRecorder()
+			credhubHandler.ServeHTTP(responseRecorder, regenerateRequest(`{"name": "some-nonexistent-name"}`))
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+			Expect(readBody(responseRecorder)).To(MatchJSON(fmt.Sprintf(`{"error": %q}`, handler.ErrCredentialDoesNotExist)))
+		})
+	})
+
+	Context("when the name is not provided", func() {
+		It("responds with a 400", func() {
+			responseRecorder := httptest.NewRecorder()
+			credhubHandler.ServeHTTP(responseRecorder, regenerateRequest(`{}`))
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(readBody(responseRecorder)).To(MatchJSON(fmt.Sprintf(`{"error": %q}`, handler.ErrMissingNameParameter)))
+		})
+	})
+})
+
+var _ = Describe("BulkRegenerateHandler", func() {
+	var (
+		credhubHandler  http.Handler
+		credentialStore credentials.Store
+	)
+
+	BeforeEach(func() {
+		credentialStore = credentials.NewStore()
+
+		var err error
+		credhubHandler, err = handler.NewCredhubHandler("some-auth-server-url", credentialStore, permissions.NewStore(false, nil), &handlerfakes.FakeTokenValidator{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	generateCertificate := func(name, parameters string) {
+		responseRecorder := httptest.NewRecorder()
+		credhubHandler.ServeHTTP(responseRecorder, generateRequest(name, "certificate", parameters, "some-token"))
+		ExpectWithOffset(1, responseRecorder.Code).To(Equal(http.StatusOK), string(readBody(responseRecorder)))
+	}
+
+	parseCertificate := func(name string) *x509.Certificate {
+		cred, found := credentialStore.GetByName(name)
+		ExpectWithOffset(1, found).To(BeTrue())
+		block, _ := pem.Decode([]byte(cred.Value.(map[string]interface{})["certificate"].(string)))
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return certificate
+	}
+
+	It("regenerates every certificate signed by the CA, including those under intermediate CAs", func() {
+		generateCertificate("/root-ca", `{"common_name": "root", "is_ca": true}`)
+		generateCertificate("/intermediate-ca", `{"common_name": "intermediate", "is_ca": true, "ca": "/root-ca"}`)
+		generateCertificate("/leaf", `{"common_name": "leaf", "ca": "/root-ca"}`)
+		generateCertificate("/nested-leaf", `{"common_name": "nested-leaf", "ca": "/intermediate-ca"}`)
+		generateCertificate("/unrelated", `{"common_name": "unrelated", "self_sign": true}`)
+		credentialStore.Set(credentials.Credential{Name: "/static", Type: "certificate", Value: map[string]interface{}{"ca_name": "/root-ca"}})
+
+		originalIntermediateSerial := parseCertificate("/intermediate-ca").SerialNumber
+
+		responseRecorder := httptest.NewRecorder()
+		credhubHandler.ServeHTTP(responseRecorder, bulkRegenerateRequest(`{"signed_by": "/root-ca"}`))
+
+		Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+		Expect(readBody(responseRecorder)).To(MatchJSON(`{
+			"regenerated_credentials": ["/intermediate-ca", "/leaf", "/nested-leaf"]
+		}`))
+
+		Expect(credentialStore.GetVersionsByName("/root-ca")).To(HaveLen(1))
+		Expect(credentialStore.GetVersionsByName("/intermediate-ca")).To(HaveLen(2))
+		Expect(credentialStore.GetVersionsByName("/leaf")).To(HaveLen(2))
+		Expect(credentialStore.GetVersionsByName("/nested-leaf")).To(HaveLen(2))
+		Expect(credentialStore.GetVersionsByName("/unrelated")).To(HaveLen(1))
+
+		intermediate := parseCertificate("/intermediate-ca")
+		Expect(intermediate.SerialNumber).NotTo(Equal(originalIntermediateSerial))
+		Expect(parseCertificate("/nested-leaf").CheckSignatureFrom(intermediate)).To(Succeed())
+	})
+
+	It("responds with an empty list when nothing is signed by the CA", func() {
+		generateCertificate("/root-ca", `{"common_name": "root", "is_ca": true}`)
+
+		responseRecorder := httptest.NewRecorder()
+		credhubHandler.ServeHTTP(responseRecorder, bulkRegenerateRequest(`{"signed_by": "/root-ca"}`))
+
+		Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+		Expect(readBody(responseRecorder)).To(MatchJSON(`{"regenerated_credentials": []}`))
+	})
+
+	Context("when the CA does not exist", func() {
+		It("responds with a 404", func() {
+			responseRecorder := httptest.NewRecorder()
+			credhubHandler.ServeHTTP(responseRecorder, bulkRegenerateRequest(`{"signed_by": "/some-nonexistent-ca"}`))
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+			Expect(readBody(responseRecorder)).To(MatchJSON(fmt.Sprintf(`{"error": %q}`, handler.ErrCredentialDoesNotExist)))
+		})
+	})
+
+	Context("when signed_by is not provided", func() {
+		It("responds with a 400", func() {
+			responseRecorder := httptest.NewRecorder()
+			credhubHandler.ServeHTTP(responseRecorder, bulkRegenerateRequest(`{}`))
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(readBody(responseRecorder)).To(MatchJSON(fmt.Sprintf(`{"error": %q}`, handler.ErrMissingSignedByParameter)))
+		})
+	})
+})
+
+func regenerateRequest(body string) *http.Request {
+	request, err := http.NewRequest("POST", "/api/v1/regenerate", strings.NewReader(body))
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	request.Header.Add("Authorization", "Bearer some-token")
+	return request
+}
+
+func bulkRegenerateRequest(body string) *http.Request {
+	request, err := http.NewRequest("POST", "/api/v1/bulk-regenerate", strings.NewReader(body))
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	request.Header.Add("Authorization", "Bearer some-token")
+	return request
+}
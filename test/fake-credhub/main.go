@@ -5,10 +5,12 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/mdelillo/credhub-fs/test/fake-credhub/credentials"
 	"github.com/mdelillo/credhub-fs/test/fake-credhub/handler"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/permissions"
 	"github.com/mdelillo/credhub-fs/test/fake-credhub/token"
 	"github.com/mdelillo/credhub-fs/test/server"
 )
@@ -17,31 +19,55 @@ var (
 	listenAddr         string
 	certPath           string
 	keyPath            string
+	clientCAPath       string
 	authServerAddr     string
 	jwtVerificationKey string
+	enforcePermissions bool
+	adminClients       stringList
 )
 
+// stringList collects the values of a flag that may be given more than once.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
 func main() {
 	flag.StringVar(&listenAddr, "listen-addr", "127.0.0.1:58844", "address to listen on")
 	flag.StringVar(&certPath, "cert-path", "1270.0.1:58844", "path to TLS certificate")
 	flag.StringVar(&keyPath, "key-path", "1270.0.1:58844", "path to TLS private key")
+	flag.StringVar(&clientCAPath, "client-ca-path", "", "path to a CA used to verify client certificates. If not set, only UAA tokens are accepted")
 	flag.StringVar(&authServerAddr, "auth-server-addr", "127.0.0.1:58443", "address of auth server")
 	flag.StringVar(&jwtVerificationKey, "jwt-verification-key", "", "key used to verify JWT auth tokens")
+	flag.BoolVar(&enforcePermissions, "enforce-permissions", false, "only allow admin clients and clients with a matching permission")
+	flag.Var(&adminClients, "admin-client", "client ID that is allowed every operation when enforcing permissions. Can be specified multiple times.")
 	flag.Parse()
 
 	credentialStore := credentials.NewStore()
 
+	var adminActors []string
+	for _, clientID := range adminClients {
+		adminActors = append(adminActors, "uaa-client:"+clientID)
+	}
+	permissionStore := permissions.NewStore(enforcePermissions, adminActors)
+
 	tokenValidator, err := token.NewValidator(jwtVerificationKey)
 	if err != nil {
 		log.Fatalf("Failed to create token validator: %s\n", err.Error())
 	}
 
-	credhubHandler, err := handler.NewCredhubHandler(authServerAddr, credentialStore, tokenValidator)
+	credhubHandler, err := handler.NewCredhubHandler(authServerAddr, credentialStore, permissionStore, tokenValidator)
 	if err != nil {
 		log.Fatalf("Failed to create handler: %s\n", err.Error())
 	}
 
-	s := server.NewServer(listenAddr, certPath, keyPath, credhubHandler)
+	s := server.NewServerWithClientCA(listenAddr, certPath, keyPath, clientCAPath, credhubHandler)
 
 	go func() {
 		if err := s.Start(); err != nil {
@@ -49,7 +75,7 @@ func main() {
 		}
 	}()
 
-	done := make(chan os.Signal)
+	done := make(chan os.Signal, 1)
 	signal.Notify(done, syscall.SIGINT, syscall.SIGTERM)
 	<-done
 
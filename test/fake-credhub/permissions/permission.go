@@ -0,0 +1,28 @@
+package permissions
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	OperationRead     = "read"
+	OperationWrite    = "write"
+	OperationDelete   = "delete"
+	OperationReadACL  = "read_acl"
+	OperationWriteACL = "write_acl"
+)
+
+// Operations lists every operation that a permission can grant.
+var Operations = []string{OperationRead, OperationWrite, OperationDelete, OperationReadACL, OperationWriteACL}
+
+type Permission struct {
+	UUID       uuid.UUID `json:"uuid"`
+	Actor      string    `json:"actor"`
+	Path       string    `json:"path"`
+	Operations []string  `json:"operations"`
+}
+
+type ActorPermission struct {
+	Actor      string   `json:"actor"`
+	Operations []string `json:"operations"`
+}
@@ -0,0 +1,13 @@
+package permissions_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestPermissions(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Permissions Suite")
+}
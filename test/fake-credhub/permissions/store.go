@@ -0,0 +1,101 @@
+package permissions
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type store struct {
+	permissions map[uuid.UUID]Permission
+	enforce     bool
+	adminActors []string
+}
+
+type Store interface {
+	GetByID(id uuid.UUID) (permission Permission, found bool)
+	GetByPathAndActor(path, actor string) (permission Permission, found bool)
+	GetByPath(path string) []Permission
+	Set(permission Permission)
+	Delete(id uuid.UUID) bool
+	Allowed(actor, name, operation string) bool
+}
+
+// NewStore returns an empty permission store. When enforce is false every
+// actor is allowed every operation; otherwise only the admin actors and
+// actors with a matching permission are.
+func NewStore(enforce bool, adminActors []string) Store {
+	return &store{
+		permissions: map[uuid.UUID]Permission{},
+		enforce:     enforce,
+		adminActors: adminActors,
+	}
+}
+
+func (s *store) GetByID(id uuid.UUID) (Permission, bool) {
+	permission, found := s.permissions[id]
+	return permission, found
+}
+
+func (s *store) GetByPathAndActor(path, actor string) (Permission, bool) {
+	for _, permission := range s.permissions {
+		if permission.Path == path && permission.Actor == actor {
+			return permission, true
+		}
+	}
+	return Permission{}, false
+}
+
+// GetByPath returns the permissions whose path is exactly the given path.
+func (s *store) GetByPath(path string) []Permission {
+	var matchingPermissions []Permission
+	for _, permission := range s.permissions {
+		if permission.Path == path {
+			matchingPermissions = append(matchingPermissions, permission)
+		}
+	}
+	return matchingPermissions
+}
+
+func (s *store) Set(permission Permission) {
+	s.permissions[permission.UUID] = permission
+}
+
+func (s *store) Delete(id uuid.UUID) bool {
+	_, exists := s.permissions[id]
+	delete(s.permissions, id)
+	return exists
+}
+
+// Allowed reports whether the actor may perform the operation on the named
+// credential. A permission path ending in '*' matches every name that starts
+// with the rest of the path.
+func (s *store) Allowed(actor, name, operation string) bool {
+	if !s.enforce {
+		return true
+	}
+	for _, adminActor := range s.adminActors {
+		if actor == adminActor {
+			return true
+		}
+	}
+
+	for _, permission := range s.permissions {
+		if permission.Actor != actor || !pathMatches(permission.Path, name) {
+			continue
+		}
+		for _, permittedOperation := range permission.Operations {
+			if permittedOperation == operation {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func pathMatches(permissionPath, name string) bool {
+	if strings.HasSuffix(permissionPath, "*") {
+		return strings.HasPrefix(name, strings.TrimSuffix(permissionPath, "*"))
+	}
+	return permissionPath == name
+}
@@ -0,0 +1,86 @@
+package permissions_test
+
+import (
+	"github.com/google/uuid"
+	"github.com/mdelillo/credhub-fs/test/fake-credhub/permissions"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Store", func() {
+	It("sets, gets, and deletes permissions", func() {
+		permission := permissions.Permission{
+			UUID:       uuid.New(),
+			Actor:      "some-actor",
+			Path:       "/some/path",
+			Operations: []string{"read"},
+		}
+		otherPermission := permissions.Permission{
+			UUID:       uuid.New(),
+			Actor:      "some-other-actor",
+			Path:       "/some/path",
+			Operations: []string{"write"},
+		}
+
+		store := permissions.NewStore(true, nil)
+		store.Set(permission)
+		store.Set(otherPermission)
+
+		actualPermission, found := store.GetByID(permission.UUID)
+		Expect(found).To(BeTrue())
+		Expect(actualPermission).To(Equal(permission))
+
+		actualPermission, found = store.GetByPathAndActor("/some/path", "some-other-actor")
+		Expect(found).To(BeTrue())
+		Expect(actualPermission).To(Equal(otherPermission))
+
+		Expect(store.GetByPath("/some/path")).To(ConsistOf(permission, otherPermission))
+		Expect(store.GetByPath("/some")).To(BeEmpty())
+
+		Expect(store.Delete(permission.UUID)).To(BeTrue())
+		_, found = store.GetByID(permission.UUID)
+		Expect(found).To(BeFalse())
+		Expect(store.Delete(permission.UUID)).To(BeFalse())
+
+		_, found = store.GetByPathAndActor("/some/path", "some-actor")
+		Expect(found).To(BeFalse())
+	})
+
+	Describe("Allowed", func() {
+		var store permissions.Store
+
+		BeforeEach(func() {
+			store = permissions.NewStore(true, []string{"some-admin"})
+			store.Set(permissions.Permission{UUID: uuid.New(), Actor: "some-actor", Path: "/exact", Operations: []string{"read", "write"}})
+			store.Set(permissions.Permission{UUID: uuid.New(), Actor: "some-actor", Path: "/dir/*", Operations: []string{"read"}})
+		})
+
+		It("allows operations granted on the exact path", func() {
+			Expect(store.Allowed("some-actor", "/exact", "write")).To(BeTrue())
+			Expect(store.Allowed("some-actor", "/exact", "delete")).To(BeFalse())
+			Expect(store.Allowed("some-actor", "/exact/nested", "read")).To(BeFalse())
+		})
+
+		It("allows operations granted on a wildcard path", func() {
+			Expect(store.Allowed("some-actor", "/dir/cred", "read")).To(BeTrue())
+			Expect(store.Allowed("some-actor", "/dir/nested/cred", "read")).To(BeTrue())
+			Expect(store.Allowed("some-actor", "/dir/cred", "write")).To(BeFalse())
+			Expect(store.Allowed("some-actor", "/directory", "read")).To(BeFalse())
+		})
+
+		It("does not allow other actors", func() {
+			Expect(store.Allowed("some-other-actor", "/exact", "read")).To(BeFalse())
+		})
+
+		It("allows admin actors everything", func() {
+			Expect(store.Allowed("some-admin", "/anything", "write_acl")).To(BeTrue())
+		})
+
+		Context("when permissions are not enforced", func() {
+			It("allows everything", func() {
+				store = permissions.NewStore(false, nil)
+				Expect(store.Allowed("some-actor", "/anything", "delete")).To(BeTrue())
+			})
+		})
+	})
+})
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
@@ -15,9 +16,13 @@ type uaaHandler struct {
 	listenAddr    string
 	jwtSigningKey *rsa.PrivateKey
 	clients       map[string]string
+	users         map[string]string
+
+	refreshTokensMutex sync.Mutex
+	refreshTokens      map[string]string
 }
 
-func NewUAAHandler(listenAddr, jwtSigningKey string, clients []string) (http.Handler, error) {
+func NewUAAHandler(listenAddr, jwtSigningKey string, clients, users []string) (http.Handler, error) {
 	clientMap := make(map[string]string)
 	for _, client := range clients {
 		if strings.Count(client, ":") == 0 {
@@ -28,6 +33,16 @@ func NewUAAHandler(listenAddr, jwtSigningKey string, clients []string) (http.Han
 		clientMap[clientID] = clientSecret
 	}
 
+	userMap := make(map[string]string)
+	for _, user := range users {
+		if strings.Count(user, ":") == 0 {
+			return nil, errors.New("'users' must contain colon-separated usernames and passwords")
+		}
+		username := strings.SplitN(user, ":", 2)[0]
+		password := strings.SplitN(user, ":", 2)[1]
+		userMap[username] = password
+	}
+
 	signingKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(jwtSigningKey))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse JWT signing key: %s", err.Error())
@@ -37,6 +52,8 @@ func NewUAAHandler(listenAddr, jwtSigningKey string, clients []string) (http.Han
 		listenAddr:    listenAddr,
 		jwtSigningKey: signingKey,
 		clients:       clientMap,
+		users:         userMap,
+		refreshTokens: make(map[string]string),
 	}
 
 	gin.SetMode(gin.ReleaseMode)
@@ -20,7 +20,7 @@ var _ = Describe("Handler", func() {
 		Expect(err).NotTo(HaveOccurred())
 		jwtSigningKey := helpers.PrivateKeyToPEM(rsaKey)
 
-		uaaHandler, err := handler.NewUAAHandler(listenAddr, jwtSigningKey, clients)
+		uaaHandler, err := handler.NewUAAHandler(listenAddr, jwtSigningKey, clients, nil)
 		Expect(err).NotTo(HaveOccurred())
 
 		routes := uaaHandler.(*gin.Engine).Routes()
@@ -38,18 +38,29 @@ var _ = Describe("Handler", func() {
 			Expect(err).NotTo(HaveOccurred())
 			jwtSigningKey := helpers.PrivateKeyToPEM(rsaKey)
 
-			_, err = handler.NewUAAHandler(listenAddr, jwtSigningKey, invalidClients)
+			_, err = handler.NewUAAHandler(listenAddr, jwtSigningKey, invalidClients, nil)
 			Expect(err).To(MatchError("'clients' must contain colon-separated client IDs and secrets"))
 		})
 	})
 
+	Context("when the users list is not formatted properly", func() {
+		It("returns an error", func() {
+			rsaKey, err := rsa.GenerateKey(rand.Reader, 4096)
+			Expect(err).NotTo(HaveOccurred())
+			jwtSigningKey := helpers.PrivateKeyToPEM(rsaKey)
+
+			_, err = handler.NewUAAHandler("some-listen-addr", jwtSigningKey, nil, []string{"invalid"})
+			Expect(err).To(MatchError("'users' must contain colon-separated usernames and passwords"))
+		})
+	})
+
 	Context("when the JWT signing key is invalid", func() {
 		It("returns an error", func() {
 			listenAddr := "some-listen-addr"
 			clients := []string{"some-client-id:some-secret"}
 			invalidJWTSigningKey := "some-invalid-jwt-signing-key"
 
-			_, err := handler.NewUAAHandler(listenAddr, invalidJWTSigningKey, clients)
+			_, err := handler.NewUAAHandler(listenAddr, invalidJWTSigningKey, clients, nil)
 			Expect(err).To(MatchError(ContainSubstring("failed to parse JWT signing key: ")))
 		})
 	})
@@ -3,20 +3,45 @@ package handler
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
+// tokenValidity matches the default access token validity of a real UAA.
+const tokenValidity = 12 * time.Hour
+
+// userClientID is the public client which a UAA deployed for CredHub provides
+// for users to log in with. Like a real UAA, it has no secret and may only be
+// used with the password and refresh_token grants.
+const userClientID = "credhub_cli"
+
 func (h *uaaHandler) tokenHandler(c *gin.Context) {
 	grantType := c.PostForm("grant_type")
 	clientID := c.PostForm("client_id")
 	clientSecret := c.PostForm("client_secret")
 
-	if grantType != "client_credentials" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Grant type must be 'client_credentials'"})
-		return
+	switch grantType {
+	case "client_credentials":
+		h.clientCredentialsGrant(c, clientID, clientSecret)
+	case "password":
+		if !h.authenticateUserClient(c, clientID, clientSecret) {
+			return
+		}
+		h.passwordGrant(c, clientID)
+	case "refresh_token":
+		if !h.authenticateUserClient(c, clientID, clientSecret) {
+			return
+		}
+		h.refreshTokenGrant(c, clientID)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Grant type must be one of 'client_credentials', 'password' or 'refresh_token'"})
 	}
+}
+
+func (h *uaaHandler) clientCredentialsGrant(c *gin.Context, clientID, clientSecret string) {
 	if clientID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "'client_id' must not be empty"})
 		return
@@ -31,12 +56,80 @@ func (h *uaaHandler) tokenHandler(c *gin.Context) {
 		return
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+	h.respondWithToken(c, jwt.MapClaims{"client_id": clientID, "grant_type": "client_credentials"}, "")
+}
+
+// authenticateUserClient checks the client that a user is logging in through,
+// which is either the public user client or a client with a matching secret.
+func (h *uaaHandler) authenticateUserClient(c *gin.Context, clientID, clientSecret string) bool {
+	if clientID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "'client_id' must not be empty"})
+		return false
+	}
+
+	if (clientID == userClientID && clientSecret == "") || (clientSecret != "" && h.clients[clientID] == clientSecret) {
+		return true
+	}
+
+	c.JSON(http.StatusUnauthorized, gin.H{"error": "incorrect 'client_id' and/or 'client_secret'"})
+	return false
+}
+
+func (h *uaaHandler) passwordGrant(c *gin.Context, clientID string) {
+	username := c.PostForm("username")
+	password := c.PostForm("password")
+
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "'username' must not be empty"})
+		return
+	}
+	if expected, found := h.users[username]; !found || expected != password {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "incorrect 'username' and/or 'password'"})
+		return
+	}
+
+	refreshToken := uuid.New().String()
+	h.refreshTokensMutex.Lock()
+	h.refreshTokens[refreshToken] = username
+	h.refreshTokensMutex.Unlock()
+
+	h.respondWithToken(c, userClaims(clientID, username, "password"), refreshToken)
+}
+
+func (h *uaaHandler) refreshTokenGrant(c *gin.Context, clientID string) {
+	refreshToken := c.PostForm("refresh_token")
+
+	h.refreshTokensMutex.Lock()
+	username, found := h.refreshTokens[refreshToken]
+	h.refreshTokensMutex.Unlock()
+
+	if !found {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid 'refresh_token'"})
+		return
+	}
+
+	h.respondWithToken(c, userClaims(clientID, username, "refresh_token"), refreshToken)
+}
+
+// userClaims identifies the user a token is issued to. The fake uses usernames
+// as user IDs, so CredHub sees the user as the actor 'uaa-user:USERNAME'.
+func userClaims(clientID, username, grantType string) jwt.MapClaims {
+	return jwt.MapClaims{
 		"client_id":  clientID,
-		"grant_type": "client_credentials",
-		"iss":        fmt.Sprintf("https://%s%s", h.listenAddr, c.Request.URL.Path),
-		"scope":      []string{"credhub.read", "credhub.write"},
-	})
+		"user_id":    username,
+		"user_name":  username,
+		"grant_type": grantType,
+	}
+}
+
+func (h *uaaHandler) respondWithToken(c *gin.Context, claims jwt.MapClaims, refreshToken string) {
+	issuedAt := time.Now()
+	claims["exp"] = issuedAt.Add(tokenValidity).Unix()
+	claims["iat"] = issuedAt.Unix()
+	claims["iss"] = fmt.Sprintf("https://%s%s", h.listenAddr, c.Request.URL.Path)
+	claims["scope"] = []string{"credhub.read", "credhub.write"}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
 	token.Header["kid"] = "legacy-token-key"
 	tokenString, err := token.SignedString(h.jwtSigningKey)
 	if err != nil {
@@ -44,5 +137,13 @@ func (h *uaaHandler) tokenHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"access_token": tokenString})
+	response := gin.H{
+		"access_token": tokenString,
+		"token_type":   "bearer",
+		"expires_in":   int(tokenValidity.Seconds()),
+	}
+	if refreshToken != "" {
+		response["refresh_token"] = refreshToken
+	}
+	c.JSON(http.StatusOK, response)
 }
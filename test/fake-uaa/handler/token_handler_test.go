@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/mdelillo/credhub-fs/test/fake-uaa/handler"
@@ -23,6 +24,7 @@ var _ = Describe("TokenHandler", func() {
 		clientID      = "some-client-id"
 		clientSecret  = "some-client-secret"
 		clients       = []string{clientID + ":" + clientSecret}
+		users         = []string{"some-user:some-password"}
 		rsaKey        *rsa.PrivateKey
 		jwtSigningKey string
 	)
@@ -38,7 +40,7 @@ var _ = Describe("TokenHandler", func() {
 		responseRecorder := httptest.NewRecorder()
 		request := generateRequest(clientID, clientSecret, "client_credentials")
 
-		uaaHandler, err := handler.NewUAAHandler(listenAddr, jwtSigningKey, clients)
+		uaaHandler, err := handler.NewUAAHandler(listenAddr, jwtSigningKey, clients, nil)
 		Expect(err).NotTo(HaveOccurred())
 		uaaHandler.ServeHTTP(responseRecorder, request)
 
@@ -54,19 +56,92 @@ var _ = Describe("TokenHandler", func() {
 		Expect(claims["grant_type"]).To(Equal("client_credentials"))
 		Expect(claims["iss"]).To(Equal(fmt.Sprintf("https://%s/oauth/token", listenAddr)))
 		Expect(claims["scope"]).To(ConsistOf("credhub.read", "credhub.write"))
+		Expect(claims["exp"]).To(BeNumerically("~", time.Now().Add(12*time.Hour).Unix(), 5))
 	})
 
-	Context("when the grant type is not 'client_credentials'", func() {
+	Describe("the password grant", func() {
+		var uaaHandler http.Handler
+
+		BeforeEach(func() {
+			var err error
+			uaaHandler, err = handler.NewUAAHandler(listenAddr, jwtSigningKey, clients, users)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("responds with a token for the user and a refresh token", func() {
+			responseRecorder := httptest.NewRecorder()
+			uaaHandler.ServeHTTP(responseRecorder, generatePasswordRequest("some-user", "some-password"))
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			var tokenResponse struct {
+				RefreshToken string `json:"refresh_token"`
+			}
+			Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &tokenResponse)).To(Succeed())
+			Expect(tokenResponse.RefreshToken).NotTo(BeEmpty())
+
+			token := getRSATokenFromResponse(responseRecorder, rsaKey)
+			Expect(token.Valid).To(BeTrue())
+			claims := token.Claims.(jwt.MapClaims)
+			Expect(claims["client_id"]).To(Equal("credhub_cli"))
+			Expect(claims["user_id"]).To(Equal("some-user"))
+			Expect(claims["user_name"]).To(Equal("some-user"))
+			Expect(claims["grant_type"]).To(Equal("password"))
+
+			By("exchanging the refresh token for another token for the user")
+			responseRecorder = httptest.NewRecorder()
+			uaaHandler.ServeHTTP(responseRecorder, generateRefreshTokenRequest(tokenResponse.RefreshToken))
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			token = getRSATokenFromResponse(responseRecorder, rsaKey)
+			claims = token.Claims.(jwt.MapClaims)
+			Expect(claims["user_id"]).To(Equal("some-user"))
+			Expect(claims["grant_type"]).To(Equal("refresh_token"))
+		})
+
+		Context("when the password is incorrect", func() {
+			It("responds with a 401", func() {
+				responseRecorder := httptest.NewRecorder()
+				uaaHandler.ServeHTTP(responseRecorder, generatePasswordRequest("some-user", "some-wrong-password"))
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(readBody(responseRecorder)).To(MatchJSON(`{"error": "incorrect 'username' and/or 'password'"}`))
+			})
+		})
+
+		Context("when the user logs in through an unknown client", func() {
+			It("responds with a 401", func() {
+				responseRecorder := httptest.NewRecorder()
+				request := generatePasswordRequest("some-user", "some-password")
+				request.PostForm.Set("client_id", "some-unknown-client")
+				uaaHandler.ServeHTTP(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(readBody(responseRecorder)).To(MatchJSON(`{"error": "incorrect 'client_id' and/or 'client_secret'"}`))
+			})
+		})
+
+		Context("when the refresh token was not issued by the handler", func() {
+			It("responds with a 401", func() {
+				responseRecorder := httptest.NewRecorder()
+				uaaHandler.ServeHTTP(responseRecorder, generateRefreshTokenRequest("some-unknown-refresh-token"))
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(readBody(responseRecorder)).To(MatchJSON(`{"error": "invalid 'refresh_token'"}`))
+			})
+		})
+	})
+
+	Context("when the grant type is not supported", func() {
 		It("responds with a 400", func() {
 			responseRecorder := httptest.NewRecorder()
 			request := generateRequest(clientID, clientSecret, "some-invalid-grant-type")
 
-			uaaHandler, err := handler.NewUAAHandler(listenAddr, jwtSigningKey, clients)
+			uaaHandler, err := handler.NewUAAHandler(listenAddr, jwtSigningKey, clients, nil)
 			Expect(err).NotTo(HaveOccurred())
 			uaaHandler.ServeHTTP(responseRecorder, request)
 
 			Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
-			Expect(readBody(responseRecorder)).To(MatchJSON(`{"error": "Grant type must be 'client_credentials'"}`))
+			Expect(readBody(responseRecorder)).To(MatchJSON(`{"error": "Grant type must be one of 'client_credentials', 'password' or 'refresh_token'"}`))
 		})
 	})
 
@@ -75,7 +150,7 @@ var _ = Describe("TokenHandler", func() {
 			responseRecorder := httptest.NewRecorder()
 			request := generateRequest("", clientSecret, "client_credentials")
 
-			uaaHandler, err := handler.NewUAAHandler(listenAddr, jwtSigningKey, clients)
+			uaaHandler, err := handler.NewUAAHandler(listenAddr, jwtSigningKey, clients, nil)
 			Expect(err).NotTo(HaveOccurred())
 			uaaHandler.ServeHTTP(responseRecorder, request)
 
@@ -89,7 +164,7 @@ var _ = Describe("TokenHandler", func() {
 			responseRecorder := httptest.NewRecorder()
 			request := generateRequest(clientID, "", "client_credentials")
 
-			uaaHandler, err := handler.NewUAAHandler(listenAddr, jwtSigningKey, clients)
+			uaaHandler, err := handler.NewUAAHandler(listenAddr, jwtSigningKey, clients, nil)
 			Expect(err).NotTo(HaveOccurred())
 			uaaHandler.ServeHTTP(responseRecorder, request)
 
@@ -103,7 +178,7 @@ var _ = Describe("TokenHandler", func() {
 			responseRecorder := httptest.NewRecorder()
 			request := generateRequest("some-invalid-client-id", clientSecret, "client_credentials")
 
-			uaaHandler, err := handler.NewUAAHandler(listenAddr, jwtSigningKey, clients)
+			uaaHandler, err := handler.NewUAAHandler(listenAddr, jwtSigningKey, clients, nil)
 			Expect(err).NotTo(HaveOccurred())
 			uaaHandler.ServeHTTP(responseRecorder, request)
 
@@ -117,7 +192,7 @@ var _ = Describe("TokenHandler", func() {
 			responseRecorder := httptest.NewRecorder()
 			request := generateRequest(clientID, "some-invalid-client-secret", "client_credentials")
 
-			uaaHandler, err := handler.NewUAAHandler(listenAddr, jwtSigningKey, clients)
+			uaaHandler, err := handler.NewUAAHandler(listenAddr, jwtSigningKey, clients, nil)
 			Expect(err).NotTo(HaveOccurred())
 			uaaHandler.ServeHTTP(responseRecorder, request)
 
@@ -127,6 +202,32 @@ var _ = Describe("TokenHandler", func() {
 	})
 })
 
+func generatePasswordRequest(username, password string) *http.Request {
+	return generateFormRequest(url.Values{
+		"client_id":     {"credhub_cli"},
+		"client_secret": {""},
+		"grant_type":    {"password"},
+		"username":      {username},
+		"password":      {password},
+	})
+}
+
+func generateRefreshTokenRequest(refreshToken string) *http.Request {
+	return generateFormRequest(url.Values{
+		"client_id":     {"credhub_cli"},
+		"client_secret": {""},
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+}
+
+func generateFormRequest(form url.Values) *http.Request {
+	request, err := http.NewRequest("POST", "/oauth/token", nil)
+	ExpectWithOffset(2, err).NotTo(HaveOccurred())
+	request.PostForm = form
+	return request
+}
+
 func generateRequest(clientID, clientSecret, grantType string) *http.Request {
 	request, err := http.NewRequest("POST", "/oauth/token", nil)
 	request.PostForm = url.Values{
@@ -18,12 +18,13 @@ func main() {
 		KeyPath       string   `short:"k" long:"key-path" description:"path to TLS private key" required:"true"`
 		JWTSigningKey string   `short:"j" long:"jwt-signing-key" description:"RSA key used to sign JWT tokens" required:"true"`
 		Clients       []string `long:"client" description:"client ID and secret, colon-separated, to be allowed for authentication. Can be specified multiple times."`
+		Users         []string `long:"user" description:"username and password, colon-separated, to be allowed to log in with the password grant. Can be specified multiple times."`
 	}
 	if _, err := flags.Parse(&opts); err != nil {
 		os.Exit(1)
 	}
 
-	uaaHandler, err := handler.NewUAAHandler(opts.ListenAddr, opts.JWTSigningKey, opts.Clients)
+	uaaHandler, err := handler.NewUAAHandler(opts.ListenAddr, opts.JWTSigningKey, opts.Clients, opts.Users)
 	if err != nil {
 		log.Fatalf("Failed to create handler: %s\n", err.Error())
 	}
@@ -36,7 +37,7 @@ func main() {
 		}
 	}()
 
-	done := make(chan os.Signal)
+	done := make(chan os.Signal, 1)
 	signal.Notify(done, syscall.SIGINT, syscall.SIGTERM)
 	<-done
 
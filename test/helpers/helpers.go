@@ -1,14 +1,21 @@
 package helpers
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/pem"
 	"fmt"
+	"io/ioutil"
+	"math/big"
 	"math/rand"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -55,6 +62,20 @@ func WaitForServerToBeAvailable(address string, timeout time.Duration) error {
 	}
 }
 
+func IsMounted(mountpoint string) bool {
+	mounts, err := ioutil.ReadFile("/proc/mounts")
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(mounts), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 1 && fields[1] == mountpoint {
+			return true
+		}
+	}
+	return false
+}
+
 func RandomString() string {
 	b := make([]byte, randomStringLength)
 	for i := range b {
@@ -84,6 +105,24 @@ func PublicKeyToPEM(publicKey *rsa.PublicKey) string {
 	return string(publicKeyBytes)
 }
 
+// GenerateCertificatePEM returns a PEM-encoded, self-signed certificate for
+// commonName that expires at notAfter.
+func GenerateCertificatePEM(commonName string, notAfter time.Time) string {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	panicOnError(err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(rand.Int63()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	derBytes, err := x509.CreateCertificate(crand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	panicOnError(err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes}))
+}
+
 func panicOnError(err error) {
 	if err != nil {
 		panic(err)
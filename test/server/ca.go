@@ -0,0 +1,151 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"time"
+)
+
+// CA is a throwaway certificate authority which issues certificates to test
+// servers, so that clients can verify them rather than skipping validation.
+type CA struct {
+	CertificatePEM string
+	certificate    *x509.Certificate
+	privateKey     *ecdsa.PrivateKey
+}
+
+func NewCA() (*CA, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %s", err.Error())
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          randomSerialNumber(),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	derBytes, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %s", err.Error())
+	}
+
+	certificate, err := x509.ParseCertificate(derBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %s", err.Error())
+	}
+
+	return &CA{
+		CertificatePEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes})),
+		certificate:    certificate,
+		privateKey:     privateKey,
+	}, nil
+}
+
+// IssueCertificate returns a PEM-encoded certificate and private key for the
+// given hosts, which may be IP addresses or DNS names.
+func (ca *CA) IssueCertificate(hosts ...string) (string, string, error) {
+	return ca.issue(x509.ExtKeyUsageServerAuth, pkix.Name{}, hosts)
+}
+
+// IssueClientCertificate returns a PEM-encoded client certificate and private
+// key identifying a Cloud Foundry app, like the instance identity certificates
+// that Diego gives to app containers.
+func (ca *CA) IssueClientCertificate(appGUID string) (string, string, error) {
+	subject := pkix.Name{
+		CommonName:         appGUID,
+		OrganizationalUnit: []string{"app:" + appGUID},
+	}
+	return ca.issue(x509.ExtKeyUsageClientAuth, subject, nil)
+}
+
+// WriteCertificate issues a certificate for the given hosts and writes it and
+// its private key to cert.pem and key.pem in dir, returning their paths.
+func (ca *CA) WriteCertificate(dir string, hosts ...string) (string, string, error) {
+	certificatePEM, privateKeyPEM, err := ca.IssueCertificate(hosts...)
+	if err != nil {
+		return "", "", err
+	}
+	return write(dir, "", certificatePEM, privateKeyPEM)
+}
+
+// WriteClientCertificate issues a client certificate for the given app and
+// writes it and its private key to client-cert.pem and client-key.pem in dir,
+// returning their paths.
+func (ca *CA) WriteClientCertificate(dir, appGUID string) (string, string, error) {
+	certificatePEM, privateKeyPEM, err := ca.IssueClientCertificate(appGUID)
+	if err != nil {
+		return "", "", err
+	}
+	return write(dir, "client-", certificatePEM, privateKeyPEM)
+}
+
+func write(dir, prefix, certificatePEM, privateKeyPEM string) (string, string, error) {
+	certPath := filepath.Join(dir, prefix+"cert.pem")
+	keyPath := filepath.Join(dir, prefix+"key.pem")
+	if err := ioutil.WriteFile(certPath, []byte(certificatePEM), 0600); err != nil {
+		return "", "", fmt.Errorf("failed to write certificate: %s", err.Error())
+	}
+	if err := ioutil.WriteFile(keyPath, []byte(privateKeyPEM), 0600); err != nil {
+		return "", "", fmt.Errorf("failed to write private key: %s", err.Error())
+	}
+	return certPath, keyPath, nil
+}
+
+func (ca *CA) issue(usage x509.ExtKeyUsage, subject pkix.Name, hosts []string) (string, string, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate key: %s", err.Error())
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: randomSerialNumber(),
+		Subject:      subject,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, template, ca.certificate, &privateKey.PublicKey, ca.privateKey)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create certificate: %s", err.Error())
+	}
+
+	keyBytes, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal private key: %s", err.Error())
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes})),
+		nil
+}
+
+func randomSerialNumber() *big.Int {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		panic(err)
+	}
+	return serialNumber
+}
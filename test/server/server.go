@@ -2,6 +2,11 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"time"
 )
@@ -12,19 +17,29 @@ type Server interface {
 }
 
 type server struct {
-	listenAddr string
-	certPath   string
-	keyPath    string
-	handler    http.Handler
-	httpServer *http.Server
+	listenAddr   string
+	certPath     string
+	keyPath      string
+	clientCAPath string
+	handler      http.Handler
+	httpServer   *http.Server
 }
 
 func NewServer(listenAddr, certPath, keyPath string, handler http.Handler) Server {
+	return NewServerWithClientCA(listenAddr, certPath, keyPath, "", handler)
+}
+
+// NewServerWithClientCA returns a Server which also verifies any client
+// certificate presented to it against the CA at clientCAPath. Clients without a
+// certificate are still accepted, so handlers can fall back to other forms of
+// authentication; r.TLS.VerifiedChains is only populated for verified clients.
+func NewServerWithClientCA(listenAddr, certPath, keyPath, clientCAPath string, handler http.Handler) Server {
 	return &server{
-		listenAddr: listenAddr,
-		certPath:   certPath,
-		keyPath:    keyPath,
-		handler:    handler,
+		listenAddr:   listenAddr,
+		certPath:     certPath,
+		keyPath:      keyPath,
+		clientCAPath: clientCAPath,
+		handler:      handler,
 	}
 }
 
@@ -34,6 +49,17 @@ func (s *server) Start() error {
 		Handler: s.handler,
 	}
 
+	if s.clientCAPath != "" {
+		clientCAs, err := loadCertPool(s.clientCAPath)
+		if err != nil {
+			return err
+		}
+		s.httpServer.TLSConfig = &tls.Config{
+			ClientAuth: tls.VerifyClientCertIfGiven,
+			ClientCAs:  clientCAs,
+		}
+	}
+
 	if err := s.httpServer.ListenAndServeTLS(s.certPath, s.keyPath); err != nil && err != http.ErrServerClosed {
 		return err
 	}
@@ -53,3 +79,16 @@ func (s *server) Shutdown() error {
 	}
 	return nil
 }
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pemData, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA: %s", err.Error())
+	}
+
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(pemData) {
+		return nil, errors.New("failed to load client CA: no PEM certificates found")
+	}
+	return certPool, nil
+}
@@ -1,9 +1,12 @@
 package server_test
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"path/filepath"
 	"time"
 
@@ -48,6 +51,89 @@ var _ = Describe("Server", func() {
 
 		Expect(helpers.ServerIsAvailable(listenAddr)).To(BeFalse())
 	})
+
+	It("serves certificates issued by a throwaway CA which clients can verify", func() {
+		ca, err := server.NewCA()
+		Expect(err).NotTo(HaveOccurred())
+
+		tempDir, err := ioutil.TempDir("", "server")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tempDir)
+
+		certPath, keyPath, err := ca.WriteCertificate(tempDir, "127.0.0.1")
+		Expect(err).NotTo(HaveOccurred())
+
+		s := server.NewServer(listenAddr, certPath, keyPath, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		go s.Start()
+		defer s.Shutdown()
+		Expect(helpers.WaitForServerToBeAvailable(listenAddr, 5*time.Second)).To(Succeed())
+
+		verifyingClient := func(caPEM string) *http.Client {
+			certPool := x509.NewCertPool()
+			Expect(certPool.AppendCertsFromPEM([]byte(caPEM))).To(BeTrue())
+			return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: certPool}}}
+		}
+
+		resp, err := verifyingClient(ca.CertificatePEM).Get("https://" + listenAddr)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+
+		otherCA, err := server.NewCA()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = verifyingClient(otherCA.CertificatePEM).Get("https://" + listenAddr)
+		Expect(err).To(MatchError(ContainSubstring("certificate signed by unknown authority")))
+	})
+
+	It("verifies client certificates when given a client CA", func() {
+		ca, err := server.NewCA()
+		Expect(err).NotTo(HaveOccurred())
+
+		tempDir, err := ioutil.TempDir("", "server")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tempDir)
+
+		certPath, keyPath, err := ca.WriteCertificate(tempDir, "127.0.0.1")
+		Expect(err).NotTo(HaveOccurred())
+		clientCAPath := filepath.Join(tempDir, "client-ca.pem")
+		Expect(ioutil.WriteFile(clientCAPath, []byte(ca.CertificatePEM), 0600)).To(Succeed())
+
+		s := server.NewServerWithClientCA(listenAddr, certPath, keyPath, clientCAPath, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(r.TLS.VerifiedChains) > 0 {
+				fmt.Fprint(w, r.TLS.VerifiedChains[0][0].Subject.OrganizationalUnit[0])
+			}
+		}))
+		go s.Start()
+		defer s.Shutdown()
+		Expect(helpers.WaitForServerToBeAvailable(listenAddr, 5*time.Second)).To(Succeed())
+
+		clientWithCertificate := func(ca *server.CA) *http.Client {
+			certificatePEM, privateKeyPEM, err := ca.IssueClientCertificate("some-app-guid")
+			Expect(err).NotTo(HaveOccurred())
+			certificate, err := tls.X509KeyPair([]byte(certificatePEM), []byte(privateKeyPEM))
+			Expect(err).NotTo(HaveOccurred())
+			return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+				Certificates:       []tls.Certificate{certificate},
+			}}}
+		}
+
+		By("identifying clients with a certificate issued by the client CA")
+		resp, err := clientWithCertificate(ca).Get("https://" + listenAddr)
+		Expect(err).NotTo(HaveOccurred())
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(Equal("app:some-app-guid"))
+
+		By("accepting clients without a certificate")
+		Expect(get("https://" + listenAddr)).To(BeEmpty())
+
+		By("rejecting certificates issued by another CA")
+		otherCA, err := server.NewCA()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = clientWithCertificate(otherCA).Get("https://" + listenAddr)
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 func get(url string) string {